@@ -31,6 +31,34 @@ func (c *Client) Get(tags []string) ([]params.AnnotationsGetResult, error) {
 	return annotations.Results, nil
 }
 
+// GetWithPrefix returns the annotations set on the given entity whose
+// keys start with prefix. The filtering is done by the controller. An
+// empty map is returned if no annotations match.
+func (c *Client) GetWithPrefix(tag, prefix string) (map[string]string, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("getting annotations by prefix")
+	}
+	args := params.AnnotationsGetWithPrefix{
+		Entities: entitiesFromTags([]string{tag}).Entities,
+		Prefix:   prefix,
+	}
+	var results params.AnnotationsGetResults
+	if err := c.facade.FacadeCall("GetWithPrefix", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error.Error != nil {
+		return nil, result.Error.Error
+	}
+	if result.Annotations == nil {
+		return map[string]string{}, nil
+	}
+	return result.Annotations, nil
+}
+
 // Set sets entity annotation pairs.
 func (c *Client) Set(annotations map[string]map[string]string) ([]params.ErrorResult, error) {
 	args := params.AnnotationsSet{entitiesAnnotations(annotations)}
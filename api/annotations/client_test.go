@@ -4,6 +4,7 @@
 package annotations_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -91,3 +92,97 @@ func (s *annotationsMockSuite) TestGetEntitiesAnnotations(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 	c.Assert(found, gc.HasLen, 1)
 }
+
+func (s *annotationsMockSuite) TestGetWithPrefix(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(
+				objType string,
+				version int,
+				id, request string,
+				a, response interface{}) error {
+				called = true
+				c.Check(objType, gc.Equals, "Annotations")
+				c.Check(id, gc.Equals, "")
+				c.Check(request, gc.Equals, "GetWithPrefix")
+				c.Check(a, jc.DeepEquals, params.AnnotationsGetWithPrefix{
+					Entities: []params.Entity{{"application-foo"}},
+					Prefix:   "gui-",
+				})
+				result := response.(*params.AnnotationsGetResults)
+				result.Results = []params.AnnotationsGetResult{{
+					EntityTag:   "application-foo",
+					Annotations: map[string]string{"gui-x": "10"},
+				}}
+				return nil
+			}),
+		BestVersion: 3,
+	}
+	annotationsClient := annotations.NewClient(apiCaller)
+	found, err := annotationsClient.GetWithPrefix("application-foo", "gui-")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(found, jc.DeepEquals, map[string]string{"gui-x": "10"})
+}
+
+func (s *annotationsMockSuite) TestGetWithPrefixNoneMatching(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(
+				objType string,
+				version int,
+				id, request string,
+				a, response interface{}) error {
+				result := response.(*params.AnnotationsGetResults)
+				result.Results = []params.AnnotationsGetResult{{
+					EntityTag: "application-foo",
+				}}
+				return nil
+			}),
+		BestVersion: 3,
+	}
+	annotationsClient := annotations.NewClient(apiCaller)
+	found, err := annotationsClient.GetWithPrefix("application-foo", "gui-")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, gc.NotNil)
+	c.Assert(found, gc.HasLen, 0)
+}
+
+func (s *annotationsMockSuite) TestGetWithPrefixError(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(
+				objType string,
+				version int,
+				id, request string,
+				a, response interface{}) error {
+				result := response.(*params.AnnotationsGetResults)
+				result.Results = []params.AnnotationsGetResult{{
+					EntityTag: "application-foo",
+					Error: params.ErrorResult{
+						Error: &params.Error{Message: "permission denied"},
+					},
+				}}
+				return nil
+			}),
+		BestVersion: 3,
+	}
+	annotationsClient := annotations.NewClient(apiCaller)
+	_, err := annotationsClient.GetWithPrefix("application-foo", "gui-")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *annotationsMockSuite) TestGetWithPrefixNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(string, int, string, string, interface{}, interface{}) error {
+				c.Fatalf("unexpected API call")
+				return nil
+			}),
+		BestVersion: 2,
+	}
+	annotationsClient := annotations.NewClient(apiCaller)
+	_, err := annotationsClient.GetWithPrefix("application-foo", "gui-")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
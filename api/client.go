@@ -112,6 +112,19 @@ func (c *Client) Resolved(unit string, retry bool) error {
 	return c.facade.FacadeCall("Resolved", p, nil)
 }
 
+// ResolveUnitAndSubordinates clears errors on a unit and on any of its
+// subordinate units that are in error, returning the outcome for each
+// unit it tried to resolve.
+func (c *Client) ResolveUnitAndSubordinates(unit string, retryHooks bool) ([]params.UnitResolvedResult, error) {
+	p := params.ResolveUnitAndSubordinates{
+		UnitName:   unit,
+		RetryHooks: retryHooks,
+	}
+	var results params.UnitResolvedResults
+	err := c.facade.FacadeCall("ResolveUnitAndSubordinates", p, &results)
+	return results.Results, err
+}
+
 // RetryProvisioning updates the provisioning status of a machine allowing the
 // provisioner to retry.
 func (c *Client) RetryProvisioning(machines ...names.MachineTag) ([]params.ErrorResult, error) {
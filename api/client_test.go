@@ -26,9 +26,11 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/application"
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmstore"
 	jujunames "github.com/juju/juju/juju/names"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/rpc"
@@ -119,6 +121,31 @@ func (s *clientSuite) TestAddLocalCharm(c *gc.C) {
 	c.Assert(savedURL.String(), gc.Equals, curl.WithRevision(43).String())
 }
 
+func (s *clientSuite) TestAddLocalCharmAndDeploy(c *gc.C) {
+	charmArchive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	curl := charm.MustParseURL(
+		fmt.Sprintf("local:quantal/%s-%d", charmArchive.Meta().Name, charmArchive.Revision()),
+	)
+	savedURL, err := s.APIState.Client().AddLocalCharm(curl, charmArchive)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The uploaded charm can be deployed by its local: URL.
+	err = application.NewClient(s.APIState).Deploy(application.DeployArgs{
+		CharmID:         charmstore.CharmID{URL: savedURL},
+		ApplicationName: "dummy",
+		NumUnits:        1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	app, err := s.State.Application("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	appURL, _ := app.CharmURL()
+	c.Assert(appURL.String(), gc.Equals, savedURL.String())
+	units, err := app.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+}
+
 func (s *clientSuite) TestAddLocalCharmOtherModel(c *gc.C) {
 	charmArchive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
 	curl := charm.MustParseURL(
@@ -17,7 +17,7 @@ var facadeVersions = map[string]int{
 	"AgentTools":                   1,
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
-	"Annotations":                  2,
+	"Annotations":                  3,
 	"Application":                  6,
 	"ApplicationOffers":            1,
 	"ApplicationScaler":            1,
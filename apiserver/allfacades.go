@@ -127,6 +127,7 @@ func AllFacades() *facade.Registry {
 	reg("Agent", 2, agent.NewAgentAPIV2)
 	reg("AgentTools", 1, agenttools.NewFacade)
 	reg("Annotations", 2, annotations.NewAPI)
+	reg("Annotations", 3, annotations.NewAPIv3) // Version 3 adds GetWithPrefix.
 
 	// Application facade versions 1-4 share NewFacadeV4 as
 	// the newer methodology for versioning wasn't started with
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/simplestreams"
@@ -86,7 +87,12 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine, env environs.Envi
 		return nil, errors.Annotate(err, "cannot determine machine endpoint bindings")
 	}
 
-	imageMetadata, err := p.availableImageMetadata(m, env)
+	imageStream, err := p.machineImageStream(m)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot determine machine image stream")
+	}
+
+	imageMetadata, err := p.availableImageMetadata(m, env, imageStream)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot get available image metadata")
 	}
@@ -107,6 +113,7 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine, env environs.Envi
 		SubnetsToZones:    subnetsToZones,
 		EndpointBindings:  endpointBindings,
 		ImageMetadata:     imageMetadata,
+		ImageStream:       imageStream,
 		ControllerConfig:  controllerCfg,
 		CloudInitUserData: env.Config().CloudInitUserData(),
 	}, nil
@@ -383,10 +390,41 @@ func (p *ProvisionerAPI) allSpaceNamesToProviderIds() (map[string]string, error)
 	return namesToProviderIds, nil
 }
 
+// machineImageStream returns the image stream selected in the application
+// config of the machine's principal units, or "" if none of them selects
+// one and the model's image-stream should be used.
+func (p *ProvisionerAPI) machineImageStream(m *state.Machine) (string, error) {
+	units, err := m.Units()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	applicationNames := set.NewStrings()
+	for _, unit := range units {
+		if unit.IsPrincipal() {
+			applicationNames.Add(unit.ApplicationName())
+		}
+	}
+	for _, name := range applicationNames.SortedValues() {
+		app, err := p.st.Application(name)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		appConfig, err := app.ApplicationConfig()
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if stream := appConfig.GetString(application.ImageStreamKey, ""); stream != "" {
+			return stream, nil
+		}
+	}
+	return "", nil
+}
+
 // availableImageMetadata returns all image metadata available to this machine
-// or an error fetching them.
-func (p *ProvisionerAPI) availableImageMetadata(m *state.Machine, env environs.Environ) ([]params.CloudImageMetadata, error) {
-	imageConstraint, err := p.constructImageConstraint(m, env)
+// or an error fetching them. If imageStream is empty, the model's
+// image-stream is used.
+func (p *ProvisionerAPI) availableImageMetadata(m *state.Machine, env environs.Environ, imageStream string) ([]params.CloudImageMetadata, error) {
+	imageConstraint, err := p.constructImageConstraint(m, env, imageStream)
 	if err != nil {
 		return nil, errors.Annotate(err, "could not construct image constraint")
 	}
@@ -402,10 +440,13 @@ func (p *ProvisionerAPI) availableImageMetadata(m *state.Machine, env environs.E
 }
 
 // constructImageConstraint returns model-specific criteria used to look for image metadata.
-func (p *ProvisionerAPI) constructImageConstraint(m *state.Machine, env environs.Environ) (*imagemetadata.ImageConstraint, error) {
+func (p *ProvisionerAPI) constructImageConstraint(m *state.Machine, env environs.Environ, imageStream string) (*imagemetadata.ImageConstraint, error) {
+	if imageStream == "" {
+		imageStream = env.Config().ImageStream()
+	}
 	lookup := simplestreams.LookupParams{
 		Series: []string{m.Series()},
-		Stream: env.Config().ImageStream(),
+		Stream: imageStream,
 	}
 
 	mcons, err := m.Constraints()
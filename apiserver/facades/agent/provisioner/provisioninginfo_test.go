@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/constraints"
+	coreapplication "github.com/juju/juju/core/application"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/provider/dummy"
@@ -21,6 +22,7 @@ import (
 	"github.com/juju/juju/storage/poolmanager"
 	"github.com/juju/juju/storage/provider"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 )
 
 func (s *withoutControllerSuite) TestProvisioningInfoWithStorage(c *gc.C) {
@@ -239,6 +241,45 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithEndpointBindings(c *gc.
 	c.Assert(result, jc.DeepEquals, expected)
 }
 
+func (s *withoutControllerSuite) TestProvisioningInfoWithApplicationImageStream(c *gc.C) {
+	ch := s.AddTestingCharm(c, "wordpress")
+	daily := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:                    "daily",
+		Charm:                   ch,
+		ApplicationConfig:       map[string]interface{}{coreapplication.ImageStreamKey: "daily"},
+		ApplicationConfigFields: coreapplication.IAASConfigSchema(),
+	})
+	plain := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:  "plain",
+		Charm: ch,
+	})
+	var machines []*state.Machine
+	for _, app := range []*state.Application{daily, plain} {
+		unit, err := app.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		m, err := s.State.AddOneMachine(state.MachineTemplate{
+			Series: "quantal",
+			Jobs:   []state.MachineJob{state.JobHostUnits},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(m)
+		c.Assert(err, jc.ErrorIsNil)
+		machines = append(machines, m)
+	}
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: machines[0].Tag().String()},
+		{Tag: machines[1].Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.ImageStream, gc.Equals, "daily")
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[1].Result.ImageStream, gc.Equals, "")
+}
+
 func (s *withoutControllerSuite) TestProvisioningInfoWithUnsuitableSpacesConstraints(c *gc.C) {
 	// Add an empty space.
 	_, err := s.State.AddSpace("empty", "", nil, true)
@@ -4,6 +4,8 @@
 package annotations
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
@@ -31,6 +33,25 @@ type API struct {
 	authorizer facade.Authorizer
 }
 
+// APIv3 implements version 3 of the Annotations facade, which adds
+// GetWithPrefix.
+type APIv3 struct {
+	*API
+}
+
+// NewAPIv3 returns a new charm annotator API facade, version 3.
+func NewAPIv3(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*APIv3, error) {
+	api, err := NewAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv3{api}, nil
+}
+
 // NewAPI returns a new charm annotator API facade.
 func NewAPI(
 	st *state.State,
@@ -98,6 +119,26 @@ func (api *API) Get(args params.Entities) params.AnnotationsGetResults {
 	return params.AnnotationsGetResults{Results: entityResults}
 }
 
+// GetWithPrefix returns the annotations for the given entities whose
+// keys start with the given prefix. An entity with no matching
+// annotations gets an empty result rather than an error.
+func (api *APIv3) GetWithPrefix(args params.AnnotationsGetWithPrefix) params.AnnotationsGetResults {
+	results := api.Get(params.Entities{Entities: args.Entities})
+	for i, result := range results.Results {
+		if result.Error.Error != nil {
+			continue
+		}
+		matching := make(map[string]string)
+		for key, value := range result.Annotations {
+			if strings.HasPrefix(key, args.Prefix) {
+				matching[key] = value
+			}
+		}
+		results.Results[i].Annotations = matching
+	}
+	return results
+}
+
 // Set stores annotations for given entities
 func (api *API) Set(args params.AnnotationsSet) params.ErrorResults {
 	if err := api.checkCanWrite(); err != nil {
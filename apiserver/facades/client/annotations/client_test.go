@@ -231,6 +231,71 @@ func (s *annotationSuite) TestMultipleEntitiesAnnotations(c *gc.C) {
 	c.Assert(rGet, jc.IsTrue)
 }
 
+func (s *annotationSuite) TestSetMixedValidAndInvalidAnnotations(c *gc.C) {
+	valid := s.Factory.MakeMachine(c, nil).Tag().String()
+	badKey := s.Factory.MakeMachine(c, nil).Tag().String()
+	badTag := "not-a-tag"
+
+	setResult := s.annotationsAPI.Set(params.AnnotationsSet{Annotations: []params.EntityAnnotations{{
+		EntityTag:   valid,
+		Annotations: map[string]string{"mykey": "myvalue"},
+	}, {
+		EntityTag:   badKey,
+		Annotations: map[string]string{"invalid.key": "myvalue"},
+	}, {
+		EntityTag:   badTag,
+		Annotations: map[string]string{"mykey": "myvalue"},
+	}}})
+	c.Assert(setResult.Results, gc.HasLen, 2)
+	c.Assert(setResult.Results[0].Error, gc.ErrorMatches,
+		fmt.Sprintf(`while setting annotations to %q: .*invalid key "invalid.key"`, badKey))
+	c.Assert(setResult.Results[1].Error, gc.ErrorMatches,
+		fmt.Sprintf(`while setting annotations to %q: .*not a valid tag`, badTag))
+
+	// The valid entity was annotated regardless of the failures.
+	got := s.annotationsAPI.Get(params.Entities{[]params.Entity{{valid}, {badKey}}})
+	c.Assert(got.Results, gc.HasLen, 2)
+	c.Assert(got.Results[0].Annotations, gc.DeepEquals, map[string]string{"mykey": "myvalue"})
+	c.Assert(got.Results[1].Annotations, gc.HasLen, 0)
+}
+
+func (s *annotationSuite) TestGetWithPrefix(c *gc.C) {
+	apiV3, err := annotations.NewAPIv3(s.State, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	annotated := s.Factory.MakeMachine(c, nil).Tag().String()
+	bare := s.Factory.MakeMachine(c, nil).Tag().String()
+	setResult := s.annotationsAPI.Set(params.AnnotationsSet{Annotations: []params.EntityAnnotations{{
+		EntityTag:   annotated,
+		Annotations: map[string]string{"gui-x": "10", "gui-y": "20", "owner": "ops"},
+	}}})
+	c.Assert(setResult.Combine(), jc.ErrorIsNil)
+
+	getWithPrefix := func(prefix string, entities ...string) []params.AnnotationsGetResult {
+		args := params.AnnotationsGetWithPrefix{Prefix: prefix}
+		for _, entity := range entities {
+			args.Entities = append(args.Entities, params.Entity{entity})
+		}
+		return apiV3.GetWithPrefix(args).Results
+	}
+
+	results := getWithPrefix("gui-", annotated, bare, "machine-99")
+	c.Assert(results, gc.HasLen, 3)
+	c.Check(results[0].Error.Error, gc.IsNil)
+	c.Check(results[0].Annotations, jc.DeepEquals, map[string]string{"gui-x": "10", "gui-y": "20"})
+	c.Check(results[1].Error.Error, gc.IsNil)
+	c.Check(results[1].Annotations, gc.HasLen, 0)
+	c.Check(results[2].Error.Error, gc.ErrorMatches, `.*permission denied.*`)
+
+	results = getWithPrefix("nothing", annotated)
+	c.Assert(results, gc.HasLen, 1)
+	c.Check(results[0].Error.Error, gc.IsNil)
+	c.Check(results[0].Annotations, gc.HasLen, 0)
+
+	results = getWithPrefix("", annotated)
+	c.Assert(results, gc.HasLen, 1)
+	c.Check(results[0].Annotations, gc.HasLen, 3)
+}
+
 func (s *annotationSuite) testSetGetEntitiesAnnotations(c *gc.C, tag names.Tag) {
 	entity := tag.String()
 	entities := []string{entity}
@@ -211,7 +211,7 @@ func (api *APIv5) Deploy(args params.ApplicationsDeploy) (params.ErrorResults, e
 
 func applicationConfigSchema(modelType state.ModelType) (environschema.Fields, schema.Defaults, error) {
 	if modelType != state.ModelTypeCAAS {
-		return environschema.Fields{}, schema.Defaults{}, nil
+		return application.IAASConfigSchema(), schema.Defaults{}, nil
 	}
 	// TODO(caas) - get the schema from the provider
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
@@ -277,7 +277,7 @@ func deployApplication(
 
 	// Do a quick but not complete validation check before going any further.
 	for _, p := range args.Placement {
-		if p.Scope != instance.MachineScope {
+		if p == nil || p.Scope != instance.MachineScope {
 			continue
 		}
 		_, err = backend.Machine(p.Directive)
@@ -411,6 +411,26 @@ func parseSettingsCompatible(charmConfig *charm.Config, settings map[string]stri
 	return changes, nil
 }
 
+// parseSettingsWithDefaults parses setting strings as
+// parseSettingsCompatible does. If fillDefaults is true, the
+// result also holds the charm's default value for every option
+// with a default that is not mentioned in settings.
+func parseSettingsWithDefaults(charmConfig *charm.Config, settings map[string]string, fillDefaults bool) (charm.Settings, error) {
+	changes, err := parseSettingsCompatible(charmConfig, settings)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !fillDefaults {
+		return changes, nil
+	}
+	for name, value := range charmConfig.DefaultSettings() {
+		if _, ok := settings[name]; !ok {
+			changes[name] = value
+		}
+	}
+	return changes, nil
+}
+
 // Update updates the application attributes, including charm URL,
 // minimum number of units, charm config and constraints.
 // All parameters in params.ApplicationUpdate except the application name are optional.
@@ -301,6 +301,47 @@ func (s *applicationSuite) TestCompatibleSettingsParsing(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unknown option "yummy"`)
 }
 
+func (s *applicationSuite) TestSettingsParsingWithDefaults(c *gc.C) {
+	ch := s.AddTestingCharm(c, "dummy")
+	options := map[string]string{
+		"title":    "foobar",
+		"username": "",
+	}
+
+	// Defaults are not included unless requested.
+	settings, err := application.ParseSettingsWithDefaults(ch.Config(), options, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{
+		"title":    "foobar",
+		"username": nil,
+	})
+
+	// Only options that have defaults and are not mentioned are filled.
+	options["outlook"] = "fine"
+	settings, err = application.ParseSettingsWithDefaults(ch.Config(), options, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{
+		"title":    "foobar",
+		"username": nil,
+		"outlook":  "fine",
+	})
+	delete(options, "title")
+	settings, err = application.ParseSettingsWithDefaults(ch.Config(), options, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{
+		"title":    "My Title",
+		"username": nil,
+		"outlook":  "fine",
+	})
+
+	// Unknown options still lead to an error.
+	options = map[string]string{
+		"yummy": "didgeridoo",
+	}
+	_, err = application.ParseSettingsWithDefaults(ch.Config(), options, true)
+	c.Assert(err, gc.ErrorMatches, `unknown option "yummy"`)
+}
+
 func (s *applicationSuite) TestApplicationDeployWithStorage(c *gc.C) {
 	curl, ch := s.UploadCharm(c, "utopic/storage-block-10", "storage-block")
 	err := application.AddCharmWithAuthorization(s.State, params.AddCharmWithAuthorization{
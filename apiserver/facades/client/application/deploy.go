@@ -33,7 +33,9 @@ type DeployApplicationParams struct {
 	Constraints       constraints.Value
 	NumUnits          int
 	// Placement is a list of placement directives which may be used
-	// instead of a machine spec.
+	// instead of a machine spec. The directives are applied to
+	// successive units; units without a directive, or with a nil or
+	// empty one, are assigned according to the default policy.
 	Placement        []*instance.Placement
 	Storage          map[string]storage.Constraints
 	AttachStorage    []names.StorageTag
@@ -64,6 +66,12 @@ func DeployApplication(st ApplicationDeployer, args DeployApplicationParams) (Ap
 			return nil, fmt.Errorf("subordinate application must be deployed without constraints")
 		}
 	}
+	if len(args.Placement) > args.NumUnits {
+		return nil, errors.NotValidf(
+			"%d placement directives for %d units",
+			len(args.Placement), args.NumUnits,
+		)
+	}
 	// TODO(fwereade): transactional State.AddApplication including settings, constraints
 	// (minimumUnitCount, initialMachineIds?).
 
@@ -184,7 +192,7 @@ func addUnits(
 		}
 
 		// Are there still placement directives to use?
-		if i > len(placement)-1 {
+		if i > len(placement)-1 || placement[i] == nil || *placement[i] == (instance.Placement{}) {
 			if err := unit.AssignWithPolicy(policy); err != nil {
 				return nil, errors.Trace(err)
 			}
@@ -455,6 +455,62 @@ func (s *DeployLocalSuite) TestDeployWithFewerPlacement(c *gc.C) {
 	c.Assert(f.args.Placement, gc.DeepEquals, placement)
 }
 
+func (s *DeployLocalSuite) TestDeployWithTooManyPlacement(c *gc.C) {
+	var f fakeDeployer
+	_, err := application.DeployApplication(&f,
+		application.DeployApplicationParams{
+			ApplicationName: "bob",
+			Charm:           s.charm,
+			NumUnits:        1,
+			Placement: []*instance.Placement{
+				{Scope: "#", Directive: "0"},
+				{Scope: "#", Directive: "1"},
+			},
+		})
+	c.Assert(err, gc.ErrorMatches, "2 placement directives for 1 units not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *DeployLocalSuite) TestDeployPlacementPerUnit(c *gc.C) {
+	for i := 0; i < 2; i++ {
+		_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	app, err := application.DeployApplication(stateDeployer{s.State},
+		application.DeployApplicationParams{
+			ApplicationName: "bob",
+			Charm:           s.charm,
+			NumUnits:        3,
+			Placement: []*instance.Placement{
+				{Scope: "#", Directive: "0"},
+				nil,
+				{Scope: "lxd", Directive: "1"},
+			},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	units, err := app.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 3)
+	for _, unit := range units {
+		res, err := s.State.AssignStagedUnits([]string{unit.UnitTag().Id()})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(res[0].Error, jc.ErrorIsNil)
+	}
+
+	assigned := func(name string) string {
+		unit, err := s.State.Unit(name)
+		c.Assert(err, jc.ErrorIsNil)
+		id, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		return id
+	}
+	c.Assert(assigned("bob/0"), gc.Equals, "0")
+	// The unit without a directive is assigned by the default policy.
+	c.Assert(assigned("bob/1"), gc.Not(gc.Equals), "0")
+	c.Assert(assigned("bob/2"), gc.Equals, "1/lxd/0")
+}
+
 func (s *DeployLocalSuite) assertCharm(c *gc.C, app application.Application, expect *charm.URL) {
 	curl, force := app.CharmURL()
 	c.Assert(curl, gc.DeepEquals, expect)
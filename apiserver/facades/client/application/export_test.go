@@ -4,6 +4,7 @@
 package application
 
 var (
-	ParseSettingsCompatible = parseSettingsCompatible
-	NewStateStorage         = &newStateStorage
+	ParseSettingsCompatible   = parseSettingsCompatible
+	ParseSettingsWithDefaults = parseSettingsWithDefaults
+	NewStateStorage           = &newStateStorage
 )
@@ -9,6 +9,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/environschema.v1"
 
 	apiapplication "github.com/juju/juju/api/application"
 	"github.com/juju/juju/apiserver/common"
@@ -112,8 +113,14 @@ func (s *getSuite) TestClientApplicationGetIAASModelSmoketest(c *gc.C) {
 				"value":       "My Title",
 			},
 		},
-		ApplicationConfig: map[string]interface{}{},
-		Series:            "quantal",
+		ApplicationConfig: map[string]interface{}{
+			"image-stream": map[string]interface{}{
+				"description": "the image stream used to provision machines for the application",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
+		},
+		Series: "quantal",
 	})
 }
 
@@ -88,6 +88,8 @@ type Unit interface {
 	PublicAddress() (network.Address, error)
 	PrivateAddress() (network.Address, error)
 	Resolve(retryHooks bool) error
+	Status() (status.StatusInfo, error)
+	SubordinateNames() []string
 	AgentHistory() status.StatusHistoryGetter
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
+	"github.com/juju/juju/status"
 	jujuversion "github.com/juju/juju/version"
 )
 
@@ -217,6 +218,55 @@ func (c *Client) Resolved(p params.Resolved) error {
 	return unit.Resolve(p.Retry)
 }
 
+// ResolveUnitAndSubordinates implements the server side of
+// Client.ResolveUnitAndSubordinates. It resolves the given unit and any
+// of its subordinate units that are in error; other subordinates are
+// left alone.
+func (c *Client) ResolveUnitAndSubordinates(p params.ResolveUnitAndSubordinates) (params.UnitResolvedResults, error) {
+	if err := c.checkCanWrite(); err != nil {
+		return params.UnitResolvedResults{}, err
+	}
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.UnitResolvedResults{}, errors.Trace(err)
+	}
+	unit, err := c.api.stateAccessor.Unit(p.UnitName)
+	if err != nil {
+		return params.UnitResolvedResults{}, err
+	}
+	results := []params.UnitResolvedResult{{
+		UnitName: p.UnitName,
+		Error:    common.ServerError(unit.Resolve(!p.RetryHooks)),
+	}}
+	for _, name := range unit.SubordinateNames() {
+		inError, err := c.resolveIfInError(name, !p.RetryHooks)
+		if err == nil && !inError {
+			continue
+		}
+		results = append(results, params.UnitResolvedResult{
+			UnitName: name,
+			Error:    common.ServerError(err),
+		})
+	}
+	return params.UnitResolvedResults{Results: results}, nil
+}
+
+// resolveIfInError resolves the named unit if it is in error, and
+// reports whether it was.
+func (c *Client) resolveIfInError(name string, noretryHooks bool) (bool, error) {
+	unit, err := c.api.stateAccessor.Unit(name)
+	if err != nil {
+		return false, err
+	}
+	statusInfo, err := unit.Status()
+	if err != nil {
+		return false, err
+	}
+	if statusInfo.Status != status.Error {
+		return false, nil
+	}
+	return true, unit.Resolve(noretryHooks)
+}
+
 // PublicAddress implements the server side of Client.PublicAddress.
 func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAddressResults, err error) {
 	if err := c.checkCanRead(); err != nil {
@@ -655,6 +655,65 @@ func (s *clientSuite) TestBlockChangeUnitResolved(c *gc.C) {
 	s.assertResolvedBlocked(c, u, "TestBlockChangeUnitResolved")
 }
 
+func (s *clientSuite) TestClientResolveUnitAndSubordinates(c *gc.C) {
+	// wordpress/0 is set up in error; put its subordinate in error too.
+	s.setUpScenario(c)
+	now := time.Now()
+	sub, err := s.State.Unit("logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = sub.SetAgentStatus(status.StatusInfo{
+		Status:  status.Error,
+		Message: "gaaah",
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.APIState.Client().ResolveUnitAndSubordinates("wordpress/0", true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.UnitResolvedResult{
+		{UnitName: "wordpress/0"},
+		{UnitName: "logging/0"},
+	})
+	for _, name := range []string{"wordpress/0", "logging/0"} {
+		u, err := s.State.Unit(name)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(u.Resolved(), gc.Equals, state.ResolvedRetryHooks)
+	}
+}
+
+func (s *clientSuite) TestClientResolveUnitAndSubordinatesSkipsHealthySubordinates(c *gc.C) {
+	s.setUpScenario(c)
+
+	results, err := s.APIState.Client().ResolveUnitAndSubordinates("wordpress/0", false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.UnitResolvedResult{
+		{UnitName: "wordpress/0"},
+	})
+	u, err := s.State.Unit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(u.Resolved(), gc.Equals, state.ResolvedNoHooks)
+	sub, err := s.State.Unit("logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(sub.Resolved(), gc.Equals, state.ResolvedNone)
+}
+
+func (s *clientSuite) TestClientResolveUnitAndSubordinatesNotInError(c *gc.C) {
+	s.setUpScenario(c)
+
+	results, err := s.APIState.Client().ResolveUnitAndSubordinates("wordpress/1", true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].UnitName, gc.Equals, "wordpress/1")
+	c.Assert(results[0].Error, gc.ErrorMatches, `unit "wordpress/1" is not in an error state`)
+}
+
+func (s *clientSuite) TestBlockChangeResolveUnitAndSubordinates(c *gc.C) {
+	s.setUpScenario(c)
+	s.BlockAllChanges(c, "TestBlockChangeResolveUnitAndSubordinates")
+	_, err := s.APIState.Client().ResolveUnitAndSubordinates("wordpress/0", true)
+	s.AssertBlocked(c, err, "TestBlockChangeResolveUnitAndSubordinates")
+}
+
 type clientRepoSuite struct {
 	baseSuite
 	testing.CharmStoreSuite
@@ -1178,6 +1237,29 @@ func (s *clientSuite) TestClientAddMachinesSomeErrors(c *gc.C) {
 	c.Check(machines[2].Error, gc.ErrorMatches, "cannot add a new machine: machine 0 cannot host kvm containers")
 }
 
+func (s *clientSuite) TestClientAddMachinesInvalidEntryDoesNotBlockOthers(c *gc.C) {
+	apiParams := []params.AddMachineParams{{
+		Jobs: []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+	}, {
+		// No jobs, so this one fails.
+	}, {
+		Jobs:        []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		Constraints: constraints.MustParse("mem=4G"),
+	}}
+	machines, err := s.APIState.Client().AddMachines(apiParams)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 3)
+
+	c.Check(machines[0].Error, gc.IsNil)
+	c.Check(machines[0].Machine, gc.Equals, "0")
+	s.checkMachine(c, "0", series.LatestLts(), "")
+	c.Check(machines[1].Error, gc.ErrorMatches, "cannot add a new machine: no jobs specified")
+	c.Check(machines[1].Machine, gc.Equals, "")
+	c.Check(machines[2].Error, gc.IsNil)
+	c.Check(machines[2].Machine, gc.Equals, "1")
+	s.checkMachine(c, "1", series.LatestLts(), "mem=4G")
+}
+
 func (s *clientSuite) TestClientAddMachinesWithInstanceIdSomeErrors(c *gc.C) {
 	apiParams := make([]params.AddMachineParams, 3)
 	addrs := network.NewAddresses("1.2.3.4")
@@ -1620,7 +1702,7 @@ func (s *clientSuite) assertForceDestroyMachines(c *gc.C) {
 	assertLife(c, m2, state.Alive)
 	assertLife(c, u, state.Alive)
 
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 	assertLife(c, m0, state.Alive)
 	assertLife(c, m1, state.Dead)
@@ -89,6 +89,10 @@ func (s *permSuite) TestOperationPerm(c *gc.C) {
 		about: "Client.Resolved",
 		op:    opClientResolved,
 		allow: []names.Tag{userAdmin, userOther},
+	}, {
+		about: "Client.ResolveUnitAndSubordinates",
+		op:    opClientResolveUnitAndSubordinates,
+		allow: []names.Tag{userAdmin, userOther},
 	}, {
 		about: "Application.Expose",
 		op:    opClientServiceExpose,
@@ -282,6 +286,17 @@ func opClientResolved(c *gc.C, st api.Connection, _ *state.State) (func(), error
 	return func() {}, nil
 }
 
+func opClientResolveUnitAndSubordinates(c *gc.C, st api.Connection, _ *state.State) (func(), error) {
+	results, err := st.Client().ResolveUnitAndSubordinates("wordpress/1", false)
+	if err != nil {
+		return func() {}, err
+	}
+	// The user was authorized, but the unit is not in an error state.
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `unit "wordpress/1" is not in an error state`)
+	return func() {}, nil
+}
+
 func opClientGetAnnotations(c *gc.C, st api.Connection, mst *state.State) (func(), error) {
 	ann, err := annotations.NewClient(st).Get([]string{"application-wordpress"})
 	if err != nil {
@@ -14,6 +14,13 @@ type stateShim struct {
 	*state.State
 }
 
+// Cleanup runs the queued cleanups, discarding the report of what
+// they removed.
+func (s stateShim) Cleanup() error {
+	_, err := s.State.Cleanup()
+	return err
+}
+
 var getState = func(st *state.State) StateInterface {
 	return stateShim{st}
 }
@@ -25,3 +25,10 @@ type EntityAnnotations struct {
 	EntityTag   string            `json:"entity"`
 	Annotations map[string]string `json:"annotations"`
 }
+
+// AnnotationsGetWithPrefix holds the parameters for making a
+// GetWithPrefix call on the Annotations client.
+type AnnotationsGetWithPrefix struct {
+	Entities []Entity `json:"entities"`
+	Prefix   string   `json:"prefix"`
+}
@@ -568,6 +568,12 @@ type RelationUnitsChange struct {
 	// Departed holds a set of units that have previously been reported to
 	// be in scope, but which no longer are.
 	Departed []string `json:"departed,omitempty"`
+
+	// ChangedKeys holds, for units in Changed, the settings keys that
+	// were added, modified or removed since the unit's settings were
+	// last reported. It is only populated by watchers that diff keys,
+	// and units whose settings values did not change have no entry.
+	ChangedKeys map[string][]string `json:"changed-keys,omitempty"`
 }
 
 // RelationUnitsWatchResult holds a RelationUnitsWatcher id, baseline state
@@ -686,6 +692,7 @@ type ProvisioningInfo struct {
 	EndpointBindings  map[string]string         `json:"endpoint-bindings,omitempty"`
 	ControllerConfig  map[string]interface{}    `json:"controller-config,omitempty"`
 	CloudInitUserData map[string]interface{}    `json:"cloudinit-userdata,omitempty"`
+	ImageStream       string                    `json:"image-stream,omitempty"`
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
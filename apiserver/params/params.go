@@ -453,6 +453,25 @@ type Resolved struct {
 	Retry    bool   `json:"retry"`
 }
 
+// ResolveUnitAndSubordinates holds parameters for the
+// ResolveUnitAndSubordinates call.
+type ResolveUnitAndSubordinates struct {
+	UnitName   string `json:"unit-name"`
+	RetryHooks bool   `json:"retry-hooks"`
+}
+
+// UnitResolvedResult holds the outcome of resolving a single unit.
+type UnitResolvedResult struct {
+	UnitName string `json:"unit-name"`
+	Error    *Error `json:"error,omitempty"`
+}
+
+// UnitResolvedResults holds results of the ResolveUnitAndSubordinates
+// call.
+type UnitResolvedResults struct {
+	Results []UnitResolvedResult `json:"results"`
+}
+
 // ResolvedResults holds results of the Resolved call.
 type ResolvedResults struct {
 	Application string                 `json:"application"`
@@ -32,6 +32,7 @@ const (
 	InstanceType = "instance-type"
 	Spaces       = "spaces"
 	VirtType     = "virt-type"
+	Zones        = "zones"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -85,6 +86,11 @@ type Value struct {
 	// VirtType, if not nil or empty, indicates that a machine must run the named
 	// virtual type. Only valid for clouds with multi-hypervisor support.
 	VirtType *string `json:"virt-type,omitempty" yaml:"virt-type,omitempty"`
+
+	// Zones, if not nil, holds a list of availability zones the machine
+	// must (or, for names with a "^" prefix, must not) be placed in.
+	// A machine in any one of the positive zones is acceptable.
+	Zones *[]string `json:"zones,omitempty" yaml:"zones,omitempty"`
 }
 
 var rawAliases = map[string]string{
@@ -170,6 +176,25 @@ func (v *Value) ExcludeSpaces() []string {
 	return v.extractItems(*v.Spaces, false)
 }
 
+// IncludeZones returns a list of zones the machine may be placed in,
+// if specified.
+func (v *Value) IncludeZones() []string {
+	if v.Zones == nil {
+		return nil
+	}
+	return v.extractItems(*v.Zones, true)
+}
+
+// ExcludeZones returns a list of zones the machine must not be placed
+// in, if specified. They are given in the zones constraint with a "^"
+// prefix to the name, which is stripped before returning.
+func (v *Value) ExcludeZones() []string {
+	if v.Zones == nil {
+		return nil
+	}
+	return v.extractItems(*v.Zones, false)
+}
+
 // HaveSpaces returns whether any spaces constraints were specified.
 func (v *Value) HaveSpaces() bool {
 	return v.Spaces != nil && len(*v.Spaces) > 0
@@ -223,6 +248,10 @@ func (v Value) String() string {
 	if v.VirtType != nil {
 		strs = append(strs, "virt-type="+string(*v.VirtType))
 	}
+	if v.Zones != nil {
+		s := strings.Join(*v.Zones, ",")
+		strs = append(strs, "zones="+s)
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -264,6 +293,11 @@ func (v Value) GoString() string {
 	if v.VirtType != nil {
 		values = append(values, fmt.Sprintf("VirtType: %q", *v.VirtType))
 	}
+	if v.Zones != nil && *v.Zones != nil {
+		values = append(values, fmt.Sprintf("Zones: %q", *v.Zones))
+	} else if v.Zones != nil {
+		values = append(values, "Zones: (*[]string)(nil)")
+	}
 	return fmt.Sprintf("{%s}", strings.Join(values, ", "))
 }
 
@@ -420,6 +454,8 @@ func (v *Value) setRaw(name, str string) error {
 		err = v.setSpaces(str)
 	case VirtType:
 		err = v.setVirtType(str)
+	case Zones:
+		err = v.setZones(str)
 	default:
 		return errors.Errorf("unknown constraint %q", name)
 	}
@@ -483,6 +519,8 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			}
 		case VirtType:
 			v.VirtType = &vstr
+		case Zones:
+			v.Zones, err = parseYamlStrings("zones", val)
 		default:
 			return errors.Errorf("unknown constraint value: %v", k)
 		}
@@ -599,6 +637,14 @@ func (v *Value) validateSpaces(spaces *[]string) error {
 	return nil
 }
 
+func (v *Value) setZones(str string) error {
+	if v.Zones != nil {
+		return errors.Errorf("already set")
+	}
+	v.Zones = parseCommaDelimited(str)
+	return nil
+}
+
 func (v *Value) setVirtType(str string) error {
 	if v.VirtType != nil {
 		return errors.Errorf("already set")
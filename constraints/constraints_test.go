@@ -284,6 +284,18 @@ var parseConstraintsTests = []struct {
 		args:    []string{"spaces="},
 	},
 
+	// zones
+	{
+		summary: "single zone",
+		args:    []string{"zones=az1"},
+	}, {
+		summary: "multiple zones - positive and negative",
+		args:    []string{"zones=az1,^az2,az3"},
+	}, {
+		summary: "no zones",
+		args:    []string{"zones="},
+	},
+
 	// instance type
 	{
 		summary: "set instance type",
@@ -422,6 +434,15 @@ func (s *ConstraintsSuite) TestIncludeExcludeAndHaveSpaces(c *gc.C) {
 	c.Check(con.HaveSpaces(), jc.IsTrue)
 }
 
+func (s *ConstraintsSuite) TestIncludeExcludeZones(c *gc.C) {
+	con := constraints.MustParse("zones=az1,^az2,az3")
+	c.Check(con.IncludeZones(), jc.SameContents, []string{"az1", "az3"})
+	c.Check(con.ExcludeZones(), jc.SameContents, []string{"az2"})
+	con = constraints.MustParse("mem=4G")
+	c.Check(con.IncludeZones(), gc.IsNil)
+	c.Check(con.ExcludeZones(), gc.IsNil)
+}
+
 func (s *ConstraintsSuite) TestInvalidSpaces(c *gc.C) {
 	invalidNames := []string{
 		"%$pace", "^foo#2", "+", "tcp:ip",
@@ -508,6 +529,9 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Spaces1", constraints.Value{Spaces: nil}},
 	{"Spaces2", constraints.Value{Spaces: &[]string{}}},
 	{"Spaces3", constraints.Value{Spaces: &[]string{"space1", "^space2"}}},
+	{"Zones1", constraints.Value{Zones: nil}},
+	{"Zones2", constraints.Value{Zones: &[]string{}}},
+	{"Zones3", constraints.Value{Zones: &[]string{"az1", "^az2"}}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
 	{"All", constraints.Value{
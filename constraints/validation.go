@@ -40,6 +40,10 @@ type Validator interface {
 	// overriding those from consFallback.
 	Merge(consFallback, cons Value) (Value, error)
 
+	// MergeConflicts reports, without merging, the attributes of
+	// consFallback which would be overridden by cons in a call to Merge.
+	MergeConflicts(consFallback, cons Value) ([]MergeConflict, error)
+
 	// UpdateVocabulary merges new attribute values with existing values.
 	// This method does not overwrite or delete values, i.e.
 	//     if existing values are {a, b}
@@ -48,6 +52,28 @@ type Validator interface {
 	UpdateVocabulary(attributeName string, newValues interface{})
 }
 
+// MergeConflict describes an attribute of the overriding constraints
+// which displaces one or more attributes of the fallback constraints
+// when the two are merged.
+type MergeConflict struct {
+	// Attribute is the name of the overriding attribute.
+	Attribute string
+
+	// Value holds only the overriding attribute; this is the value
+	// which wins the merge.
+	Value Value
+
+	// Overridden holds the fallback attributes discarded by the merge.
+	// These are either the same attribute with a different value, or
+	// attributes registered as conflicting with it.
+	Overridden Value
+}
+
+// String returns a human readable description of the conflict.
+func (c MergeConflict) String() string {
+	return fmt.Sprintf("%q overrides %q", c.Value.String(), c.Overridden.String())
+}
+
 // NewValidator returns a new constraints Validator instance.
 func NewValidator() Validator {
 	return &validator{
@@ -286,3 +312,42 @@ func (v *validator) Merge(consFallback, cons Value) (Value, error) {
 	// non conflicting consFallback attributes.
 	return withFallbacks(cons, consFallbackMinusConflicts), nil
 }
+
+// MergeConflicts is defined on Validator.
+func (v *validator) MergeConflicts(consFallback, cons Value) ([]MergeConflict, error) {
+	if _, err := v.Validate(consFallback); err != nil {
+		return nil, err
+	}
+	if _, err := v.Validate(cons); err != nil {
+		return nil, err
+	}
+	fallbackValues := consFallback.attributesWithValues()
+	attrValues := cons.attributesWithValues()
+	attrTags := make(set.Strings)
+	for attrTag := range attrValues {
+		attrTags.Add(attrTag)
+	}
+	var result []MergeConflict
+	for _, attrTag := range attrTags.SortedValues() {
+		overridden := make(map[string]interface{})
+		// An attribute set to the same value in both is not a conflict,
+		// as the merge result is the same whichever one wins.
+		if fallback, ok := fallbackValues[attrTag]; ok && !reflect.DeepEqual(fallback, attrValues[attrTag]) {
+			overridden[attrTag] = fallback
+		}
+		for _, conflict := range v.conflicts[attrTag].Values() {
+			if fallback, ok := fallbackValues[conflict]; ok {
+				overridden[conflict] = fallback
+			}
+		}
+		if len(overridden) == 0 {
+			continue
+		}
+		result = append(result, MergeConflict{
+			Attribute:  attrTag,
+			Value:      fromAttributes(map[string]interface{}{attrTag: attrValues[attrTag]}),
+			Overridden: fromAttributes(overridden),
+		})
+	}
+	return result, nil
+}
@@ -364,6 +364,78 @@ func (s *validationSuite) TestMergeError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `ambiguous constraints: "instance-type" overlaps with "mem"`)
 }
 
+var mergeConflictsTests = []struct {
+	desc         string
+	consFallback string
+	cons         string
+	reds         []string
+	blues        []string
+	expected     []string
+}{
+	{
+		desc:         "no overlap",
+		consFallback: "root-disk=8G",
+		cons:         "mem=4G",
+	}, {
+		desc:         "same value is not a conflict",
+		consFallback: "mem=4G cores=2",
+		cons:         "mem=4G",
+	}, {
+		desc:         "overlapping keys",
+		consFallback: "mem=4G cores=2 arch=amd64",
+		cons:         "mem=8G cores=4 arch=amd64",
+		expected:     []string{"cores=4 <- cores=2", "mem=8192M <- mem=4096M"},
+	}, {
+		desc:         "red overrides blues",
+		consFallback: "instance-type=foo cores=2",
+		cons:         "mem=4G arch=amd64",
+		reds:         []string{"mem", "arch"},
+		blues:        []string{"instance-type"},
+		expected:     []string{"arch=amd64 <- instance-type=foo", "mem=4096M <- instance-type=foo"},
+	}, {
+		desc:         "blue overrides reds",
+		consFallback: "mem=4G arch=amd64 cores=2",
+		cons:         "instance-type=foo cores=4",
+		reds:         []string{"mem", "arch"},
+		blues:        []string{"instance-type"},
+		expected:     []string{"cores=4 <- cores=2", "instance-type=foo <- arch=amd64 mem=4096M"},
+	},
+}
+
+func (s *validationSuite) TestMergeConflicts(c *gc.C) {
+	for i, t := range mergeConflictsTests {
+		c.Logf("test %d: %s", i, t.desc)
+		validator := constraints.NewValidator()
+		validator.RegisterConflicts(t.reds, t.blues)
+		consFallback := constraints.MustParse(t.consFallback)
+		cons := constraints.MustParse(t.cons)
+		conflicts, err := validator.MergeConflicts(consFallback, cons)
+		c.Assert(err, jc.ErrorIsNil)
+		merged, err := validator.Merge(consFallback, cons)
+		c.Assert(err, jc.ErrorIsNil)
+
+		var reported []string
+		for _, conflict := range conflicts {
+			reported = append(reported, conflict.Value.String()+" <- "+conflict.Overridden.String())
+			// The reported winner must be what ended up in the merged
+			// value, so merging it in again changes nothing.
+			remerged, err := validator.Merge(merged, conflict.Value)
+			c.Assert(err, jc.ErrorIsNil)
+			c.Check(remerged, gc.DeepEquals, merged)
+		}
+		c.Check(reported, gc.DeepEquals, t.expected)
+	}
+}
+
+func (s *validationSuite) TestMergeConflictsError(c *gc.C) {
+	validator := constraints.NewValidator()
+	validator.RegisterConflicts([]string{"instance-type"}, []string{"mem"})
+	consFallback := constraints.MustParse("instance-type=foo mem=4G")
+	cons := constraints.MustParse("cores=2")
+	_, err := validator.MergeConflicts(consFallback, cons)
+	c.Assert(err, gc.ErrorMatches, `ambiguous constraints: "instance-type" overlaps with "mem"`)
+}
+
 func (s *validationSuite) TestUpdateVocabulary(c *gc.C) {
 	validator := constraints.NewValidator()
 	attributeName := "arch"
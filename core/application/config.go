@@ -10,6 +10,31 @@ import (
 	"gopkg.in/juju/environschema.v1"
 )
 
+const (
+	// ImageStreamKey specifies the image stream used to provision
+	// machines for an IAAS application, overriding the model's
+	// image-stream.
+	ImageStreamKey = "image-stream"
+)
+
+var iaasConfigFields = environschema.Fields{
+	ImageStreamKey: {
+		Description: "the image stream used to provision machines for the application",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Values:      []interface{}{"released", "daily"},
+	},
+}
+
+// IAASConfigSchema returns the valid fields for an IAAS application config.
+func IAASConfigSchema() environschema.Fields {
+	fields := make(environschema.Fields)
+	for name, field := range iaasConfigFields {
+		fields[name] = field
+	}
+	return fields
+}
+
 // ConfigAttributes is the config for an application.
 type ConfigAttributes map[string]interface{}
 
@@ -104,3 +104,15 @@ func (s *ApplicationSuite) TestGetBool(c *gc.C) {
 	c.Assert(cfg.Attributes().GetBool("field4", false), gc.Equals, true)
 	c.Assert(cfg.Attributes().GetBool("missing", true), gc.Equals, true)
 }
+
+func (s *ApplicationSuite) TestIAASConfigSchemaImageStream(c *gc.C) {
+	fields := application.IAASConfigSchema()
+	c.Assert(application.KnownConfigKeys(fields), jc.DeepEquals, set.NewStrings(application.ImageStreamKey))
+
+	cfg, err := application.NewConfig(map[string]interface{}{"image-stream": "daily"}, fields, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Attributes(), jc.DeepEquals, application.ConfigAttributes{"image-stream": "daily"})
+
+	_, err = application.NewConfig(map[string]interface{}{"image-stream": "nightly"}, fields, nil)
+	c.Assert(err, gc.ErrorMatches, `.*image-stream: expected one of .*, got "nightly"`)
+}
@@ -87,6 +87,12 @@ type StartInstanceParams struct {
 	// changes in status. Its signature is consistent with other
 	// status-related functions to allow them to be used as callbacks.
 	StatusCallback StatusCallbackFunc
+
+	// Abort, if not nil, is closed when the caller is no longer
+	// interested in the result, such as when the provisioner is
+	// stopping. Providers that can do so should give up starting the
+	// instance and return promptly; others may ignore it.
+	Abort <-chan struct{}
 }
 
 // StartInstanceResult holds the result of an
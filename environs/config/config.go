@@ -17,6 +17,7 @@ import (
 	"github.com/juju/utils/proxy"
 	"github.com/juju/utils/series"
 	"github.com/juju/version"
+	"golang.org/x/crypto/openpgp"
 	"gopkg.in/juju/charmrepo.v2"
 	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/juju/names.v2"
@@ -73,12 +74,28 @@ const (
 	// ProvisionerHarvestModeKey stores the key for this setting.
 	ProvisionerHarvestModeKey = "provisioner-harvest-mode"
 
+	// ProvisionerHarvestExcludeKey stores the key for the instance name
+	// prefixes the provisioner must never treat as unknown.
+	ProvisionerHarvestExcludeKey = "provisioner-harvest-exclude"
+
+	// ProvisionerHarvestUnknownGraceKey stores the key for how long an
+	// instance must have been unknown before the provisioner harvests it.
+	ProvisionerHarvestUnknownGraceKey = "provisioner-harvest-unknown-grace"
+
+	// ProvisionerStartTimeoutKey stores the key for how long the
+	// provisioner keeps retrying a machine that fails to start.
+	ProvisionerStartTimeoutKey = "provisioner-start-timeout"
+
 	// AgentStreamKey stores the key for this setting.
 	AgentStreamKey = "agent-stream"
 
 	// AgentMetadataURLKey stores the key for this setting.
 	AgentMetadataURLKey = "agent-metadata-url"
 
+	// ImageMetadataPublicKeyKey stores the key for the armored public
+	// key used to verify signed image metadata at image-metadata-url.
+	ImageMetadataPublicKeyKey = "image-metadata-public-key"
+
 	// HTTPProxyKey stores the key for this setting.
 	HTTPProxyKey = "http-proxy"
 
@@ -167,6 +184,10 @@ const (
 	// grow to before it is pruned, eg "5M"
 	MaxActionResultsSize = "max-action-results-size"
 
+	// MaxRelationSettingsSize is the maximum serialized size of a unit's
+	// settings within a relation, eg "1M"
+	MaxRelationSettingsSize = "max-relation-settings-size"
+
 	// UpdateStatusHookInterval is how often to run the update-status hook.
 	UpdateStatusHookInterval = "update-status-hook-interval"
 
@@ -344,6 +365,9 @@ const (
 	DefaultActionResultsAge = "336h" // 2 weeks
 
 	DefaultActionResultsSize = "5G"
+
+	// DefaultRelationSettingsSize is the default value for MaxRelationSettingsSize.
+	DefaultRelationSettingsSize = "1M"
 )
 
 var defaultConfigValues = map[string]interface{}{
@@ -374,26 +398,31 @@ var defaultConfigValues = map[string]interface{}{
 	NetBondReconfigureDelayKey: 17,
 	ContainerNetworkingMethod:  "",
 
-	"default-series":           series.LatestLts(),
-	ProvisionerHarvestModeKey:  HarvestDestroyed.String(),
-	ResourceTagsKey:            "",
-	"logging-config":           "",
-	AutomaticallyRetryHooks:    true,
-	"enable-os-refresh-update": true,
-	"enable-os-upgrade":        true,
-	"development":              false,
-	"test-mode":                false,
-	TransmitVendorMetricsKey:   true,
-	UpdateStatusHookInterval:   DefaultUpdateStatusHookInterval,
-	EgressSubnets:              "",
-	FanConfig:                  "",
-	CloudInitUserDataKey:       "",
+	"default-series":                  series.LatestLts(),
+	ProvisionerHarvestModeKey:         HarvestDestroyed.String(),
+	ProvisionerHarvestExcludeKey:      "",
+	ProvisionerHarvestUnknownGraceKey: "",
+	ProvisionerStartTimeoutKey:        "",
+	ResourceTagsKey:                   "",
+	"logging-config":                  "",
+	AutomaticallyRetryHooks:           true,
+	"enable-os-refresh-update":        true,
+	"enable-os-upgrade":               true,
+	"development":                     false,
+	"test-mode":                       false,
+	TransmitVendorMetricsKey:          true,
+	UpdateStatusHookInterval:          DefaultUpdateStatusHookInterval,
+	EgressSubnets:                     "",
+	FanConfig:                         "",
+	CloudInitUserDataKey:              "",
+	MaxRelationSettingsSize:           DefaultRelationSettingsSize,
 
 	// Image and agent streams and URLs.
-	"image-stream":       "released",
-	"image-metadata-url": "",
-	AgentStreamKey:       "released",
-	AgentMetadataURLKey:  "",
+	"image-stream":            "released",
+	"image-metadata-url":      "",
+	ImageMetadataPublicKeyKey: "",
+	AgentStreamKey:            "released",
+	AgentMetadataURLKey:       "",
 
 	// Log forward settings.
 	LogForwardEnabled: false,
@@ -556,6 +585,12 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[MaxRelationSettingsSize].(string); ok && v != "" {
+		if _, err := utils.ParseSize(v); err != nil {
+			return errors.Annotate(err, "invalid max relation settings size in model configuration")
+		}
+	}
+
 	if v, ok := cfg.defined[UpdateStatusHookInterval].(string); ok {
 		if f, err := time.ParseDuration(v); err != nil {
 			return errors.Annotate(err, "invalid update status hook interval in model configuration")
@@ -569,6 +604,28 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[ProvisionerHarvestUnknownGraceKey].(string); ok && v != "" {
+		if d, err := time.ParseDuration(v); err != nil {
+			return errors.Annotate(err, "invalid provisioner harvest unknown grace in model configuration")
+		} else if d < 0 {
+			return errors.Errorf("provisioner harvest unknown grace %v cannot be negative", d)
+		}
+	}
+
+	if v, ok := cfg.defined[ProvisionerStartTimeoutKey].(string); ok && v != "" {
+		if d, err := time.ParseDuration(v); err != nil {
+			return errors.Annotate(err, "invalid provisioner start timeout in model configuration")
+		} else if d < 0 {
+			return errors.Errorf("provisioner start timeout %v cannot be negative", d)
+		}
+	}
+
+	if v, ok := cfg.defined[ImageMetadataPublicKeyKey].(string); ok && v != "" {
+		if _, err := openpgp.ReadArmoredKeyRing(strings.NewReader(v)); err != nil {
+			return errors.Annotate(err, "invalid image metadata public key in model configuration")
+		}
+	}
+
 	if v, ok := cfg.defined[EgressSubnets].(string); ok && v != "" {
 		cidrs := strings.Split(v, ",")
 		for _, cidr := range cidrs {
@@ -912,6 +969,16 @@ func (c *Config) ImageMetadataURL() (string, bool) {
 	return "", false
 }
 
+// ImageMetadataPublicKey returns the armored public key used to verify
+// signed metadata found at the image metadata URL, and whether it has
+// been set.
+func (c *Config) ImageMetadataPublicKey() (string, bool) {
+	if key, ok := c.defined[ImageMetadataPublicKeyKey]; ok && key != "" {
+		return key.(string), true
+	}
+	return "", false
+}
+
 // Development returns whether the environment is in development mode.
 func (c *Config) Development() bool {
 	value, _ := c.defined["development"].(bool)
@@ -985,6 +1052,50 @@ func (c *Config) ProvisionerHarvestMode() HarvestMode {
 	}
 }
 
+// ProvisionerHarvestExclude returns the instance name prefixes of
+// instances that are managed outside of Juju. The provisioner never
+// treats such instances as unknown, whatever the harvest mode.
+func (c *Config) ProvisionerHarvestExclude() []string {
+	raw := c.asString(ProvisionerHarvestExcludeKey)
+	if raw == "" {
+		return []string{}
+	}
+	var result []string
+	for _, prefix := range strings.Split(raw, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			result = append(result, prefix)
+		}
+	}
+	return result
+}
+
+// ProvisionerHarvestUnknownGrace returns how long an instance must
+// have been unknown to the provisioner before it may be harvested.
+// Zero means unknown instances are harvested as soon as they are seen.
+func (c *Config) ProvisionerHarvestUnknownGrace() time.Duration {
+	raw := c.asString(ProvisionerHarvestUnknownGraceKey)
+	if raw == "" {
+		return 0
+	}
+	// Value has already been validated.
+	val, _ := time.ParseDuration(raw)
+	return val
+}
+
+// ProvisionerStartTimeout returns how long the provisioner keeps
+// retrying a machine that fails to start, measured from the first
+// attempt, before giving up on it until provisioning is retried by
+// hand. Zero means there is no limit.
+func (c *Config) ProvisionerStartTimeout() time.Duration {
+	raw := c.asString(ProvisionerStartTimeoutKey)
+	if raw == "" {
+		return 0
+	}
+	// Value has already been validated.
+	val, _ := time.ParseDuration(raw)
+	return val
+}
+
 // ImageStream returns the simplestreams stream
 // used to identify which image ids to search
 // when starting an instance.
@@ -1096,6 +1207,19 @@ func (c *Config) MaxActionResultsSizeMB() uint {
 	return uint(val)
 }
 
+// MaxRelationSettingsSizeMB is the maximum serialized size in MiB of a
+// unit's settings within a relation. Zero means there is no limit.
+func (c *Config) MaxRelationSettingsSizeMB() uint {
+	raw := c.asString(MaxRelationSettingsSize)
+	if raw == "" {
+		// Models created before the setting existed.
+		raw = DefaultRelationSettingsSize
+	}
+	// Value has already been validated.
+	val, _ := utils.ParseSize(raw)
+	return uint(val)
+}
+
 // UpdateStatusHookInterval is how often to run the charm
 // update-status hook.
 func (c *Config) UpdateStatusHookInterval() time.Duration {
@@ -1222,45 +1346,50 @@ var alwaysOptional = schema.Defaults{
 	StorageDefaultBlockSourceKey:      schema.Omit,
 	StorageDefaultFilesystemSourceKey: schema.Omit,
 
-	"firewall-mode":              schema.Omit,
-	"logging-config":             schema.Omit,
-	ProvisionerHarvestModeKey:    schema.Omit,
-	HTTPProxyKey:                 schema.Omit,
-	HTTPSProxyKey:                schema.Omit,
-	FTPProxyKey:                  schema.Omit,
-	NoProxyKey:                   schema.Omit,
-	AptHTTPProxyKey:              schema.Omit,
-	AptHTTPSProxyKey:             schema.Omit,
-	AptFTPProxyKey:               schema.Omit,
-	AptNoProxyKey:                schema.Omit,
-	"apt-mirror":                 schema.Omit,
-	AgentStreamKey:               schema.Omit,
-	ResourceTagsKey:              schema.Omit,
-	"cloudimg-base-url":          schema.Omit,
-	"enable-os-refresh-update":   schema.Omit,
-	"enable-os-upgrade":          schema.Omit,
-	"image-stream":               schema.Omit,
-	"image-metadata-url":         schema.Omit,
-	AgentMetadataURLKey:          schema.Omit,
-	"default-series":             schema.Omit,
-	"development":                schema.Omit,
-	"ssl-hostname-verification":  schema.Omit,
-	"proxy-ssh":                  schema.Omit,
-	"disable-network-management": schema.Omit,
-	IgnoreMachineAddresses:       schema.Omit,
-	AutomaticallyRetryHooks:      schema.Omit,
-	"test-mode":                  schema.Omit,
-	TransmitVendorMetricsKey:     schema.Omit,
-	NetBondReconfigureDelayKey:   schema.Omit,
-	ContainerNetworkingMethod:    schema.Omit,
-	MaxStatusHistoryAge:          schema.Omit,
-	MaxStatusHistorySize:         schema.Omit,
-	MaxActionResultsAge:          schema.Omit,
-	MaxActionResultsSize:         schema.Omit,
-	UpdateStatusHookInterval:     schema.Omit,
-	EgressSubnets:                schema.Omit,
-	FanConfig:                    schema.Omit,
-	CloudInitUserDataKey:         schema.Omit,
+	"firewall-mode":                   schema.Omit,
+	"logging-config":                  schema.Omit,
+	ProvisionerHarvestModeKey:         schema.Omit,
+	ProvisionerHarvestExcludeKey:      schema.Omit,
+	ProvisionerHarvestUnknownGraceKey: schema.Omit,
+	ProvisionerStartTimeoutKey:        schema.Omit,
+	HTTPProxyKey:                      schema.Omit,
+	HTTPSProxyKey:                     schema.Omit,
+	FTPProxyKey:                       schema.Omit,
+	NoProxyKey:                        schema.Omit,
+	AptHTTPProxyKey:                   schema.Omit,
+	AptHTTPSProxyKey:                  schema.Omit,
+	AptFTPProxyKey:                    schema.Omit,
+	AptNoProxyKey:                     schema.Omit,
+	"apt-mirror":                      schema.Omit,
+	AgentStreamKey:                    schema.Omit,
+	ResourceTagsKey:                   schema.Omit,
+	"cloudimg-base-url":               schema.Omit,
+	"enable-os-refresh-update":        schema.Omit,
+	"enable-os-upgrade":               schema.Omit,
+	"image-stream":                    schema.Omit,
+	"image-metadata-url":              schema.Omit,
+	ImageMetadataPublicKeyKey:         schema.Omit,
+	AgentMetadataURLKey:               schema.Omit,
+	"default-series":                  schema.Omit,
+	"development":                     schema.Omit,
+	"ssl-hostname-verification":       schema.Omit,
+	"proxy-ssh":                       schema.Omit,
+	"disable-network-management":      schema.Omit,
+	IgnoreMachineAddresses:            schema.Omit,
+	AutomaticallyRetryHooks:           schema.Omit,
+	"test-mode":                       schema.Omit,
+	TransmitVendorMetricsKey:          schema.Omit,
+	NetBondReconfigureDelayKey:        schema.Omit,
+	ContainerNetworkingMethod:         schema.Omit,
+	MaxStatusHistoryAge:               schema.Omit,
+	MaxStatusHistorySize:              schema.Omit,
+	MaxActionResultsAge:               schema.Omit,
+	MaxActionResultsSize:              schema.Omit,
+	MaxRelationSettingsSize:           schema.Omit,
+	UpdateStatusHookInterval:          schema.Omit,
+	EgressSubnets:                     schema.Omit,
+	FanConfig:                         schema.Omit,
+	CloudInitUserDataKey:              schema.Omit,
 }
 
 func allowEmpty(attr string) bool {
@@ -1546,6 +1675,11 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	ImageMetadataPublicKeyKey: {
+		Description: "The armored public key used to verify signed metadata at image-metadata-url, in place of the client's simplestreams public key",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"image-stream": {
 		Description: `The simplestreams stream used to identify which image ids to search when starting an instance.`,
 		Type:        environschema.Tstring,
@@ -1575,6 +1709,21 @@ global or per instance security groups.`,
 		Values:      []interface{}{"all", "none", "unknown", "destroyed"},
 		Group:       environschema.EnvironGroup,
 	},
+	ProvisionerHarvestExcludeKey: {
+		Description: "Instance name prefixes (comma-separated) of instances managed outside of Juju, which are never harvested",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ProvisionerHarvestUnknownGraceKey: {
+		Description: "How long an unknown instance must have been seen before it is harvested, in human-readable time format (default 0s)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ProvisionerStartTimeoutKey: {
+		Description: "How long to keep retrying a machine that fails to start before giving up until provisioning is retried, in human-readable time format (default 0s, no limit)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"proxy-ssh": {
 		// default: true
 		Description: `Whether SSH commands should be proxied through the API server`,
@@ -1686,6 +1835,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	MaxRelationSettingsSize: {
+		Description: "The maximum size of a unit's settings within a relation, in human-readable memory format (default 1M, 0 for no limit)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	UpdateStatusHookInterval: {
 		Description: "How often to run the charm update-status hook, in human-readable time format (default 5m, range 1-60m)",
 		Type:        environschema.Tstring,
@@ -22,6 +22,7 @@ import (
 
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/juju/keys"
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/testing"
 )
@@ -1208,6 +1209,105 @@ func (s *ConfigSuite) TestEgressSubnets(c *gc.C) {
 	c.Assert(cfg.EgressSubnets(), gc.DeepEquals, []string{"10.0.0.1/32", "192.168.1.1/16"})
 }
 
+func (s *ConfigSuite) TestProvisionerHarvestExclude(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		config.ProvisionerHarvestExcludeKey: "manual-, juju-legacy-, ",
+	})
+	c.Assert(cfg.ProvisionerHarvestExclude(), gc.DeepEquals, []string{"manual-", "juju-legacy-"})
+}
+
+func (s *ConfigSuite) TestProvisionerHarvestExcludeDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.ProvisionerHarvestExclude(), gc.HasLen, 0)
+}
+
+func (s *ConfigSuite) TestProvisionerHarvestUnknownGrace(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		config.ProvisionerHarvestUnknownGraceKey: "10m",
+	})
+	c.Assert(cfg.ProvisionerHarvestUnknownGrace(), gc.Equals, 10*time.Minute)
+}
+
+func (s *ConfigSuite) TestProvisionerHarvestUnknownGraceDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.ProvisionerHarvestUnknownGrace(), gc.Equals, time.Duration(0))
+}
+
+func (s *ConfigSuite) TestProvisionerHarvestUnknownGraceNegative(c *gc.C) {
+	_, err := config.New(config.UseDefaults, testing.Attrs{
+		"type": "my-type", "name": "my-name",
+		"uuid":                                   testing.ModelTag.Id(),
+		config.ProvisionerHarvestUnknownGraceKey: "-1m",
+	})
+	c.Assert(err, gc.ErrorMatches, `provisioner harvest unknown grace -1m0s cannot be negative`)
+}
+
+func (s *ConfigSuite) TestProvisionerStartTimeout(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		config.ProvisionerStartTimeoutKey: "30m",
+	})
+	c.Assert(cfg.ProvisionerStartTimeout(), gc.Equals, 30*time.Minute)
+}
+
+func (s *ConfigSuite) TestProvisionerStartTimeoutDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.ProvisionerStartTimeout(), gc.Equals, time.Duration(0))
+}
+
+func (s *ConfigSuite) TestProvisionerStartTimeoutNegative(c *gc.C) {
+	_, err := config.New(config.UseDefaults, testing.Attrs{
+		"type": "my-type", "name": "my-name",
+		"uuid":                            testing.ModelTag.Id(),
+		config.ProvisionerStartTimeoutKey: "-1m",
+	})
+	c.Assert(err, gc.ErrorMatches, `provisioner start timeout -1m0s cannot be negative`)
+}
+
+func (s *ConfigSuite) TestMaxRelationSettingsSize(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		config.MaxRelationSettingsSize: "2M",
+	})
+	c.Assert(cfg.MaxRelationSettingsSizeMB(), gc.Equals, uint(2))
+}
+
+func (s *ConfigSuite) TestMaxRelationSettingsSizeDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.MaxRelationSettingsSizeMB(), gc.Equals, uint(1))
+}
+
+func (s *ConfigSuite) TestMaxRelationSettingsSizeInvalid(c *gc.C) {
+	_, err := config.New(config.UseDefaults, testing.Attrs{
+		"type": "my-type", "name": "my-name",
+		"uuid":                         testing.ModelTag.Id(),
+		config.MaxRelationSettingsSize: "lots",
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid max relation settings size in model configuration: .*`)
+}
+
+func (s *ConfigSuite) TestImageMetadataPublicKey(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		config.ImageMetadataPublicKeyKey: keys.JujuPublicKey,
+	})
+	key, ok := cfg.ImageMetadataPublicKey()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(key, gc.Equals, keys.JujuPublicKey)
+}
+
+func (s *ConfigSuite) TestImageMetadataPublicKeyDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	_, ok := cfg.ImageMetadataPublicKey()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestImageMetadataPublicKeyInvalid(c *gc.C) {
+	_, err := config.New(config.UseDefaults, testing.Attrs{
+		"type": "my-type", "name": "my-name",
+		"uuid":                           testing.ModelTag.Id(),
+		config.ImageMetadataPublicKeyKey: "not a key",
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid image metadata public key in model configuration: .*`)
+}
+
 func (s *ConfigSuite) TestCloudInitUserDataFromEnvironment(c *gc.C) {
 	cfg := newTestConfig(c, testing.Attrs{
 		config.CloudInitUserDataKey: validCloudInitUserData,
@@ -41,3 +41,28 @@ func IsAvailabilityZoneIndependent(err error) bool {
 	}
 	return false
 }
+
+// RetryableError provides an interface for compute providers to
+// indicate whether or not retrying a failed operation may succeed.
+type RetryableError interface {
+	error
+
+	// Retryable reports whether or not retrying the failed
+	// operation may succeed.
+	Retryable() bool
+}
+
+// IsRetryable reports whether or not the given error, or its cause,
+// may go away if the failed operation is retried. Juju uses this to
+// decide whether or not to keep retrying a failed StartInstance; for
+// example, an exhausted quota will not go away by itself.
+//
+// If the error implements RetryableError, then the result of calling
+// its Retryable method will be returned; otherwise this function
+// returns true.
+func IsRetryable(err error) bool {
+	if err, ok := errors.Cause(err).(RetryableError); ok {
+		return err.Retryable()
+	}
+	return true
+}
@@ -90,7 +90,10 @@ func ImageMetadataSources(env Environ) ([]simplestreams.DataSource, error) {
 		if !config.SSLHostnameVerification() {
 			verify = utils.NoVerifySSLHostnames
 		}
-		publicKey, _ := simplestreams.UserPublicSigningKey()
+		publicKey, ok := config.ImageMetadataPublicKey()
+		if !ok {
+			publicKey, _ = simplestreams.UserPublicSigningKey()
+		}
 		sources = append(sources, simplestreams.NewURLSignedDataSource("image-metadata-url", userURL, publicKey, verify, simplestreams.SPECIFIC_CLOUD_DATA, false))
 	}
 
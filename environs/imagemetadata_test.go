@@ -4,6 +4,9 @@
 package environs_test
 
 import (
+	"bytes"
+	"strings"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -34,7 +37,11 @@ func (s *ImageMetadataSuite) TearDownTest(c *gc.C) {
 }
 
 func (s *ImageMetadataSuite) env(c *gc.C, imageMetadataURL, stream string) environs.Environ {
-	attrs := dummy.SampleConfig()
+	return s.envWithAttrs(c, imageMetadataURL, stream, nil)
+}
+
+func (s *ImageMetadataSuite) envWithAttrs(c *gc.C, imageMetadataURL, stream string, extra testing.Attrs) environs.Environ {
+	attrs := dummy.SampleConfig().Merge(extra)
 	if stream != "" {
 		attrs = attrs.Merge(testing.Attrs{
 			"image-stream": stream,
@@ -81,6 +88,33 @@ func (s *ImageMetadataSuite) TestImageMetadataURLs(c *gc.C) {
 	})
 }
 
+func (s *ImageMetadataSuite) TestImageMetadataURLsPublicKey(c *gc.C) {
+	env := s.envWithAttrs(c, "config-image-metadata-url", "", testing.Attrs{
+		"image-metadata-public-key": sstesting.SignedMetadataPublicKey,
+	})
+	sources, err := environs.ImageMetadataSources(env)
+	c.Assert(err, jc.ErrorIsNil)
+	sstesting.AssertExpectedSources(c, sources, []sstesting.SourceDetails{
+		{"config-image-metadata-url/", sstesting.SignedMetadataPublicKey},
+		{"https://streams.canonical.com/juju/images/releases/", keys.JujuPublicKey},
+		{"http://cloud-images.ubuntu.com/releases/", imagemetadata.SimplestreamsImagesPublicKey},
+	})
+
+	// Metadata signed with the configured key is accepted by the
+	// image-metadata-url source; the official sources reject it.
+	signed, err := simplestreams.Encode(
+		strings.NewReader("private metadata"), sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := simplestreams.DecodeCheckSignature(bytes.NewReader(signed), sources[0].PublicSigningKey())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.TrimSpace(string(data)), gc.Equals, "private metadata")
+	for _, source := range sources[1:] {
+		_, err := simplestreams.DecodeCheckSignature(bytes.NewReader(signed), source.PublicSigningKey())
+		c.Assert(err, gc.NotNil)
+	}
+}
+
 func (s *ImageMetadataSuite) TestImageMetadataURLsRegisteredFuncs(c *gc.C) {
 	environs.RegisterImageDataSourceFunc("id0", func(environs.Environ) (simplestreams.DataSource, error) {
 		return simplestreams.NewURLDataSource("id0", "betwixt/releases", utils.NoVerifySSLHostnames, simplestreams.DEFAULT_CLOUD_DATA, false), nil
@@ -235,9 +235,9 @@ func (s *cmdControllerSuite) testControllerDestroy(c *gc.C, forceAPI bool) {
 		defer close(done)
 		a := testing.LongAttempt.Start()
 		for a.Next() {
-			err := s.State.Cleanup()
+			_, err := s.State.Cleanup()
 			c.Check(err, jc.ErrorIsNil)
-			err = st.Cleanup()
+			_, err = st.Cleanup()
 			c.Check(err, jc.ErrorIsNil)
 			err = st.ProcessDyingModel()
 			if errors.Cause(err) != state.ErrModelNotDying {
@@ -46,6 +46,15 @@ type ZonedEnviron interface {
 	DeriveAvailabilityZones(args environs.StartInstanceParams) ([]string, error)
 }
 
+// ZoneCapacityEnviron is an optional interface that a ZonedEnviron may
+// implement to report the relative capacity of its availability zones,
+// so that larger zones can be given proportionally more machines.
+type ZoneCapacityEnviron interface {
+	// AvailabilityZoneCapacities returns the relative capacity of
+	// each availability zone, keyed by zone name.
+	AvailabilityZoneCapacities() (map[string]float64, error)
+}
+
 // AvailabilityZoneInstances describes an availability zone and
 // a set of instances in that zone.
 type AvailabilityZoneInstances struct {
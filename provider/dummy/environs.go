@@ -976,7 +976,7 @@ func (e *environ) DestroyController(controllerUUID string) error {
 // ConstraintsValidator is defined on the Environs interface.
 func (e *environ) ConstraintsValidator() (constraints.Validator, error) {
 	validator := constraints.NewValidator()
-	validator.RegisterUnsupported([]string{constraints.CpuPower, constraints.VirtType})
+	validator.RegisterUnsupported([]string{constraints.CpuPower, constraints.VirtType, constraints.Zones})
 	validator.RegisterConflicts([]string{constraints.InstanceType}, []string{constraints.Mem})
 	validator.RegisterVocabulary(constraints.Arch, []string{arch.AMD64, arch.ARM64, arch.I386, arch.PPC64EL})
 	return validator, nil
@@ -30,6 +30,16 @@ func (inst *ec2Instance) Id() instance.Id {
 	return instance.Id(inst.InstanceId)
 }
 
+// Name returns the value of the instance's Name tag, if any.
+func (inst *ec2Instance) Name() string {
+	for _, tag := range inst.Tags {
+		if tag.Key == tagName {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
 func (inst *ec2Instance) Status() instance.InstanceStatus {
 	// pending | running | shutting-down | terminated | stopping | stopped
 	jujuStatus := status.Pending
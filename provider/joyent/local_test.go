@@ -377,10 +377,10 @@ func (s *localServerSuite) TestConstraintsValidator(c *gc.C) {
 	env := s.Prepare(c)
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 tags=bar cpu-power=10 virt-type=kvm")
+	cons := constraints.MustParse("arch=amd64 tags=bar cpu-power=10 virt-type=kvm zones=az1")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "tags", "virt-type"})
+	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "tags", "virt-type", "zones"})
 }
 
 func (s *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
@@ -98,6 +98,7 @@ func (s *environPolSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 		"cores=2",
 		"cpu-power=250",
 		"virt-type=kvm",
+		"zones=az1",
 	}, " "))
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
@@ -108,6 +109,7 @@ func (s *environPolSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 		"cores",
 		"cpu-power",
 		"virt-type",
+		"zones",
 	}
 	c.Check(unsupported, jc.SameContents, expected)
 }
@@ -31,9 +31,34 @@ func (environ *maasEnviron) ConstraintsValidator() (constraints.Validator, error
 		return nil, err
 	}
 	validator.RegisterVocabulary(constraints.Arch, supportedArches)
+	zoneNames, err := environ.zoneNames()
+	if err != nil && !errors.IsNotImplemented(err) {
+		return nil, errors.Trace(err)
+	}
+	if len(zoneNames) > 0 {
+		// Zones may be given with a "^" prefix to exclude them.
+		vocab := make([]string, 0, 2*len(zoneNames))
+		for _, name := range zoneNames {
+			vocab = append(vocab, name, "^"+name)
+		}
+		validator.RegisterVocabulary(constraints.Zones, vocab)
+	}
 	return validator, nil
 }
 
+// zoneNames returns the names of the availability zones known to MAAS.
+func (environ *maasEnviron) zoneNames() ([]string, error) {
+	zones, err := environ.AvailabilityZones()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(zones))
+	for i, zone := range zones {
+		names[i] = zone.Name()
+	}
+	return names, nil
+}
+
 // convertConstraints converts the given constraints into an url.Values object
 // suitable to pass to MAAS when acquiring a node. CpuPower is ignored because
 // it cannot be translated into something meaningful for MAAS right now.
@@ -52,6 +77,7 @@ func convertConstraints(cons constraints.Value) url.Values {
 		params.Add("mem", fmt.Sprintf("%d", *cons.Mem))
 	}
 	convertTagsToParams(params, cons.Tags)
+	convertZonesToParams(params, cons.Zones)
 	if cons.CpuPower != nil {
 		logger.Warningf("ignoring unsupported constraint 'cpu-power'")
 	}
@@ -80,6 +106,17 @@ func convertConstraints2(cons constraints.Value) gomaasapi.AllocateMachineArgs {
 			params.NotTags = negatives
 		}
 	}
+	if cons.Zones != nil {
+		// MAAS 2 accepts only a single zone to allocate in; when
+		// more are given, acquireNode2 excludes all the others.
+		positives, negatives := parseDelimitedValues(*cons.Zones)
+		if len(positives) == 1 {
+			params.Zone = positives[0]
+		}
+		if len(negatives) > 0 {
+			params.NotInZone = negatives
+		}
+	}
 	if cons.CpuPower != nil {
 		logger.Warningf("ignoring unsupported constraint 'cpu-power'")
 	}
@@ -103,6 +140,43 @@ func convertTagsToParams(params url.Values, tags *[]string) {
 	}
 }
 
+// convertZonesToParams converts a list of positive/negative zones from
+// constraints into repeated "zone" and "not_in_zone" arguments to acquire.
+// MAAS treats repeated "zone" arguments as alternatives, so a node in any
+// of the positive zones can be selected.
+func convertZonesToParams(params url.Values, zones *[]string) {
+	if zones == nil || len(*zones) == 0 {
+		return
+	}
+	positives, negatives := parseDelimitedValues(*zones)
+	for _, zone := range positives {
+		params.Add("zone", zone)
+	}
+	for _, zone := range negatives {
+		params.Add("not_in_zone", zone)
+	}
+}
+
+// zoneAllowed reports whether the zones constraint in cons, if any,
+// permits a node in the named zone.
+func zoneAllowed(cons constraints.Value, zoneName string) bool {
+	for _, name := range cons.ExcludeZones() {
+		if name == zoneName {
+			return false
+		}
+	}
+	positives := cons.IncludeZones()
+	if len(positives) == 0 {
+		return true
+	}
+	for _, name := range positives {
+		if name == zoneName {
+			return true
+		}
+	}
+	return false
+}
+
 // convertSpacesFromConstraints extracts spaces from constraints and converts
 // them to two lists of positive and negative spaces.
 func convertSpacesFromConstraints(spaces *[]string) ([]string, []string) {
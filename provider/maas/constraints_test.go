@@ -36,6 +36,12 @@ func (*environSuite) TestConvertConstraints(c *gc.C) {
 			"tags":     {"tag1,tag2"},
 			"not_tags": {"tag3,tag4"},
 		},
+	}, {
+		cons: constraints.Value{Zones: stringslicep("az1", "^az2", "az3", "^az4")},
+		expected: url.Values{
+			"zone":        {"az1", "az3"},
+			"not_in_zone": {"az2", "az4"},
+		},
 	}, { // CpuPower is ignored.
 		cons:     constraints.Value{CpuPower: uint64p(1024)},
 		expected: url.Values{},
@@ -90,6 +96,15 @@ func (*environSuite) TestConvertConstraints2(c *gc.C) {
 			Tags:    []string{"tag1", "tag2"},
 			NotTags: []string{"tag3", "tag4"},
 		},
+	}, {
+		cons: constraints.Value{Zones: stringslicep("az1", "^az2", "^az3")},
+		expected: gomaasapi.AllocateMachineArgs{
+			Zone:      "az1",
+			NotInZone: []string{"az2", "az3"},
+		},
+	}, { // Multiple positive zones are handled by acquireNode2.
+		cons:     constraints.Value{Zones: stringslicep("az1", "az2")},
+		expected: gomaasapi.AllocateMachineArgs{},
 	}, { // CpuPower is ignored.
 		cons:     constraints.Value{CpuPower: uint64p(1024)},
 		expected: gomaasapi.AllocateMachineArgs{},
@@ -124,6 +139,26 @@ func (*environSuite) TestConvertConstraints2(c *gc.C) {
 	}
 }
 
+func (*environSuite) TestZoneAllowed(c *gc.C) {
+	for i, test := range []struct {
+		zones   string
+		zone    string
+		allowed bool
+	}{
+		{"", "az1", true},
+		{"zones=", "az1", true},
+		{"zones=az1,az2", "az2", true},
+		{"zones=az1,az2", "az3", false},
+		{"zones=^az1", "az1", false},
+		{"zones=^az1", "az2", true},
+		{"zones=az1,^az1", "az1", false},
+	} {
+		c.Logf("test #%d: %q in %q", i, test.zone, test.zones)
+		cons := constraints.MustParse(test.zones)
+		c.Check(zoneAllowed(cons, test.zone), gc.Equals, test.allowed)
+	}
+}
+
 var nilStringSlice []string
 
 func (*environSuite) TestConvertTagsToParams(c *gc.C) {
@@ -265,6 +300,19 @@ func (suite *environSuite) TestAcquireNodeByName(c *gc.C) {
 	c.Assert(nodeName, gc.Equals, "host0")
 }
 
+func (suite *environSuite) TestAcquireNodeByZone(c *gc.C) {
+	env := suite.makeEnviron()
+	suite.testMAASObject.TestServer.NewNode(`{"system_id": "node0", "hostname": "host0", "zone": "bar"}`)
+
+	_, err := env.acquireNode("", "bar", "", constraints.Value{}, nil, nil)
+
+	c.Check(err, jc.ErrorIsNil)
+	values := suite.testMAASObject.TestServer.NodeOperationRequestValues()["node0"][0]
+	c.Assert(values.Get("zone"), gc.Equals, "bar")
+	_, found := values["name"]
+	c.Assert(found, jc.IsFalse)
+}
+
 func (suite *environSuite) TestAcquireNodeTakesConstraintsIntoAccount(c *gc.C) {
 	env := suite.makeEnviron()
 	suite.testMAASObject.TestServer.NewNode(
@@ -13,10 +13,12 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/juju/errors"
 	"github.com/juju/gomaasapi"
 	"github.com/juju/utils"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/os"
 	"github.com/juju/utils/series"
 	"github.com/juju/utils/set"
@@ -80,8 +82,14 @@ type maasEnviron struct {
 	cloud environs.CloudSpec
 	uuid  string
 
-	// archMutex gates access to supportedArchitectures
-	archMutex sync.Mutex
+	// archMutex gates access to supportedArchitectures and
+	// supportedArchitecturesTime.
+	archMutex                  sync.Mutex
+	supportedArchitectures     []string
+	supportedArchitecturesTime time.Time
+
+	// clock is used to expire cached state.
+	clock clock.Clock
 
 	// ecfgMutex protects the *Unlocked fields below.
 	ecfgMutex sync.Mutex
@@ -123,6 +131,7 @@ func NewEnviron(cloud environs.CloudSpec, cfg *config.Config, getCaps MaasCapabi
 		uuid:            cfg.UUID(),
 		cloud:           cloud,
 		GetCapabilities: getCaps,
+		clock:           clock.WallClock,
 	}
 	err := env.SetConfig(cfg)
 	if err != nil {
@@ -307,14 +316,36 @@ func (env *maasEnviron) SetConfig(cfg *config.Config) error {
 	return nil
 }
 
+// supportedArchitecturesTTL is how long the architectures supported by
+// MAAS are cached before being queried again.
+var supportedArchitecturesTTL = 10 * time.Minute
+
 func (env *maasEnviron) getSupportedArchitectures() ([]string, error) {
 	env.archMutex.Lock()
 	defer env.archMutex.Unlock()
+	now := env.clock.Now()
+	if env.supportedArchitectures != nil && now.Sub(env.supportedArchitecturesTime) < supportedArchitecturesTTL {
+		return env.supportedArchitectures, nil
+	}
 	fetchArchitectures := env.allArchitecturesWithFallback
 	if env.usingMAAS2() {
 		fetchArchitectures = env.allArchitectures2
 	}
-	return fetchArchitectures()
+	architectures, err := fetchArchitectures()
+	if err != nil {
+		return nil, err
+	}
+	env.supportedArchitectures = architectures
+	env.supportedArchitecturesTime = now
+	return architectures, nil
+}
+
+// RefreshSupportedArchitectures discards the cached architectures
+// supported by MAAS, so that they are queried again on next use.
+func (env *maasEnviron) RefreshSupportedArchitectures() {
+	env.archMutex.Lock()
+	defer env.archMutex.Unlock()
+	env.supportedArchitectures = nil
 }
 
 // SupportsSpaces is specified on environs.Networking.
@@ -772,6 +803,10 @@ func (environ *maasEnviron) acquireNode2(
 	acquireParams.AgentName = environ.uuid
 	if zoneName != "" {
 		acquireParams.Zone = zoneName
+	} else if positives := cons.IncludeZones(); len(positives) > 1 {
+		if err := environ.excludeOtherZones2(&acquireParams, positives); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 	if nodeName != "" {
 		acquireParams.Hostname = nodeName
@@ -791,6 +826,24 @@ func (environ *maasEnviron) acquireNode2(
 	}, nil
 }
 
+// excludeOtherZones2 updates acquireParams to exclude every zone other
+// than those given, as MAAS 2 allows allocating in only a single zone.
+func (environ *maasEnviron) excludeOtherZones2(acquireParams *gomaasapi.AllocateMachineArgs, zones []string) error {
+	zoneNames, err := environ.zoneNames()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	allowed := set.NewStrings(zones...)
+	excluded := set.NewStrings(acquireParams.NotInZone...)
+	for _, name := range zoneNames {
+		if !allowed.Contains(name) {
+			excluded.Add(name)
+		}
+	}
+	acquireParams.NotInZone = excluded.SortedValues()
+	return nil
+}
+
 // acquireNode allocates a node from the MAAS.
 func (environ *maasEnviron) acquireNode(
 	nodeName, zoneName, systemId string,
@@ -822,7 +875,8 @@ func (environ *maasEnviron) acquireNode(
 	addStorage(acquireParams, volumes)
 	acquireParams.Add("agent_name", environ.uuid)
 	if zoneName != "" {
-		acquireParams.Add("zone", zoneName)
+		// An explicit zone replaces any from the zones constraint.
+		acquireParams.Set("zone", zoneName)
 	}
 	if nodeName != "" {
 		acquireParams.Add("name", nodeName)
@@ -930,6 +984,10 @@ func (environ *maasEnviron) StartInstance(
 		if err := common.ValidateAvailabilityZone(environ, availabilityZone); err != nil {
 			return nil, errors.Trace(err)
 		}
+		if !zoneAllowed(args.Constraints, availabilityZone) {
+			// Not zone independent, so another zone will be tried.
+			return nil, errors.Errorf("zone %q excluded by zones constraint", availabilityZone)
+		}
 		logger.Debugf("attempting to acquire node in zone %q", availabilityZone)
 	}
 
@@ -1403,18 +1461,21 @@ func (environ *maasEnviron) releaseNodes1(nodes gomaasapi.MAASObject, ids url.Va
 		return nil
 	}
 
-	var lastErr error
-	for _, id := range ids["nodes"] {
-		idFilter := url.Values{}
-		idFilter.Add("nodes", id)
-		err := environ.releaseNodes1(nodes, idFilter, false)
-		if err != nil {
-			lastErr = err
-			logger.Errorf("error while releasing node %v (%v)", id, err)
+	// MAAS names the unknown nodes in the error; if we can tell which
+	// they are, drop them and retry with the remainder in one request.
+	unknown, remainder := unknownNodeIds(ids["nodes"], maasErr.BodyMessage)
+	if len(unknown) > 0 {
+		logger.Infof("ignoring unknown nodes %v while releasing nodes", unknown)
+		if len(remainder) == 0 {
+			return nil
 		}
+		return environ.releaseNodes1(nodes, url.Values{"nodes": remainder}, true)
 	}
-	return errors.Trace(lastErr)
-
+	return releaseNodesInParallel(ids["nodes"], func(id string) error {
+		idFilter := url.Values{}
+		idFilter.Add("nodes", id)
+		return environ.releaseNodes1(nodes, idFilter, false)
+	})
 }
 
 func (environ *maasEnviron) releaseNodes2(ids []instance.Id, recurse bool) error {
@@ -1445,23 +1506,74 @@ func (environ *maasEnviron) releaseNodes2(ids []instance.Id, recurse bool) error
 			// this node has already been released and we're golden
 			return nil
 		}
-		return environ.releaseNodesIndividually(ids)
+		unknown, remainder := unknownNodeIds(instanceIdsToSystemIDs(ids), err.Error())
+		if len(unknown) > 0 {
+			logger.Infof("ignoring unknown nodes %v while releasing nodes", unknown)
+			if len(remainder) == 0 {
+				return nil
+			}
+			return environ.releaseNodes2(systemIDsToInstanceIds(remainder), true)
+		}
+		return releaseNodesInParallel(instanceIdsToSystemIDs(ids), func(id string) error {
+			return environ.releaseNodes2([]instance.Id{instance.Id(id)}, false)
+		})
 
 	default:
 		return errors.Annotatef(err, "cannot release nodes")
 	}
 }
 
-func (environ *maasEnviron) releaseNodesIndividually(ids []instance.Id) error {
-	var lastErr error
+// unknownNodeIds splits ids into those named in message, the body of
+// an error returned by MAAS when rejecting a release because some of
+// the nodes are unknown, and the remainder.
+func unknownNodeIds(ids []string, message string) (unknown, remainder []string) {
+	named := set.NewStrings(strings.FieldsFunc(message, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.')
+	})...)
 	for _, id := range ids {
-		err := environ.releaseNodes2([]instance.Id{id}, false)
+		if named.Contains(id) {
+			unknown = append(unknown, id)
+		} else {
+			remainder = append(remainder, id)
+		}
+	}
+	return unknown, remainder
+}
+
+// releaseNodesInParallel calls release for each of the given node ids
+// concurrently. The returned error names every node that could not be
+// released.
+func releaseNodesInParallel(ids []string, release func(id string) error) error {
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			errs[i] = release(id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
 		if err != nil {
-			lastErr = err
-			logger.Errorf("error while releasing node %v (%v)", id, err)
+			logger.Errorf("error while releasing node %v (%v)", ids[i], err)
+			failed = append(failed, fmt.Sprintf("%s (%v)", ids[i], err))
 		}
 	}
-	return errors.Trace(lastErr)
+	if len(failed) > 0 {
+		return errors.Errorf("cannot release nodes: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func systemIDsToInstanceIds(systemIDs []string) []instance.Id {
+	ids := make([]instance.Id, len(systemIDs))
+	for index, id := range systemIDs {
+		ids[index] = instance.Id(id)
+	}
+	return ids
 }
 
 func instanceIdsToSystemIDs(ids []instance.Id) []string {
@@ -2003,7 +2115,33 @@ func checkNotFound(subnetIdSet map[string]bool) error {
 
 // AllInstances returns all the instance.Instance in this provider.
 func (environ *maasEnviron) AllInstances() ([]instance.Instance, error) {
-	return environ.acquiredInstances(nil)
+	return environ.allInstances(false)
+}
+
+// AllRunningInstances returns all the instance.Instance in this provider,
+// omitting any acquired node that MAAS reports as powered off. The
+// provisioner uses it to avoid harvesting powered off nodes.
+func (environ *maasEnviron) AllRunningInstances() ([]instance.Instance, error) {
+	return environ.allInstances(true)
+}
+
+// allInstances lists the acquired nodes. If runningOnly is true, nodes
+// that MAAS reports as powered off are left out; nodes whose power state
+// is unknown are kept, since MAAS cannot query every power type.
+func (environ *maasEnviron) allInstances(runningOnly bool) ([]instance.Instance, error) {
+	instances, err := environ.acquiredInstances(nil)
+	if err != nil || !runningOnly {
+		return instances, err
+	}
+	running := make([]instance.Instance, 0, len(instances))
+	for _, inst := range instances {
+		if inst.(maasInstance).powerState() == powerStateOff {
+			logger.Debugf("ignoring powered off instance %s", inst.Id())
+			continue
+		}
+		running = append(running, inst)
+	}
+	return running, nil
 }
 
 // Storage is defined by the Environ interface.
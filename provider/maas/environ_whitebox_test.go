@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"sync"
 
 	"github.com/juju/errors"
 	"github.com/juju/gomaasapi"
@@ -115,6 +116,26 @@ func (suite *environSuite) TestAllInstancesReturnsEmptySliceIfNoInstance(c *gc.C
 	c.Check(instances, gc.HasLen, 0)
 }
 
+func (suite *environSuite) TestAllRunningInstancesOmitsPoweredOffNodes(c *gc.C) {
+	on := suite.addNode(`{"system_id": "test-on", "power_state": "on"}`)
+	suite.addNode(`{"system_id": "test-off", "power_state": "off"}`)
+	unknown := suite.addNode(`{"system_id": "test-unknown", "power_state": "unknown"}`)
+	unreported := suite.addNode(`{"system_id": "test-unreported"}`)
+	env := suite.makeEnviron()
+
+	all, err := env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(all, gc.HasLen, 4)
+
+	running, err := env.AllRunningInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	ids := make([]instance.Id, len(running))
+	for i, inst := range running {
+		ids[i] = inst.Id()
+	}
+	c.Check(ids, jc.SameContents, []instance.Id{on, unknown, unreported})
+}
+
 func (suite *environSuite) TestInstancesReturnsErrorIfPartialInstances(c *gc.C) {
 	known := suite.addNode(allocatedNode)
 	suite.addNode(`{"system_id": "test2"}`)
@@ -261,8 +282,11 @@ func (suite *environSuite) TestStopInstancesIgnoresConflict(c *gc.C) {
 }
 
 func (suite *environSuite) TestStopInstancesIgnoresMissingNodeAndRecurses(c *gc.C) {
+	var mu sync.Mutex
 	attemptedNodes := [][]string{}
 	releaseNodes := func(nodes gomaasapi.MAASObject, ids url.Values) error {
+		mu.Lock()
+		defer mu.Unlock()
 		attemptedNodes = append(attemptedNodes, ids["nodes"])
 		return gomaasapi.ServerError{StatusCode: 404}
 	}
@@ -271,10 +295,56 @@ func (suite *environSuite) TestStopInstancesIgnoresMissingNodeAndRecurses(c *gc.
 	err := env.StopInstances("test1", "test2")
 	c.Assert(err, jc.ErrorIsNil)
 
-	expectedNodes := [][]string{{"test1", "test2"}, {"test1"}, {"test2"}}
+	c.Assert(attemptedNodes, gc.HasLen, 3)
+	c.Assert(attemptedNodes[0], gc.DeepEquals, []string{"test1", "test2"})
+	c.Assert(attemptedNodes[1:], jc.SameContents, [][]string{{"test1"}, {"test2"}})
+}
+
+func (suite *environSuite) TestStopInstancesDropsUnknownNodesAndRetries(c *gc.C) {
+	attemptedNodes := [][]string{}
+	releaseNodes := func(nodes gomaasapi.MAASObject, ids url.Values) error {
+		attemptedNodes = append(attemptedNodes, ids["nodes"])
+		if len(attemptedNodes) == 1 {
+			return gomaasapi.ServerError{
+				StatusCode:  400,
+				BodyMessage: "Unknown node(s): test2.",
+			}
+		}
+		return nil
+	}
+	suite.PatchValue(&ReleaseNodes, releaseNodes)
+	env := suite.makeEnviron()
+	err := env.StopInstances("test1", "test2", "test3")
+	c.Assert(err, jc.ErrorIsNil)
+
+	expectedNodes := [][]string{{"test1", "test2", "test3"}, {"test1", "test3"}}
 	c.Assert(attemptedNodes, gc.DeepEquals, expectedNodes)
 }
 
+func (suite *environSuite) TestStopInstancesReturnsOnlyGenuineFailures(c *gc.C) {
+	var mu sync.Mutex
+	released := []string{}
+	releaseNodes := func(nodes gomaasapi.MAASObject, ids url.Values) error {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case len(ids["nodes"]) > 1:
+			return gomaasapi.ServerError{StatusCode: 404}
+		case ids.Get("nodes") == "test1":
+			return gomaasapi.ServerError{StatusCode: 404}
+		case ids.Get("nodes") == "test3":
+			return errors.New("boom")
+		}
+		released = append(released, ids["nodes"]...)
+		return nil
+	}
+	suite.PatchValue(&ReleaseNodes, releaseNodes)
+	env := suite.makeEnviron()
+	err := env.StopInstances("test1", "test2", "test3")
+	c.Assert(err, gc.ErrorMatches, `cannot release nodes: test3 \(cannot release nodes: boom\)`)
+	c.Assert(released, gc.DeepEquals, []string{"test2"})
+}
+
 func (suite *environSuite) TestStopInstancesReturnsUnexpectedMAASError(c *gc.C) {
 	releaseNodes := func(nodes gomaasapi.MAASObject, ids url.Values) error {
 		return gomaasapi.ServerError{StatusCode: 405}
@@ -452,6 +522,19 @@ func (suite *environSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid constraint value: arch=ppc64el\nvalid values are: \\[amd64 armhf\\]")
 }
 
+func (suite *environSuite) TestConstraintsValidatorZonesVocab(c *gc.C) {
+	suite.testMAASObject.TestServer.AddBootImage("uuid-0", `{"architecture": "amd64", "release": "trusty"}`)
+	suite.testMAASObject.TestServer.AddZone("zone1", "the grass is greener in zone1")
+	suite.testMAASObject.TestServer.AddZone("zone2", "")
+	env := suite.makeEnviron()
+	validator, err := env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("zones=zone1,^zone2"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("zones=zone3"))
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: zones=zone3\nvalid values are: .*")
+}
+
 func (suite *environSuite) TestSupportsNetworking(c *gc.C) {
 	env := suite.makeEnviron()
 	_, supported := environs.SupportsNetworking(env)
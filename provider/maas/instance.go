@@ -21,6 +21,7 @@ type maasInstance interface {
 	instance.Instance
 	zone() (string, error)
 	hostname() (string, error)
+	powerState() string
 	hardwareCharacteristics() (*instance.HardwareCharacteristics, error)
 	volumes(names.MachineTag, []names.VolumeTag) ([]storage.Volume, []storage.VolumeAttachment, error)
 }
@@ -33,6 +34,10 @@ type maas1Instance struct {
 
 var _ maasInstance = (*maas1Instance)(nil)
 
+// powerStateOff is the power state MAAS reports for a node it knows
+// to be switched off.
+const powerStateOff = "off"
+
 func (mi *maas1Instance) String() string {
 	hostname, err := mi.hostname()
 	if err != nil {
@@ -139,6 +144,17 @@ func (mi *maas1Instance) interfaceAddresses() ([]network.Address, error) {
 	return addresses, nil
 }
 
+// powerState returns the node's power state as last seen by MAAS,
+// e.g. "on", "off" or "unknown". It returns an empty string if MAAS
+// did not report one.
+func (mi *maas1Instance) powerState() string {
+	state, err := mi.maasObject.GetField("power_state")
+	if err != nil {
+		return ""
+	}
+	return normalizeStatus(state)
+}
+
 func (mi *maas1Instance) architecture() (arch, subarch string, err error) {
 	// MAAS may return an architecture of the form, for example,
 	// "amd64/generic"; we only care about the major part.
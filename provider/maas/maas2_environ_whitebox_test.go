@@ -6,6 +6,7 @@ package maas
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/gomaasapi"
@@ -121,6 +122,29 @@ func (suite *maas2EnvironSuite) TestAllInstances(c *gc.C) {
 	c.Assert(actualMachines, gc.DeepEquals, expectedMachines)
 }
 
+func (suite *maas2EnvironSuite) TestAllRunningInstances(c *gc.C) {
+	controller := &fakeController{
+		machines: []gomaasapi.Machine{
+			&fakeMachine{systemID: "tuco", powerState: "on"},
+			&fakeMachine{systemID: "tio", powerState: "off"},
+			&fakeMachine{systemID: "gus", powerState: "unknown"},
+		},
+	}
+	env := suite.makeEnviron(c, controller)
+
+	all, err := env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(all, gc.HasLen, 3)
+
+	result, err := env.AllRunningInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	actualMachines := set.NewStrings()
+	for _, instance := range result {
+		actualMachines.Add(string(instance.Id()))
+	}
+	c.Assert(actualMachines, gc.DeepEquals, set.NewStrings("tuco", "gus"))
+}
+
 func (suite *maas2EnvironSuite) TestAllInstancesError(c *gc.C) {
 	controller := &fakeController{machinesError: errors.New("Something terrible!")}
 	env := suite.makeEnviron(c, controller)
@@ -270,9 +294,28 @@ func (suite *maas2EnvironSuite) TestStopInstancesIgnoresMissingNodeAndRecurses(c
 	args := collectReleaseArgs(controller)
 	c.Assert(args, gc.HasLen, 4)
 	c.Assert(args[0].SystemIDs, gc.DeepEquals, []string{"test1", "test2", "test3"})
-	c.Assert(args[1].SystemIDs, gc.DeepEquals, []string{"test1"})
-	c.Assert(args[2].SystemIDs, gc.DeepEquals, []string{"test2"})
-	c.Assert(args[3].SystemIDs, gc.DeepEquals, []string{"test3"})
+	// test1 is named as unknown, so the rest are retried together; the
+	// second failure names no known node, so they're released one by one.
+	c.Assert(args[1].SystemIDs, gc.DeepEquals, []string{"test2", "test3"})
+	c.Assert([][]string{args[2].SystemIDs, args[3].SystemIDs}, jc.SameContents, [][]string{{"test2"}, {"test3"}})
+}
+
+func (suite *maas2EnvironSuite) TestStopInstancesReturnsOnlyGenuineFailures(c *gc.C) {
+	controller := newFakeControllerWithFiles(&fakeFile{name: coretesting.ModelTag.Id() + "-provider-state"})
+	controller.releaseMachines = func(args gomaasapi.ReleaseMachinesArgs) error {
+		switch {
+		case len(args.SystemIDs) > 1:
+			return gomaasapi.NewBadRequestError("something went wrong")
+		case args.SystemIDs[0] == "test1":
+			return gomaasapi.NewBadRequestError("no such machine")
+		case args.SystemIDs[0] == "test3":
+			return errors.New("boom")
+		}
+		return nil
+	}
+	err := suite.makeEnviron(c, controller).StopInstances("test1", "test2", "test3")
+	c.Assert(err, gc.ErrorMatches, `cannot release nodes: test3 \(cannot release nodes: boom\)`)
+	c.Assert(collectReleaseArgs(controller), gc.HasLen, 4)
 }
 
 func (suite *maas2EnvironSuite) checkStopInstancesFails(c *gc.C, withError error) {
@@ -656,6 +699,24 @@ func (suite *maas2EnvironSuite) TestAcquireNodeTranslatesSpaceNames(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (suite *maas2EnvironSuite) TestAcquireNodeExcludesOtherZones(c *gc.C) {
+	expected := gomaasapi.AllocateMachineArgs{
+		NotInZone: []string{"fonseca", "panama"},
+	}
+	env, controller := suite.injectControllerWithSpacesAndCheck(c, nil, expected)
+	controller.zones = []gomaasapi.Zone{
+		&fakeZone{name: "mossack"},
+		&fakeZone{name: "fonseca"},
+		&fakeZone{name: "panama"},
+		&fakeZone{name: "papers"},
+	}
+	cons := constraints.Value{
+		Zones: stringslicep("mossack", "papers", "^panama"),
+	}
+	_, err := env.acquireNode2("", "", "", cons, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (suite *maas2EnvironSuite) TestAcquireNodeUnrecognisedSpace(c *gc.C) {
 	suite.injectController(&fakeController{})
 	env := suite.makeEnviron(c, nil)
@@ -2307,6 +2368,58 @@ func (suite *maas2EnvironSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid constraint value: arch=ppc64el\nvalid values are: \\[amd64 armhf\\]")
 }
 
+func (suite *maas2EnvironSuite) TestSupportedArchitecturesCachedUntilExpiry(c *gc.C) {
+	controller := newFakeController()
+	controller.bootResources = []gomaasapi.BootResource{&fakeBootResource{name: "trusty", architecture: "amd64"}}
+	env := suite.makeEnviron(c, controller)
+	clock := testing.NewClock(time.Time{})
+	env.clock = clock
+
+	arches, err := env.getSupportedArchitectures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(arches, gc.DeepEquals, []string{"amd64"})
+
+	// A new boot image isn't seen while the cached value is fresh.
+	controller.bootResources = append(controller.bootResources, &fakeBootResource{name: "xenial", architecture: "arm64"})
+	clock.Advance(supportedArchitecturesTTL - time.Second)
+	arches, err = env.getSupportedArchitectures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(arches, gc.DeepEquals, []string{"amd64"})
+
+	clock.Advance(time.Second)
+	arches, err = env.getSupportedArchitectures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(arches, gc.DeepEquals, []string{"amd64", "arm64"})
+}
+
+func (suite *maas2EnvironSuite) TestRefreshSupportedArchitectures(c *gc.C) {
+	controller := newFakeController()
+	controller.bootResources = []gomaasapi.BootResource{&fakeBootResource{name: "trusty", architecture: "amd64"}}
+	env := suite.makeEnviron(c, controller)
+
+	arches, err := env.getSupportedArchitectures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(arches, gc.DeepEquals, []string{"amd64"})
+
+	controller.bootResources = []gomaasapi.BootResource{&fakeBootResource{name: "xenial", architecture: "arm64"}}
+	env.RefreshSupportedArchitectures()
+	arches, err = env.getSupportedArchitectures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(arches, gc.DeepEquals, []string{"arm64"})
+}
+
+func (suite *maas2EnvironSuite) TestConstraintsValidatorZonesVocab(c *gc.C) {
+	controller := newFakeController()
+	controller.bootResources = []gomaasapi.BootResource{&fakeBootResource{name: "trusty", architecture: "amd64"}}
+	env := suite.makeEnviron(c, controller)
+	validator, err := env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("zones=mossack,^fonseca"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("zones=panama"))
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: zones=panama\nvalid values are: \\[mossack \\^mossack fonseca \\^fonseca\\]")
+}
+
 func (suite *maas2EnvironSuite) TestReleaseContainerAddresses(c *gc.C) {
 	dev1 := newFakeDevice("a", "eleven")
 	dev2 := newFakeDevice("b", "will")
@@ -83,6 +83,8 @@ type fakeController struct {
 	files []gomaasapi.File
 
 	devices []gomaasapi.Device
+
+	releaseMachines func(gomaasapi.ReleaseMachinesArgs) error
 }
 
 func newFakeController() *fakeController {
@@ -197,6 +199,9 @@ func (c *fakeController) AddFile(args gomaasapi.AddFileArgs) error {
 
 func (c *fakeController) ReleaseMachines(args gomaasapi.ReleaseMachinesArgs) error {
 	c.MethodCall(c, "ReleaseMachines", args)
+	if c.releaseMachines != nil {
+		return c.releaseMachines(args)
+	}
 	return c.NextErr()
 }
 
@@ -232,6 +237,7 @@ type fakeMachine struct {
 	ipAddresses   []string
 	statusName    string
 	statusMessage string
+	powerState    string
 	cpuCount      int
 	memory        int
 	architecture  string
@@ -291,6 +297,10 @@ func (m *fakeMachine) StatusMessage() string {
 	return m.statusMessage
 }
 
+func (m *fakeMachine) PowerState() string {
+	return m.powerState
+}
+
 func (m *fakeMachine) Zone() gomaasapi.Zone {
 	return fakeZone{name: m.zoneName}
 }
@@ -29,6 +29,10 @@ func (mi *maas2Instance) hostname() (string, error) {
 	return mi.machine.Hostname(), nil
 }
 
+func (mi *maas2Instance) powerState() string {
+	return normalizeStatus(mi.machine.PowerState())
+}
+
 func (mi *maas2Instance) hardwareCharacteristics() (*instance.HardwareCharacteristics, error) {
 	nodeArch := strings.Split(mi.machine.Architecture(), "/")[0]
 	nodeCpuCount := uint64(mi.machine.CPUCount())
@@ -5,6 +5,7 @@ package openstack
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/schema"
 	"gopkg.in/juju/environschema.v1"
@@ -22,20 +23,30 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tbool,
 	},
 	"network": {
-		Description: "The network label or UUID to bring machines up on when multiple networks exist.",
+		Description: "The network label or UUID to bring machines up on when multiple networks exist. Several networks may be given as a comma-separated list, in which case machines are attached to all of them in order.",
 		Type:        environschema.Tstring,
 	},
 	"external-network": {
 		Description: "The network label or UUID to create floating IP addresses on when multiple external networks exist.",
 		Type:        environschema.Tstring,
 	},
+	"restrict-internal-traffic": {
+		Description: "Whether the juju security group should only allow the traffic Juju needs between machines (SSH, API and mongo) rather than all TCP, UDP and ICMP traffic.",
+		Type:        environschema.Tbool,
+	},
+	"release-floating-ip": {
+		Description: "Whether floating IP addresses assigned to an instance should be released back to the pool when the instance is terminated, rather than only being disassociated from it.",
+		Type:        environschema.Tbool,
+	},
 }
 
 var configDefaults = schema.Defaults{
-	"use-floating-ip":      false,
-	"use-default-secgroup": false,
-	"network":              "",
-	"external-network":     "",
+	"use-floating-ip":           false,
+	"use-default-secgroup":      false,
+	"network":                   "",
+	"external-network":          "",
+	"restrict-internal-traffic": false,
+	"release-floating-ip":       false,
 }
 
 var configFields = func() schema.Fields {
@@ -59,14 +70,39 @@ func (c *environConfig) useDefaultSecurityGroup() bool {
 	return c.attrs["use-default-secgroup"].(bool)
 }
 
+// network returns the first of the configured networks, or the empty
+// string if there are none.
 func (c *environConfig) network() string {
-	return c.attrs["network"].(string)
+	if networks := c.networks(); len(networks) > 0 {
+		return networks[0]
+	}
+	return ""
+}
+
+// networks returns the labels or UUIDs of the networks that machines
+// should be attached to, in order.
+func (c *environConfig) networks() []string {
+	var networks []string
+	for _, name := range strings.Split(c.attrs["network"].(string), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			networks = append(networks, name)
+		}
+	}
+	return networks
 }
 
 func (c *environConfig) externalNetwork() string {
 	return c.attrs["external-network"].(string)
 }
 
+func (c *environConfig) restrictInternalTraffic() bool {
+	return c.attrs["restrict-internal-traffic"].(bool)
+}
+
+func (c *environConfig) releaseFloatingIP() bool {
+	return c.attrs["release-floating-ip"].(bool)
+}
+
 type AuthMode string
 
 const (
@@ -31,6 +31,8 @@ type configTest struct {
 	region                  string
 	useFloatingIP           bool
 	useDefaultSecurityGroup bool
+	restrictInternalTraffic bool
+	releaseFloatingIP       bool
 	network                 string
 	externalNetwork         string
 	firewallMode            string
@@ -96,6 +98,8 @@ func (t configTest) check(c *gc.C) {
 	}
 	c.Assert(ecfg.useFloatingIP(), gc.Equals, t.useFloatingIP)
 	c.Assert(ecfg.useDefaultSecurityGroup(), gc.Equals, t.useDefaultSecurityGroup)
+	c.Assert(ecfg.restrictInternalTraffic(), gc.Equals, t.restrictInternalTraffic)
+	c.Assert(ecfg.releaseFloatingIP(), gc.Equals, t.releaseFloatingIP)
 	c.Assert(ecfg.network(), gc.Equals, t.network)
 	c.Assert(ecfg.externalNetwork(), gc.Equals, t.externalNetwork)
 	// Default should be true
@@ -144,6 +148,34 @@ var configTests = []configTest{
 			"use-default-secgroup": true,
 		}),
 		useDefaultSecurityGroup: true,
+	}, {
+		summary: "default restrict internal traffic",
+		config:  requiredConfig,
+		// Allow all internal traffic by default.
+		restrictInternalTraffic: false,
+	}, {
+		summary: "restrict internal traffic",
+		config: requiredConfig.Merge(testing.Attrs{
+			"restrict-internal-traffic": true,
+		}),
+		restrictInternalTraffic: true,
+	}, {
+		summary: "invalid restrict internal traffic",
+		config: requiredConfig.Merge(testing.Attrs{
+			"restrict-internal-traffic": "maybe",
+		}),
+		err: `.*expected bool, got string\("maybe"\)`,
+	}, {
+		summary: "default release floating ip",
+		config:  requiredConfig,
+		// Only disassociate floating IPs by default.
+		releaseFloatingIP: false,
+	}, {
+		summary: "release floating ip",
+		config: requiredConfig.Merge(testing.Attrs{
+			"release-floating-ip": true,
+		}),
+		releaseFloatingIP: true,
 	}, {
 		summary: "admin-secret given",
 		config: requiredConfig.Merge(testing.Attrs{
@@ -210,6 +242,13 @@ var configTests = []configTest{
 			"network": "a-network-label",
 		}),
 		network: "a-network-label",
+	}, {
+		summary: "multiple networks",
+		config: requiredConfig.Merge(testing.Attrs{
+			"network": "a-network-label, another-network-label",
+		}),
+		// The first network is used to find external networks.
+		network: "a-network-label",
 	}, {}, {
 		summary:         "default external network",
 		config:          requiredConfig,
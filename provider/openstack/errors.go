@@ -0,0 +1,136 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	gooseerrors "gopkg.in/goose.v2/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+)
+
+// quotaExceededError is returned when OpenStack refuses an operation
+// because a project quota has been used up. Quotas apply across all
+// availability zones, and retrying will not help until the quota is
+// raised or resources are released.
+type quotaExceededError struct {
+	error
+}
+
+// AvailabilityZoneIndependent is part of the
+// environs.AvailabilityZoneError interface.
+func (quotaExceededError) AvailabilityZoneIndependent() bool {
+	return true
+}
+
+// Retryable is part of the environs.RetryableError interface.
+func (quotaExceededError) Retryable() bool {
+	return false
+}
+
+// authFailureError is returned when OpenStack rejects the model's
+// credentials.
+type authFailureError struct {
+	error
+}
+
+// AvailabilityZoneIndependent is part of the
+// environs.AvailabilityZoneError interface.
+func (authFailureError) AvailabilityZoneIndependent() bool {
+	return true
+}
+
+// Retryable is part of the environs.RetryableError interface.
+func (authFailureError) Retryable() bool {
+	return false
+}
+
+// inactiveControllersError is returned when controller instances exist,
+// but none of them are active, and so none of their addresses can be
+// used yet.
+type inactiveControllersError struct {
+	ids []instance.Id
+}
+
+// Error is part of the error interface.
+func (e inactiveControllersError) Error() string {
+	return fmt.Sprintf("controller instances %v exist but none are active", e.ids)
+}
+
+// IsQuotaExceeded reports whether or not the cause of the given error
+// is an exhausted OpenStack quota.
+func IsQuotaExceeded(err error) bool {
+	_, ok := errors.Cause(err).(quotaExceededError)
+	return ok
+}
+
+// IsAuthFailure reports whether or not the cause of the given error
+// is OpenStack rejecting the model's credentials.
+func IsAuthFailure(err error) bool {
+	_, ok := errors.Cause(err).(authFailureError)
+	return ok
+}
+
+// IsControllerInstancesInactive reports whether or not the cause of
+// the given error is that controller instances exist, but none of them
+// are active. Unlike environs.ErrNoInstances, waiting may resolve it.
+func IsControllerInstancesInactive(err error) bool {
+	_, ok := errors.Cause(err).(inactiveControllersError)
+	return ok
+}
+
+// classifyError returns err with its cause replaced by one of the typed
+// errors above if the cause is recognised, and err unchanged otherwise.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	cause := errors.Cause(err)
+	var wrapped error
+	switch {
+	case gooseerrors.IsUnauthorised(cause):
+		wrapped = errors.Wrap(err, authFailureError{err})
+	case isQuotaExceededError(cause):
+		wrapped = errors.Wrap(err, quotaExceededError{err})
+	default:
+		return err
+	}
+	wrapped.(*errors.Err).SetLocation(1)
+	return wrapped
+}
+
+// zoneIndependentError classifies err, and marks it as independent of
+// any availability zone unless the classified error already says
+// whether it is.
+func zoneIndependentError(err error) error {
+	err = classifyError(err)
+	if _, ok := errors.Cause(err).(environs.AvailabilityZoneError); ok {
+		return err
+	}
+	return common.ZoneIndependentError(err)
+}
+
+// isQuotaExceededError reports whether err is Nova, Neutron or Cinder
+// refusing a request because a quota has been exceeded. None of them
+// reports this with a distinct error code, so we have to match the
+// message. Nova also uses "overLimit" for transient rate limiting, so
+// that alone is not taken to mean a quota has been used up.
+func isQuotaExceededError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{
+		"quota exceeded",
+		"overquota",
+		"exceeded for quota",
+	} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack_test
+
+import (
+	"regexp"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	gooseerrors "gopkg.in/goose.v2/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/openstack"
+	"github.com/juju/juju/testing"
+)
+
+type errorsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&errorsSuite{})
+
+func (s *errorsSuite) TestClassifyQuotaExceeded(c *gc.C) {
+	for _, msg := range []string{
+		"Quota exceeded for instances: Requested 1, but already used 10 of 10 instances",
+		"Quota exceeded for resources: ['floatingip']",
+		"caused by: OverQuota: Quota exceeded for resources: ['port']",
+		"VolumeLimitExceeded: Maximum number of volumes allowed (10) exceeded for quota 'volumes'",
+	} {
+		c.Logf("%s", msg)
+		err := openstack.ClassifyError(errors.Annotate(errors.New(msg), "cannot run instance"))
+		c.Check(err, jc.Satisfies, openstack.IsQuotaExceeded)
+		c.Check(err, gc.Not(jc.Satisfies), openstack.IsAuthFailure)
+		c.Check(err, gc.Not(jc.Satisfies), environs.IsRetryable)
+		c.Check(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+		c.Check(err, gc.ErrorMatches, "cannot run instance: "+regexp.QuoteMeta(msg))
+	}
+}
+
+func (s *errorsSuite) TestClassifyAuthFailure(c *gc.C) {
+	cause := gooseerrors.NewUnauthorisedf(nil, "", "authentication failed")
+	err := openstack.ClassifyError(errors.Annotate(cause, "cannot run instance"))
+	c.Check(err, jc.Satisfies, openstack.IsAuthFailure)
+	c.Check(err, gc.Not(jc.Satisfies), openstack.IsQuotaExceeded)
+	c.Check(err, gc.Not(jc.Satisfies), environs.IsRetryable)
+	c.Check(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+	c.Check(err, gc.ErrorMatches, "cannot run instance: .*authentication failed.*")
+}
+
+func (s *errorsSuite) TestClassifyOtherErrors(c *gc.C) {
+	for _, cause := range []error{
+		gooseerrors.NewNotFoundf(nil, "", "no such server"),
+		errors.New("No valid host was found"),
+		errors.New("connection refused"),
+		errors.New("overLimit: This request was rate-limited."),
+	} {
+		err := openstack.ClassifyError(cause)
+		c.Check(err, gc.Equals, cause)
+		c.Check(err, jc.Satisfies, environs.IsRetryable)
+		c.Check(err, gc.Not(jc.Satisfies), openstack.IsQuotaExceeded)
+		c.Check(err, gc.Not(jc.Satisfies), openstack.IsAuthFailure)
+	}
+	c.Check(openstack.ClassifyError(nil), jc.ErrorIsNil)
+}
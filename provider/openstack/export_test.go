@@ -30,9 +30,11 @@ import (
 )
 
 var (
-	ShortAttempt   = &shortAttempt
-	StorageAttempt = &storageAttempt
-	CinderAttempt  = &cinderAttempt
+	ShortAttempt      = &shortAttempt
+	StorageAttempt    = &storageAttempt
+	CinderAttempt     = &cinderAttempt
+	UserDataSizeLimit = &userDataSizeLimit
+	CheckUserDataSize = checkUserDataSize
 )
 
 // MetadataStorage returns a Storage instance which is used to store simplestreams metadata for tests.
@@ -449,12 +451,12 @@ func FindInstanceSpec(
 	}, imageMetadata)
 }
 
-func SetUpGlobalGroup(e environs.Environ, name string, apiPort int) (neutron.SecurityGroupV2, error) {
+func SetUpGlobalGroup(e environs.Environ, name string, apiPort, statePort int) (neutron.SecurityGroupV2, error) {
 	switching := e.(*Environ).firewaller.(*switchingFirewaller)
 	if err := switching.initFirewaller(); err != nil {
 		return neutron.SecurityGroupV2{}, err
 	}
-	return switching.fw.(*neutronFirewaller).setUpGlobalGroup(name, apiPort)
+	return switching.fw.(*neutronFirewaller).setUpGlobalGroup(name, apiPort, statePort)
 }
 
 func EnsureGroup(e environs.Environ, name string, rules []neutron.RuleInfoV2) (neutron.SecurityGroupV2, error) {
@@ -483,6 +485,14 @@ func MatchingGroup(e environs.Environ, nameRegExp string) (neutron.SecurityGroup
 	return switching.fw.(*neutronFirewaller).matchingGroup(nameRegExp)
 }
 
+func ReconcilePorts(e environs.Environ, nameRegExp string, desired []network.IngressRule) error {
+	switching := e.(*Environ).firewaller.(*switchingFirewaller)
+	if err := switching.initFirewaller(); err != nil {
+		return err
+	}
+	return switching.fw.(*neutronFirewaller).reconcilePorts(nameRegExp, desired)
+}
+
 // ImageMetadataStorage returns a Storage object pointing where the goose
 // infrastructure sets up its keystone entry for image metadata
 func ImageMetadataStorage(e environs.Environ) envstorage.Storage {
@@ -530,6 +540,8 @@ func ResolveNetwork(e environs.Environ, networkName string, external bool) (stri
 var PortsToRuleInfo = rulesToRuleInfo
 var SecGroupMatchesIngressRule = secGroupMatchesIngressRule
 
+var ClassifyError = classifyError
+
 var MakeServiceURL = &makeServiceURL
 
 var GetVolumeEndpointURL = getVolumeEndpointURL
@@ -562,3 +574,22 @@ func GetFirewaller(e environs.Environ) Firewaller {
 	env := e.(*Environ)
 	return env.firewaller
 }
+
+type runServerRecorder struct {
+	ProviderConfigurator
+	networks *[][]nova.ServerNetworks
+}
+
+func (r runServerRecorder) ModifyRunServerOptions(options *nova.RunServerOpts) {
+	r.ProviderConfigurator.ModifyRunServerOptions(options)
+	*r.networks = append(*r.networks, options.Networks)
+}
+
+// RecordRunServerNetworks arranges for the networks passed to each
+// RunServer call made by e to be appended to the returned slice.
+func RecordRunServerNetworks(e environs.Environ) *[][]nova.ServerNetworks {
+	env := e.(*Environ)
+	var networks [][]nova.ServerNetworks
+	env.configurator = runServerRecorder{env.configurator, &networks}
+	return &networks
+}
@@ -70,8 +70,9 @@ type Firewaller interface {
 	GetSecurityGroups(ids ...instance.Id) ([]string, error)
 
 	// SetUpGroups sets up initial security groups, if any, and returns
-	// their names.
-	SetUpGroups(controllerUUID, machineId string, apiPort int) ([]string, error)
+	// their names. statePort is the controller's mongo port, or 0 if the
+	// machine is not a controller and the port is not known.
+	SetUpGroups(controllerUUID, machineId string, apiPort, statePort int) ([]string, error)
 
 	// OpenInstancePorts opens the given port ranges for the specified  instance.
 	OpenInstancePorts(inst instance.Instance, machineId string, rules []network.IngressRule) error
@@ -177,11 +178,11 @@ func (f *switchingFirewaller) GetSecurityGroups(ids ...instance.Id) ([]string, e
 	return f.fw.GetSecurityGroups(ids...)
 }
 
-func (f *switchingFirewaller) SetUpGroups(controllerUUID, machineId string, apiPort int) ([]string, error) {
+func (f *switchingFirewaller) SetUpGroups(controllerUUID, machineId string, apiPort, statePort int) ([]string, error) {
 	if err := f.initFirewaller(); err != nil {
 		return nil, errors.Trace(err)
 	}
-	return f.fw.SetUpGroups(controllerUUID, machineId, apiPort)
+	return f.fw.SetUpGroups(controllerUUID, machineId, apiPort, statePort)
 }
 
 func (f *switchingFirewaller) OpenInstancePorts(inst instance.Instance, machineId string, rules []network.IngressRule) error {
@@ -442,8 +443,8 @@ type neutronFirewaller struct {
 // Note: ideally we'd have a better way to determine group membership so that 2
 // people that happen to share an openstack account and name their environment
 // "openstack" don't end up destroying each other's machines.
-func (c *neutronFirewaller) SetUpGroups(controllerUUID, machineId string, apiPort int) ([]string, error) {
-	jujuGroup, err := c.setUpGlobalGroup(c.jujuGroupName(controllerUUID), apiPort)
+func (c *neutronFirewaller) SetUpGroups(controllerUUID, machineId string, apiPort, statePort int) ([]string, error) {
+	jujuGroup, err := c.setUpGlobalGroup(c.jujuGroupName(controllerUUID), apiPort, statePort)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -464,75 +465,140 @@ func (c *neutronFirewaller) SetUpGroups(controllerUUID, machineId string, apiPor
 	return groups, nil
 }
 
-func (c *neutronFirewaller) setUpGlobalGroup(groupName string, apiPort int) (neutron.SecurityGroupV2, error) {
-	return c.ensureGroup(groupName,
-		[]neutron.RuleInfoV2{
-			{
-				Direction:      "ingress",
-				IPProtocol:     "tcp",
-				PortRangeMax:   22,
-				PortRangeMin:   22,
-				RemoteIPPrefix: "::/0",
-				EthernetType:   "IPv6",
-			},
-			{
-				Direction:      "ingress",
-				IPProtocol:     "tcp",
-				PortRangeMax:   22,
-				PortRangeMin:   22,
-				RemoteIPPrefix: "0.0.0.0/0",
-			},
-			{
-				Direction:      "ingress",
-				IPProtocol:     "tcp",
-				PortRangeMax:   apiPort,
-				PortRangeMin:   apiPort,
-				RemoteIPPrefix: "::/0",
-				EthernetType:   "IPv6",
-			},
-			{
-				Direction:      "ingress",
-				IPProtocol:     "tcp",
-				PortRangeMax:   apiPort,
-				PortRangeMin:   apiPort,
-				RemoteIPPrefix: "0.0.0.0/0",
-			},
-			{
+func (c *neutronFirewaller) setUpGlobalGroup(groupName string, apiPort, statePort int) (neutron.SecurityGroupV2, error) {
+	rules := []neutron.RuleInfoV2{
+		{
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMax:   22,
+			PortRangeMin:   22,
+			RemoteIPPrefix: "::/0",
+			EthernetType:   "IPv6",
+		},
+		{
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMax:   22,
+			PortRangeMin:   22,
+			RemoteIPPrefix: "0.0.0.0/0",
+		},
+		{
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMax:   apiPort,
+			PortRangeMin:   apiPort,
+			RemoteIPPrefix: "::/0",
+			EthernetType:   "IPv6",
+		},
+		{
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMax:   apiPort,
+			PortRangeMin:   apiPort,
+			RemoteIPPrefix: "0.0.0.0/0",
+		},
+	}
+	if c.environ.ecfg().restrictInternalTraffic() {
+		if statePort == 0 {
+			// Only controllers know the state port; keep the
+			// rule the controllers set up.
+			var err error
+			statePort, err = c.existingStatePort(groupName, apiPort)
+			if err != nil {
+				return zeroGroup, errors.Annotate(err, "cannot find state port")
+			}
+		}
+		if statePort == 0 {
+			return c.ensureGroup(groupName, rules)
+		}
+		// Only controllers talk to mongo, and they are all
+		// members of the juju group.
+		rules = append(rules,
+			neutron.RuleInfoV2{
 				Direction:    "ingress",
 				IPProtocol:   "tcp",
-				PortRangeMin: 1,
-				PortRangeMax: 65535,
+				PortRangeMin: statePort,
+				PortRangeMax: statePort,
 				EthernetType: "IPv6",
 			},
-			{
+			neutron.RuleInfoV2{
 				Direction:    "ingress",
 				IPProtocol:   "tcp",
-				PortRangeMin: 1,
-				PortRangeMax: 65535,
+				PortRangeMin: statePort,
+				PortRangeMax: statePort,
 			},
-			{
-				Direction:    "ingress",
-				IPProtocol:   "udp",
-				PortRangeMin: 1,
-				PortRangeMax: 65535,
-				EthernetType: "IPv6",
-			},
-			{
-				Direction:    "ingress",
-				IPProtocol:   "udp",
-				PortRangeMin: 1,
-				PortRangeMax: 65535,
-			},
-			{
-				Direction:    "ingress",
-				IPProtocol:   "icmp",
-				EthernetType: "IPv6",
-			},
-			{
-				Direction:  "ingress",
-				IPProtocol: "icmp",
-			},
-		})
+		)
+		return c.ensureGroup(groupName, rules)
+	}
+	rules = append(rules,
+		neutron.RuleInfoV2{
+			Direction:    "ingress",
+			IPProtocol:   "tcp",
+			PortRangeMin: 1,
+			PortRangeMax: 65535,
+			EthernetType: "IPv6",
+		},
+		neutron.RuleInfoV2{
+			Direction:    "ingress",
+			IPProtocol:   "tcp",
+			PortRangeMin: 1,
+			PortRangeMax: 65535,
+		},
+		neutron.RuleInfoV2{
+			Direction:    "ingress",
+			IPProtocol:   "udp",
+			PortRangeMin: 1,
+			PortRangeMax: 65535,
+			EthernetType: "IPv6",
+		},
+		neutron.RuleInfoV2{
+			Direction:    "ingress",
+			IPProtocol:   "udp",
+			PortRangeMin: 1,
+			PortRangeMax: 65535,
+		},
+		neutron.RuleInfoV2{
+			Direction:    "ingress",
+			IPProtocol:   "icmp",
+			EthernetType: "IPv6",
+		},
+		neutron.RuleInfoV2{
+			Direction:  "ingress",
+			IPProtocol: "icmp",
+		},
+	)
+	return c.ensureGroup(groupName, rules)
+}
+
+// existingStatePort returns the state port opened to members of the
+// named juju group by a controller, or 0 if there is no such group or
+// rule. It is the only single-port tcp rule in the group, other than
+// the ssh and API rules, that is not open to any CIDR. Any other error
+// is returned, as guessing would remove the controllers' rule.
+func (c *neutronFirewaller) existingStatePort(groupName string, apiPort int) (int, error) {
+	groups, err := c.environ.neutron().SecurityGroupByNameV2(groupName)
+	if err != nil && strings.Contains(err.Error(), "failed to find security group") {
+		// See the TODO in ensureGroup about typed errors.
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Trace(err)
+	} else if len(groups) > 1 {
+		return 0, errors.Errorf("more than one security group named %s was found", groupName)
+	} else if len(groups) == 0 {
+		return 0, nil
+	}
+	for _, r := range groups[0].Rules {
+		if r.Direction != "ingress" || r.RemoteIPPrefix != "" ||
+			r.IPProtocol == nil || *r.IPProtocol != "tcp" ||
+			r.PortRangeMin == nil || r.PortRangeMax == nil ||
+			*r.PortRangeMin != *r.PortRangeMax {
+			continue
+		}
+		if port := *r.PortRangeMin; port != 22 && port != apiPort {
+			return port, nil
+		}
+	}
+	return 0, nil
 }
 
 // zeroGroup holds the zero security group.
@@ -570,8 +636,30 @@ func (c *neutronFirewaller) ensureGroup(name string, rules []neutron.RuleInfoV2)
 		return zeroGroup, err
 	}
 
+	if err := c.updateGroupRules(group, newRuleInfoSetFromRuleInfo(rules)); err != nil {
+		return zeroGroup, err
+	}
+
+	// Since we may have done a few add or delete rules, get a new
+	// copy of the security group to return containing the end
+	// list of rules.
+	groupsFound, err = neutronClient.SecurityGroupByNameV2(name)
+	if err != nil {
+		return zeroGroup, err
+	} else if len(groupsFound) > 1 {
+		// TODO(hml): Add unit test for this case
+		return zeroGroup, errors.New(fmt.Sprintf("More than one security group named %s was found after group was ensured", name))
+	}
+	return groupsFound[0], nil
+}
+
+// updateGroupRules makes the rules of the given security group match
+// want in a single pass: rules the group has but which are not wanted
+// are deleted, and wanted rules the group does not have are created.
+// Egress rules are never deleted.
+func (c *neutronFirewaller) updateGroupRules(group neutron.SecurityGroupV2, want ruleInfoSet) error {
+	neutronClient := c.environ.neutron()
 	have := newRuleInfoSetFromRules(group.Rules)
-	want := newRuleInfoSetFromRuleInfo(rules)
 
 	// Find rules we want to delete, that we have but don't want, and
 	// delete them.
@@ -584,8 +672,8 @@ func (c *neutronFirewaller) ensureGroup(name string, rules []neutron.RuleInfoV2)
 		}
 	}
 	for _, ruleId := range remove {
-		if err = neutronClient.DeleteSecurityGroupRuleV2(ruleId); err != nil {
-			return zeroGroup, err
+		if err := neutronClient.DeleteSecurityGroupRuleV2(ruleId); err != nil {
+			return err
 		}
 	}
 
@@ -606,21 +694,10 @@ func (c *neutronFirewaller) ensureGroup(name string, rules []neutron.RuleInfoV2)
 			rule.RemoteGroupId = group.Id
 		}
 		if _, err := neutronClient.CreateSecurityGroupRuleV2(rule); err != nil {
-			return zeroGroup, err
+			return err
 		}
 	}
-
-	// Since we may have done a few add or delete rules, get a new
-	// copy of the security group to return containing the end
-	// list of rules.
-	groupsFound, err = neutronClient.SecurityGroupByNameV2(name)
-	if err != nil {
-		return zeroGroup, err
-	} else if len(groupsFound) > 1 {
-		// TODO(hml): Add unit test for this case
-		return zeroGroup, errors.New(fmt.Sprintf("More than one security group named %s was found after group was ensured", name))
-	}
-	return groupsFound[0], nil
+	return nil
 }
 
 // ruleInfoSet represents a Security Group Rule created for a Security Group.
@@ -837,21 +914,25 @@ func (c *neutronFirewaller) matchingGroup(nameRegExp string) (neutron.SecurityGr
 	return matchingGroups[0], nil
 }
 
-func (c *neutronFirewaller) openPortsInGroup(nameRegExp string, rules []network.IngressRule) error {
+// reconcilePorts makes the ingress rules of the security group matching
+// nameRegExp match the desired rules, adding and removing only the rules
+// that differ. Reconciling to an empty set revokes every ingress rule in
+// the group.
+func (c *neutronFirewaller) reconcilePorts(nameRegExp string, desired []network.IngressRule) error {
 	group, err := c.matchingGroup(nameRegExp)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	neutronClient := c.environ.neutron()
-	ruleInfo := rulesToRuleInfo(group.Id, rules)
-	for _, rule := range ruleInfo {
-		_, err := neutronClient.CreateSecurityGroupRuleV2(rule)
-		if err != nil {
-			// TODO: if err is not rule already exists, raise?
-			logger.Debugf("error creating security group rule: %v", err.Error())
-		}
+	want := newRuleInfoSetFromRuleInfo(rulesToRuleInfo(group.Id, desired))
+	return errors.Trace(c.updateGroupRules(group, want))
+}
+
+func (c *neutronFirewaller) openPortsInGroup(nameRegExp string, rules []network.IngressRule) error {
+	current, err := c.ingressRulesInGroup(nameRegExp)
+	if err != nil {
+		return errors.Trace(err)
 	}
-	return nil
+	return errors.Trace(c.reconcilePorts(nameRegExp, append(current, rules...)))
 }
 
 // secGroupMatchesIngressRule checks if supplied nova security group rule matches the ingress rule
@@ -884,25 +965,47 @@ func (c *neutronFirewaller) closePortsInGroup(nameRegExp string, rules []network
 	if len(rules) == 0 {
 		return nil
 	}
-	group, err := c.matchingGroup(nameRegExp)
+	current, err := c.ingressRulesInGroup(nameRegExp)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	neutronClient := c.environ.neutron()
-	// TODO: Hey look ma, it's quadratic
-	for _, rule := range rules {
-		for _, p := range group.Rules {
-			if !secGroupMatchesIngressRule(p, rule) {
-				continue
+	var desired []network.IngressRule
+	for _, have := range current {
+		var keep []string
+		for _, cidr := range have.SourceCIDRs {
+			if !ingressRulesMatchCIDR(rules, have.PortRange, cidr) {
+				keep = append(keep, cidr)
 			}
-			err := neutronClient.DeleteSecurityGroupRuleV2(p.Id)
-			if err != nil {
-				return errors.Trace(err)
+		}
+		if len(keep) == 0 {
+			continue
+		}
+		rule, err := network.NewIngressRule(have.Protocol, have.FromPort, have.ToPort, keep...)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		desired = append(desired, rule)
+	}
+	return errors.Trace(c.reconcilePorts(nameRegExp, desired))
+}
+
+// ingressRulesMatchCIDR reports whether any of the given rules covers
+// the port range from the given source CIDR.
+func ingressRulesMatchCIDR(rules []network.IngressRule, portRange network.PortRange, cidr string) bool {
+	for _, rule := range rules {
+		if rule.PortRange != portRange {
+			continue
+		}
+		if len(rule.SourceCIDRs) == 0 && cidr == "0.0.0.0/0" {
+			return true
+		}
+		for _, r := range rule.SourceCIDRs {
+			if r == cidr {
+				return true
 			}
-			break
 		}
 	}
-	return nil
+	return false
 }
 
 func (c *neutronFirewaller) ingressRulesInGroup(nameRegexp string) (rules []network.IngressRule, err error) {
@@ -4,6 +4,7 @@
 package openstack
 
 import (
+	"github.com/juju/errors"
 	"gopkg.in/goose.v2/nova"
 
 	"github.com/juju/juju/environs/imagemetadata"
@@ -39,6 +40,34 @@ func findInstanceSpec(
 	imageMetadata []*imagemetadata.ImageMetadata,
 ) (*instances.InstanceSpec, error) {
 	// First construct all available instance types from the supported flavors.
+	allInstanceTypes, err := e.flavorInstanceTypes(ic.Arches, ic.Constraints.VirtType)
+	if err != nil {
+		return nil, err
+	}
+	if ic.Constraints.HasInstanceType() {
+		if err := checkInstanceTypeExists(*ic.Constraints.InstanceType, allInstanceTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	images := instances.ImageMetadataToImages(imageMetadata)
+	spec, err := instances.FindInstanceSpec(images, ic, allInstanceTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	// If instance constraints did not have a virtualisation type,
+	// but image metadata did, we will have an instance type
+	// with virtualisation type of an image.
+	if !ic.Constraints.HasVirtType() && spec.Image.VirtType != "" {
+		spec.InstanceType.VirtType = &spec.Image.VirtType
+	}
+	return spec, nil
+}
+
+// flavorInstanceTypes returns an instance type for each server flavor
+// accepted by the environ's flavor filter.
+func (e *Environ) flavorInstanceTypes(arches []string, virtType *string) ([]instances.InstanceType, error) {
 	nova := e.nova()
 	flavors, err := nova.ListFlavorsDetail()
 	if err != nil {
@@ -56,31 +85,30 @@ func findInstanceSpec(
 		instanceType := instances.InstanceType{
 			Id:       flavor.Id,
 			Name:     flavor.Name,
-			Arches:   ic.Arches,
+			Arches:   arches,
 			Mem:      uint64(flavor.RAM),
 			CpuCores: uint64(flavor.VCPUs),
 			RootDisk: uint64(flavor.Disk * 1024),
 			// tags not currently supported on openstack
 		}
-		if ic.Constraints.HasVirtType() {
+		if virtType != nil && *virtType != "" {
 			// Instance Type virtual type depends on the virtual type of the selected image, i.e.
 			// picking an image with a virt type gives a machine with this virt type.
-			instanceType.VirtType = ic.Constraints.VirtType
+			instanceType.VirtType = virtType
 		}
 		allInstanceTypes = append(allInstanceTypes, instanceType)
 	}
+	return allInstanceTypes, nil
+}
 
-	images := instances.ImageMetadataToImages(imageMetadata)
-	spec, err := instances.FindInstanceSpec(images, ic, allInstanceTypes)
-	if err != nil {
-		return nil, err
-	}
-
-	// If instance constraints did not have a virtualisation type,
-	// but image metadata did, we will have an instance type
-	// with virtualisation type of an image.
-	if !ic.Constraints.HasVirtType() && spec.Image.VirtType != "" {
-		spec.InstanceType.VirtType = &spec.Image.VirtType
+// checkInstanceTypeExists returns an error if none of the given
+// instance types has the requested name, so that an unknown flavour
+// is reported as such rather than as a failure to match constraints.
+func checkInstanceTypeExists(name string, instanceTypes []instances.InstanceType) error {
+	for _, instanceType := range instanceTypes {
+		if instanceType.Name == name {
+			return nil
+		}
 	}
-	return spec, nil
+	return errors.Errorf("invalid Openstack flavour %q specified", name)
 }
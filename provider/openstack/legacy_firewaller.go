@@ -29,8 +29,8 @@ type legacyNovaFirewaller struct {
 // other instances that might be running on the same OpenStack account.
 // In addition, a specific machine security group is created for each
 // machine, so that its firewall rules can be configured per machine.
-func (c *legacyNovaFirewaller) SetUpGroups(controllerUUID, machineId string, apiPort int) ([]string, error) {
-	jujuGroup, err := c.setUpGlobalGroup(c.jujuGroupName(controllerUUID), apiPort)
+func (c *legacyNovaFirewaller) SetUpGroups(controllerUUID, machineId string, apiPort, statePort int) ([]string, error) {
+	jujuGroup, err := c.setUpGlobalGroup(c.jujuGroupName(controllerUUID), apiPort, statePort)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -51,37 +51,53 @@ func (c *legacyNovaFirewaller) SetUpGroups(controllerUUID, machineId string, api
 	return groupNames, nil
 }
 
-func (c *legacyNovaFirewaller) setUpGlobalGroup(groupName string, apiPort int) (nova.SecurityGroup, error) {
-	return c.ensureGroup(groupName,
-		[]nova.RuleInfo{
-			{
-				IPProtocol: "tcp",
-				ToPort:     22,
-				FromPort:   22,
-				Cidr:       "0.0.0.0/0",
-			},
-			{
-				IPProtocol: "tcp",
-				ToPort:     apiPort,
-				FromPort:   apiPort,
-				Cidr:       "0.0.0.0/0",
-			},
-			{
+func (c *legacyNovaFirewaller) setUpGlobalGroup(groupName string, apiPort, statePort int) (nova.SecurityGroup, error) {
+	rules := []nova.RuleInfo{
+		{
+			IPProtocol: "tcp",
+			ToPort:     22,
+			FromPort:   22,
+			Cidr:       "0.0.0.0/0",
+		},
+		{
+			IPProtocol: "tcp",
+			ToPort:     apiPort,
+			FromPort:   apiPort,
+			Cidr:       "0.0.0.0/0",
+		},
+	}
+	if c.environ.ecfg().restrictInternalTraffic() {
+		// Only controllers talk to mongo, and they are all
+		// members of the juju group. An existing group is
+		// left as it is, so the port is only needed when a
+		// controller creates the group.
+		if statePort != 0 {
+			rules = append(rules, nova.RuleInfo{
 				IPProtocol: "tcp",
-				FromPort:   1,
-				ToPort:     65535,
-			},
-			{
-				IPProtocol: "udp",
-				FromPort:   1,
-				ToPort:     65535,
-			},
-			{
-				IPProtocol: "icmp",
-				FromPort:   -1,
-				ToPort:     -1,
-			},
-		})
+				FromPort:   statePort,
+				ToPort:     statePort,
+			})
+		}
+		return c.ensureGroup(groupName, rules)
+	}
+	rules = append(rules,
+		nova.RuleInfo{
+			IPProtocol: "tcp",
+			FromPort:   1,
+			ToPort:     65535,
+		},
+		nova.RuleInfo{
+			IPProtocol: "udp",
+			FromPort:   1,
+			ToPort:     65535,
+		},
+		nova.RuleInfo{
+			IPProtocol: "icmp",
+			FromPort:   -1,
+			ToPort:     -1,
+		},
+	)
+	return c.ensureGroup(groupName, rules)
 }
 
 // legacyZeroGroup holds the zero security group.
@@ -53,6 +53,37 @@ func (n *LegacyNovaNetworking) AllocatePublicIP(instId instance.Id) (*string, er
 	return &newfip.IP, nil
 }
 
+// RemovePublicIPs is part of the Networking interface.
+func (n *LegacyNovaNetworking) RemovePublicIPs(ids []instance.Id, release bool) error {
+	novaClient := n.env.nova()
+	fips, err := novaClient.ListFloatingIPs()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	idSet := make(map[instance.Id]bool)
+	for _, id := range ids {
+		idSet[id] = true
+	}
+	for _, fip := range fips {
+		if fip.InstanceId == nil || !idSet[instance.Id(*fip.InstanceId)] {
+			continue
+		}
+		logger.Debugf("removing floating IP %v from instance %q", fip.IP, *fip.InstanceId)
+		if err := novaClient.RemoveServerFloatingIP(*fip.InstanceId, fip.IP); err != nil {
+			logger.Warningf("cannot remove floating IP %v from instance %q: %v", fip.IP, *fip.InstanceId, err)
+			continue
+		}
+		if !release {
+			continue
+		}
+		logger.Debugf("releasing floating IP %v", fip.IP)
+		if err := novaClient.DeleteFloatingIP(fip.Id); err != nil {
+			logger.Warningf("cannot release floating IP %v: %v", fip.IP, err)
+		}
+	}
+	return nil
+}
+
 // DefaultNetworks is part of the Networking interface.
 func (*LegacyNovaNetworking) DefaultNetworks() ([]nova.ServerNetworks, error) {
 	return []nova.ServerNetworks{}, nil
@@ -510,7 +510,47 @@ func (s *localServerSuite) TestStartInstanceExternalNetwork(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
-func (s *localServerSuite) TestStartInstanceNetworkUnknownLabel(c *gc.C) {
+func (s *localServerSuite) TestStartInstanceMultipleNetworks(c *gc.C) {
+	cfg, err := s.env.Config().Apply(coretesting.Attrs{
+		"network": "private_999, net",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.env.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	privateId, err := openstack.ResolveNetwork(s.env, "private_999", false)
+	c.Assert(err, jc.ErrorIsNil)
+	netId, err := openstack.ResolveNetwork(s.env, "net", false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	networks := openstack.RecordRunServerNetworks(s.env)
+	inst, _ := testing.AssertStartInstance(c, s.env, s.ControllerUUID, "100")
+	c.Assert(*networks, gc.DeepEquals, [][]nova.ServerNetworks{{
+		{NetworkId: privateId},
+		{NetworkId: netId},
+	}})
+	err = s.env.StopInstances(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *localServerSuite) TestPrepareForBootstrapNetworkUnknownLabel(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{
+		// A label that has no related network in the neutron test service
+		"network": "net, no-network-with-this-label",
+	})
+	err := env.PrepareForBootstrap(envtesting.BootstrapContext(c))
+	c.Assert(err, gc.ErrorMatches, `invalid network "no-network-with-this-label": no networks exist with label .*`)
+}
+
+func (s *localServerSuite) TestPrepareForBootstrapNetworks(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{"network": "private_999, net"})
+	err := env.PrepareForBootstrap(envtesting.BootstrapContext(c))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *localServerSuite) TestSetConfigNetworkUnknownLabel(c *gc.C) {
+	// SetConfig does not look networks up, so that a cloud error
+	// cannot make it fail; an unknown network is reported when an
+	// instance is started.
 	cfg, err := s.env.Config().Apply(coretesting.Attrs{
 		// A label that has no related network in the neutron test service
 		"network": "no-network-with-this-label",
@@ -524,6 +564,17 @@ func (s *localServerSuite) TestStartInstanceNetworkUnknownLabel(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "no networks exist with label .*")
 }
 
+func (s *localServerSuite) TestStartInstanceNetworkUnknownLabel(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{
+		// A label that has no related network in the neutron test service
+		"network": "no-network-with-this-label",
+	})
+
+	inst, _, _, err := testing.StartInstance(env, s.ControllerUUID, "100")
+	c.Check(inst, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "no networks exist with label .*")
+}
+
 func (s *localServerSuite) TestStartInstanceExternalNetworkUnknownLabel(c *gc.C) {
 	cfg, err := s.env.Config().Apply(coretesting.Attrs{
 		// A label that has no related network in the neutron test service
@@ -623,6 +674,21 @@ func (s *localServerSuite) TestStartInstancePortSecurityDisabled(c *gc.C) {
 	c.Assert(detail.Groups, gc.IsNil)
 }
 
+func (s *localServerSuite) TestStartInstanceUserDataTooLarge(c *gc.C) {
+	s.PatchValue(openstack.UserDataSizeLimit, 64)
+	cleanup := s.srv.Nova.RegisterControlPoint(
+		"addServer",
+		func(sc hook.ServiceControl, args ...interface{}) error {
+			c.Errorf("unexpected attempt to run server with oversized user data")
+			return nil
+		},
+	)
+	defer cleanup()
+	inst, _, _, err := testing.StartInstance(s.env, s.ControllerUUID, "100")
+	c.Check(inst, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "user data is [0-9]+ bytes when encoded, exceeding the nova limit of 64 bytes")
+}
+
 func (s *localServerSuite) TestStartInstanceGetServerFail(c *gc.C) {
 	// Force an error in waitForActiveServerDetails
 	cleanup := s.srv.Nova.RegisterControlPoint(
@@ -783,6 +849,24 @@ func (s *localServerSuite) TestDestroyEnvironmentDeletesSecurityGroupsFWModeGlob
 	assertSecurityGroups(c, env, []string{"default"})
 }
 
+func (s *localServerSuite) TestOperationsFailAfterDestroy(c *gc.C) {
+	env := s.openEnviron(c, nil)
+	inst, _ := testing.AssertStartInstance(c, env, s.ControllerUUID, "100")
+	err := env.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = env.AllInstances()
+	c.Assert(err, gc.Equals, openstack.ErrEnvironDestroyed)
+	_, err = env.Instances([]instance.Id{inst.Id()})
+	c.Assert(err, gc.Equals, openstack.ErrEnvironDestroyed)
+	_, _, _, err = testing.StartInstance(env, s.ControllerUUID, "101")
+	c.Assert(err, gc.ErrorMatches, "environ has been destroyed")
+
+	// Destroying again is a no-op.
+	err = env.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *localServerSuite) TestDestroyController(c *gc.C) {
 	env := s.openEnviron(c, coretesting.Attrs{"uuid": utils.MustNewUUID().String()})
 	controllerEnv := s.env
@@ -967,6 +1051,40 @@ func (s *localServerSuite) TestInstancesGatheringWithFloatingIP(c *gc.C) {
 	s.assertInstancesGathering(c, true)
 }
 
+func (s *localServerSuite) TestInstancesWithErrors(c *gc.C) {
+	env := s.openEnviron(c, nil).(*openstack.Environ)
+	inst0, _ := testing.AssertStartInstance(c, env, s.ControllerUUID, "100")
+	inst1, _ := testing.AssertStartInstance(c, env, s.ControllerUUID, "101")
+	defer func() {
+		err := env.StopInstances(inst0.Id(), inst1.Id())
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+
+	ids := []instance.Id{"missing0", inst1.Id(), "missing1", inst0.Id()}
+	results, err := env.InstancesWithErrors(ids)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, len(ids))
+	for i, result := range results {
+		c.Check(result.Id, gc.Equals, ids[i])
+	}
+	c.Check(results[0].Instance, gc.IsNil)
+	c.Check(results[0].Err, gc.ErrorMatches, `instance "missing0" not found`)
+	c.Check(results[0].Err, jc.Satisfies, errors.IsNotFound)
+	c.Check(results[1].Err, jc.ErrorIsNil)
+	c.Check(results[1].Instance.Id(), gc.Equals, inst1.Id())
+	c.Check(results[2].Instance, gc.IsNil)
+	c.Check(results[2].Err, jc.Satisfies, errors.IsNotFound)
+	c.Check(results[3].Err, jc.ErrorIsNil)
+	c.Check(results[3].Instance.Id(), gc.Equals, inst0.Id())
+
+	// When nothing is found, every result carries an error but the
+	// lookup as a whole succeeds.
+	results, err = env.InstancesWithErrors([]instance.Id{"missing0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Check(results[0].Err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *localServerSuite) TestInstancesShutoffSuspended(c *gc.C) {
 	coretesting.SkipIfPPC64EL(c, "lp:1425242")
 
@@ -1060,6 +1178,43 @@ func (s *localServerSuite) TestBootstrapInstanceUserDataAndState(c *gc.C) {
 	// ec2 tests).
 }
 
+func (s *localServerSuite) TestControllerInstancesNoInstances(c *gc.C) {
+	_, err := s.env.ControllerInstances(s.ControllerUUID)
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+}
+
+func (s *localServerSuite) TestControllerInstancesInactive(c *gc.C) {
+	cleanup := s.srv.Nova.RegisterControlPoint(
+		"addServer",
+		func(sc hook.ServiceControl, args ...interface{}) error {
+			details := args[0].(*nova.ServerDetail)
+			details.Status = nova.StatusShutoff
+			return nil
+		},
+	)
+	defer cleanup()
+	err := bootstrapEnv(c, s.env)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The controller instance exists, but is not active, so its
+	// addresses must not be used.
+	_, err = s.env.ControllerInstances(s.ControllerUUID)
+	c.Assert(err, gc.ErrorMatches, `controller instances \[.*\] exist but none are active`)
+	c.Assert(err, jc.Satisfies, openstack.IsControllerInstancesInactive)
+	c.Assert(err, gc.Not(gc.Equals), environs.ErrNoInstances)
+}
+
+func (s *localServerSuite) TestDestroyStopsController(c *gc.C) {
+	err := bootstrapEnv(c, s.env)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.env.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	insts, err := openstack.GetNovaClient(s.env).ListServersDetail(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(insts, gc.HasLen, 0)
+}
+
 func (s *localServerSuite) assertGetImageMetadataSources(c *gc.C, stream, officialSourcePath string) {
 	// Create a config that matches s.TestConfig but with the specified stream.
 	attrs := coretesting.Attrs{}
@@ -1315,7 +1470,7 @@ func (s *localServerSuite) TestFindImageInvalidInstanceConstraint(c *gc.C) {
 		env, series.LatestLts(), "amd64", "instance-type=m1.large",
 		imageMetadata,
 	)
-	c.Assert(err, gc.ErrorMatches, `no instance types in some-region matching constraints "instance-type=m1.large"`)
+	c.Assert(err, gc.ErrorMatches, `invalid Openstack flavour "m1.large" specified`)
 }
 
 func (s *localServerSuite) TestPrecheckInstanceValidInstanceType(c *gc.C) {
@@ -1332,6 +1487,20 @@ func (s *localServerSuite) TestPrecheckInstanceInvalidInstanceType(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid Openstack flavour "m1.large" specified`)
 }
 
+func (s *localServerSuite) TestPrecheckInstanceValidConstraints(c *gc.C) {
+	env := s.Open(c, s.env.Config())
+	cons := constraints.MustParse("arch=amd64 mem=1G cores=1")
+	err := env.PrecheckInstance(environs.PrecheckInstanceParams{Series: series.LatestLts(), Constraints: cons})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *localServerSuite) TestPrecheckInstanceUnsatisfiableConstraints(c *gc.C) {
+	env := s.Open(c, s.env.Config())
+	cons := constraints.MustParse("cores=128")
+	err := env.PrecheckInstance(environs.PrecheckInstanceParams{Series: series.LatestLts(), Constraints: cons})
+	c.Assert(err, gc.ErrorMatches, `no instance types in .* matching constraints "cores=128"`)
+}
+
 func (t *localServerSuite) TestPrecheckInstanceAvailZone(c *gc.C) {
 	placement := "zone=test-available"
 	err := t.env.PrecheckInstance(environs.PrecheckInstanceParams{Series: series.LatestLts(), Placement: placement})
@@ -1656,6 +1825,71 @@ func (s *localServerSuite) TestEnsureGroup(c *gc.C) {
 	c.Check(obtainedRulesThirdTime, jc.SameContents, obtainedRules)
 }
 
+func ingressPortRanges(rules []neutron.SecurityGroupRuleV2) []network.PortRange {
+	var ranges []network.PortRange
+	for _, r := range rules {
+		if r.Direction != "ingress" {
+			continue
+		}
+		var portRange network.PortRange
+		if r.IPProtocol != nil {
+			portRange.Protocol = *r.IPProtocol
+		}
+		if r.PortRangeMin != nil {
+			portRange.FromPort = *r.PortRangeMin
+		}
+		if r.PortRangeMax != nil {
+			portRange.ToPort = *r.PortRangeMax
+		}
+		ranges = append(ranges, portRange)
+	}
+	return ranges
+}
+
+func (s *localServerSuite) TestSetUpGlobalGroup(c *gc.C) {
+	group, err := openstack.SetUpGlobalGroup(s.env, "juju group", 17777, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ingressPortRanges(group.Rules), jc.SameContents, []network.PortRange{
+		{Protocol: "tcp", FromPort: 22, ToPort: 22},
+		{Protocol: "tcp", FromPort: 22, ToPort: 22},
+		{Protocol: "tcp", FromPort: 17777, ToPort: 17777},
+		{Protocol: "tcp", FromPort: 17777, ToPort: 17777},
+		{Protocol: "tcp", FromPort: 1, ToPort: 65535},
+		{Protocol: "tcp", FromPort: 1, ToPort: 65535},
+		{Protocol: "udp", FromPort: 1, ToPort: 65535},
+		{Protocol: "udp", FromPort: 1, ToPort: 65535},
+		{Protocol: "icmp"},
+		{Protocol: "icmp"},
+	})
+}
+
+func (s *localServerSuite) TestSetUpGlobalGroupRestrictInternalTraffic(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{"restrict-internal-traffic": true})
+	group, err := openstack.SetUpGlobalGroup(env, "juju group", 17777, 27017)
+	c.Assert(err, jc.ErrorIsNil)
+	restricted := []network.PortRange{
+		{Protocol: "tcp", FromPort: 22, ToPort: 22},
+		{Protocol: "tcp", FromPort: 22, ToPort: 22},
+		{Protocol: "tcp", FromPort: 17777, ToPort: 17777},
+		{Protocol: "tcp", FromPort: 17777, ToPort: 17777},
+		{Protocol: "tcp", FromPort: 27017, ToPort: 27017},
+		{Protocol: "tcp", FromPort: 27017, ToPort: 27017},
+	}
+	c.Check(ingressPortRanges(group.Rules), jc.SameContents, restricted)
+
+	// Machines other than controllers don't know the state port,
+	// and keep the rule a controller set up.
+	group, err = openstack.SetUpGlobalGroup(env, "juju group", 17777, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ingressPortRanges(group.Rules), jc.SameContents, restricted)
+
+	// Turning the restriction off again restores the permissive rules.
+	env = s.openEnviron(c, coretesting.Attrs{"restrict-internal-traffic": false})
+	group, err = openstack.SetUpGlobalGroup(env, "juju group", 17777, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ingressPortRanges(group.Rules), gc.HasLen, 10)
+}
+
 // TestMatchingGroup checks that you receive the group you expected.  matchingGroup()
 // is used by the firewaller when opening and closing ports.  Unit test in response to bug 1675799.
 func (s *localServerSuite) TestMatchingGroup(c *gc.C) {
@@ -1692,6 +1926,161 @@ func (s *localServerSuite) TestMatchingGroup(c *gc.C) {
 	c.Assert(group2.Id, gc.Equals, groupMatched.Id)
 }
 
+func ingressRuleInfo(rules []neutron.SecurityGroupRuleV2) []neutron.RuleInfoV2 {
+	var ingress []neutron.SecurityGroupRuleV2
+	for _, r := range rules {
+		if r.Direction == "ingress" {
+			ingress = append(ingress, r)
+		}
+	}
+	return ruleToRuleInfo(ingress)
+}
+
+func (s *localServerSuite) TestReconcilePorts(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{"firewall-mode": config.FwInstance})
+	testing.AssertStartInstance(c, env, s.ControllerUUID, "100")
+	machineRegexp := openstack.MachineGroupRegexp(env, "100")
+
+	err := openstack.ReconcilePorts(env, machineRegexp, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80),
+		network.MustNewIngressRule("tcp", 443, 443, "10.0.0.0/8", "192.168.0.0/16"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	group, err := openstack.MatchingGroup(env, machineRegexp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ingressRuleInfo(group.Rules), jc.SameContents, []neutron.RuleInfoV2{{
+		Direction:      "ingress",
+		IPProtocol:     "tcp",
+		PortRangeMin:   80,
+		PortRangeMax:   80,
+		RemoteIPPrefix: "0.0.0.0/0",
+		EthernetType:   "IPv4",
+	}, {
+		Direction:      "ingress",
+		IPProtocol:     "tcp",
+		PortRangeMin:   443,
+		PortRangeMax:   443,
+		RemoteIPPrefix: "10.0.0.0/8",
+		EthernetType:   "IPv4",
+	}, {
+		Direction:      "ingress",
+		IPProtocol:     "tcp",
+		PortRangeMin:   443,
+		PortRangeMax:   443,
+		RemoteIPPrefix: "192.168.0.0/16",
+		EthernetType:   "IPv4",
+	}})
+
+	// Only the rules that differ are touched.
+	err = openstack.ReconcilePorts(env, machineRegexp, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 443, 443, "10.0.0.0/8"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	group, err = openstack.MatchingGroup(env, machineRegexp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ingressRuleInfo(group.Rules), jc.SameContents, []neutron.RuleInfoV2{{
+		Direction:      "ingress",
+		IPProtocol:     "tcp",
+		PortRangeMin:   443,
+		PortRangeMax:   443,
+		RemoteIPPrefix: "10.0.0.0/8",
+		EthernetType:   "IPv4",
+	}})
+
+	// Reconciling to nothing removes every rule Juju added to the
+	// machine group, but leaves the egress rules and the base rules in
+	// the model group alone.
+	err = openstack.ReconcilePorts(env, machineRegexp, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	group, err = openstack.MatchingGroup(env, machineRegexp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ingressRuleInfo(group.Rules), gc.HasLen, 0)
+	c.Check(group.Rules, gc.HasLen, 2)
+
+	modelGroup, err := openstack.MatchingGroup(env,
+		fmt.Sprintf("^juju-%v-%v$", s.ControllerUUID, env.Config().UUID()))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ingressRuleInfo(modelGroup.Rules), jc.DeepContains, []neutron.RuleInfoV2{{
+		Direction:      "ingress",
+		IPProtocol:     "tcp",
+		PortRangeMin:   22,
+		PortRangeMax:   22,
+		RemoteIPPrefix: "0.0.0.0/0",
+		EthernetType:   "IPv4",
+	}})
+}
+
+func (s *localServerSuite) TestClosePortsRemovesEveryMatchingCIDR(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{"firewall-mode": config.FwGlobal})
+	testing.AssertStartInstance(c, env, s.ControllerUUID, "100")
+	fw := openstack.GetFirewaller(env)
+
+	rule := network.MustNewIngressRule("tcp", 443, 443, "10.0.0.0/8", "192.168.0.0/16")
+	err := fw.OpenPorts([]network.IngressRule{rule})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err := fw.IngressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rules, jc.DeepEquals, []network.IngressRule{rule})
+
+	err = fw.ClosePorts([]network.IngressRule{rule})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fw.IngressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rules, gc.HasLen, 0)
+}
+
+func (s *localServerSuite) TestOpenPortsLeavesExistingRules(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{"firewall-mode": config.FwInstance})
+	inst, _ := testing.AssertStartInstance(c, env, s.ControllerUUID, "100")
+	fw := openstack.GetFirewaller(env)
+	machineRegexp := openstack.MachineGroupRegexp(env, "100")
+
+	rules := []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80),
+		network.MustNewIngressRule("tcp", 443, 443, "2001:db8::/32"),
+	}
+	err := fw.OpenInstancePorts(inst, "100", rules)
+	c.Assert(err, jc.ErrorIsNil)
+	group, err := openstack.MatchingGroup(env, machineRegexp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ingressRuleInfo(group.Rules), jc.SameContents, []neutron.RuleInfoV2{{
+		Direction:      "ingress",
+		IPProtocol:     "tcp",
+		PortRangeMin:   80,
+		PortRangeMax:   80,
+		RemoteIPPrefix: "0.0.0.0/0",
+		EthernetType:   "IPv4",
+	}, {
+		Direction:      "ingress",
+		IPProtocol:     "tcp",
+		PortRangeMin:   443,
+		PortRangeMax:   443,
+		RemoteIPPrefix: "2001:db8::/32",
+		EthernetType:   "IPv6",
+	}})
+	ruleIds := make(set.Strings)
+	for _, r := range group.Rules {
+		ruleIds.Add(r.Id)
+	}
+
+	// Opening the same ports again neither fails nor recreates
+	// the existing rules.
+	err = fw.OpenInstancePorts(inst, "100", rules)
+	c.Assert(err, jc.ErrorIsNil)
+	group, err = openstack.MatchingGroup(env, machineRegexp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(group.Rules, gc.HasLen, ruleIds.Size())
+	for _, r := range group.Rules {
+		c.Check(ruleIds.Contains(r.Id), jc.IsTrue)
+	}
+
+	err = fw.CloseInstancePorts(inst, "100", rules[1:])
+	c.Assert(err, jc.ErrorIsNil)
+	obtained, err := fw.InstanceIngressRules(inst, "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(obtained, jc.DeepEquals, rules[:1])
+}
+
 // localHTTPSServerSuite contains tests that run against an Openstack service
 // double connected on an HTTPS port with a self-signed certificate. This
 // service is set up and torn down for every test.  This should only test
@@ -1970,6 +2359,50 @@ func (s *localServerSuite) TestAllInstancesIgnoresOtherMachines(c *gc.C) {
 	c.Check(insts, gc.HasLen, 1)
 }
 
+func (s *localServerSuite) TestAllInstancesFiltersByModelMetadata(c *gc.C) {
+	inst, _ := testing.AssertStartInstance(c, s.env, s.ControllerUUID, "100")
+
+	// A server belonging to another model is ignored, even though its
+	// name looks like one of ours; a server tagged with our model UUID
+	// is included, whatever it is called.
+	novaClient := openstack.GetNovaClient(s.env)
+	_, err := novaClient.RunServer(nova.RunServerOpts{
+		Name:     fmt.Sprintf("juju-%s-machine-1", s.TestConfig["name"]),
+		FlavorId: "1",
+		ImageId:  "1",
+		Networks: []nova.ServerNetworks{{NetworkId: "1"}},
+		Metadata: map[string]string{tags.JujuModel: utils.MustNewUUID().String()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := novaClient.RunServer(nova.RunServerOpts{
+		Name:     "inventory-managed",
+		FlavorId: "1",
+		ImageId:  "1",
+		Networks: []nova.ServerNetworks{{NetworkId: "1"}},
+		Metadata: map[string]string{tags.JujuModel: s.env.Config().UUID()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	insts, err := s.env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	var ids []instance.Id
+	for _, inst := range insts {
+		ids = append(ids, inst.Id())
+	}
+	c.Check(ids, jc.SameContents, []instance.Id{inst.Id(), instance.Id(other.Id)})
+}
+
+func (s *localServerSuite) TestStartInstanceSendsResourceTags(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{"resource-tags": "owner=ops cost-centre=42"})
+	inst, _ := testing.AssertStartInstance(c, env, s.ControllerUUID, "100")
+	c.Assert(openstack.InstanceServerDetail(inst).Metadata, jc.DeepEquals, map[string]string{
+		tags.JujuModel:      env.Config().UUID(),
+		tags.JujuController: s.ControllerUUID,
+		"owner":             "ops",
+		"cost-centre":       "42",
+	})
+}
+
 func (s *localServerSuite) TestResolveNetworkUUID(c *gc.C) {
 	var sampleUUID = "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"
 	networkId, err := openstack.ResolveNetwork(s.env, sampleUUID, false)
@@ -2738,6 +3171,56 @@ func (s *noSwiftSuite) TestBootstrap(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *noNeutronSuite) TestStopInstanceDisassociatesFloatingIP(c *gc.C) {
+	inst, _ := testing.AssertStartInstance(c, s.env, coretesting.ControllerTag.Id(), "100")
+	novaClient := openstack.GetNovaClient(s.env)
+	fip, err := novaClient.AllocateFloatingIP()
+	c.Assert(err, jc.ErrorIsNil)
+	err = novaClient.AddServerFloatingIP(string(inst.Id()), fip.IP)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.env.StopInstances(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The floating IP is still allocated, but no longer assigned.
+	fips, err := novaClient.ListFloatingIPs()
+	c.Assert(err, jc.ErrorIsNil)
+	found := findFloatingIP(fips, fip.IP)
+	c.Assert(found, gc.NotNil)
+	if found.InstanceId != nil {
+		c.Assert(*found.InstanceId, gc.Equals, "")
+	}
+}
+
+func (s *noNeutronSuite) TestStopInstanceReleasesFloatingIP(c *gc.C) {
+	cfg, err := s.env.Config().Apply(coretesting.Attrs{"release-floating-ip": true})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.env.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	inst, _ := testing.AssertStartInstance(c, s.env, coretesting.ControllerTag.Id(), "100")
+	novaClient := openstack.GetNovaClient(s.env)
+	fip, err := novaClient.AllocateFloatingIP()
+	c.Assert(err, jc.ErrorIsNil)
+	err = novaClient.AddServerFloatingIP(string(inst.Id()), fip.IP)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.env.StopInstances(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	fips, err := novaClient.ListFloatingIPs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(findFloatingIP(fips, fip.IP), gc.IsNil)
+}
+
+func findFloatingIP(fips []nova.FloatingIP, ip string) *nova.FloatingIP {
+	for _, fip := range fips {
+		if fip.IP == ip {
+			return &fip
+		}
+	}
+	return nil
+}
+
 func newFullOpenstackService(cred *identity.Credentials, auth identity.AuthMode, useTSL bool) (*openstackservice.Openstack, []string) {
 	service, logMsg := openstackservice.New(cred, auth, useTSL)
 	service.UseNeutronNetworking()
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/utils"
 	"github.com/juju/utils/set"
+	gooseerrors "gopkg.in/goose.v2/errors"
 	"gopkg.in/goose.v2/neutron"
 	"gopkg.in/goose.v2/nova"
 
@@ -25,6 +26,11 @@ type Networking interface {
 	// to the specified instance.
 	AllocatePublicIP(instance.Id) (*string, error)
 
+	// RemovePublicIPs disassociates the public (floating) IPs
+	// assigned to the specified instances, and releases them
+	// back to the pool if release is true.
+	RemovePublicIPs(ids []instance.Id, release bool) error
+
 	// DefaultNetworks returns the set of networks that should be
 	// added by default to all new instances.
 	DefaultNetworks() ([]nova.ServerNetworks, error)
@@ -95,6 +101,14 @@ func (n *switchingNetworking) AllocatePublicIP(instId instance.Id) (*string, err
 	return n.networking.AllocatePublicIP(instId)
 }
 
+// RemovePublicIPs is part of the Networking interface.
+func (n *switchingNetworking) RemovePublicIPs(ids []instance.Id, release bool) error {
+	if err := n.initNetworking(); err != nil {
+		return errors.Trace(err)
+	}
+	return n.networking.RemovePublicIPs(ids, release)
+}
+
 // DefaultNetworks is part of the Networking interface.
 func (n *switchingNetworking) DefaultNetworks() ([]nova.ServerNetworks, error) {
 	if err := n.initNetworking(); err != nil {
@@ -232,6 +246,50 @@ func (n *NeutronNetworking) AllocatePublicIP(instId instance.Id) (*string, error
 	return nil, lastErr
 }
 
+// RemovePublicIPs is part of the Networking interface.
+func (n *NeutronNetworking) RemovePublicIPs(ids []instance.Id, release bool) error {
+	if !release {
+		// Neutron disassociates a floating IP when the port it is
+		// assigned to is deleted along with the instance.
+		return nil
+	}
+	// Floating IPs are reported in the server's addresses, which is
+	// how we find those assigned to the instances.
+	addresses := set.NewStrings()
+	for _, id := range ids {
+		server, err := n.env.nova().GetServer(string(id))
+		if gooseerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, addrs := range server.Addresses {
+			for _, addr := range addrs {
+				addresses.Add(addr.Address)
+			}
+		}
+	}
+	if addresses.IsEmpty() {
+		return nil
+	}
+	neutronClient := n.env.neutron()
+	fips, err := neutronClient.ListFloatingIPsV2(projectIdFilter(n.env.client().TenantId()))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, fip := range fips {
+		if !addresses.Contains(fip.IP) {
+			continue
+		}
+		logger.Debugf("releasing floating IP %v", fip.IP)
+		if err := neutronClient.DeleteFloatingIPV2(fip.Id); err != nil {
+			logger.Warningf("cannot release floating IP %v: %v", fip.IP, err)
+		}
+	}
+	return nil
+}
+
 // externalNetworkFilter returns a neutron.Filter to match Neutron Networks with
 // router:external = true.
 func externalNetworkFilter() *neutron.Filter {
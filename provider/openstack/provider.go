@@ -7,7 +7,10 @@ package openstack
 
 import (
 	"fmt"
+	"math/rand"
+	"net"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +20,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/retry"
 	"github.com/juju/utils"
+	"github.com/juju/utils/arch"
 	"github.com/juju/utils/clock"
 	"github.com/juju/version"
 	"gopkg.in/goose.v2/cinder"
@@ -47,6 +51,10 @@ import (
 
 var logger = loggo.GetLogger("juju.provider.openstack")
 
+// ErrEnvironDestroyed is returned by operations attempted on an
+// environ after it has been destroyed.
+var ErrEnvironDestroyed = errors.New("environ has been destroyed")
+
 type EnvironProvider struct {
 	environs.ProviderCredentials
 	Configurator      ProviderConfigurator
@@ -135,6 +143,33 @@ var shortAttempt = utils.AttemptStrategy{
 	Delay: 200 * time.Millisecond,
 }
 
+// attemptJitter is the fraction by which jitteredAttempt varies the
+// delay of an attempt strategy, in either direction.
+const attemptJitter = 0.2
+
+var (
+	// jitterMutex guards jitterSource, which is not safe for
+	// concurrent use.
+	jitterMutex sync.Mutex
+
+	// jitterSource supplies the randomness used by jitteredAttempt.
+	// Tests may replace it with a seeded source.
+	jitterSource = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// jitteredAttempt returns a copy of the given strategy with its delay
+// randomly varied by up to attemptJitter, so that concurrent callers
+// polling the cloud API do not retry in lockstep.
+func jitteredAttempt(strategy utils.AttemptStrategy) utils.AttemptStrategy {
+	jitterMutex.Lock()
+	r := jitterSource.Float64()
+	jitterMutex.Unlock()
+	lower := (1.0 - attemptJitter) * float64(strategy.Delay)
+	window := (2.0 * attemptJitter) * float64(strategy.Delay)
+	strategy.Delay = time.Duration(lower + r*window)
+	return strategy
+}
+
 // Version is part of the EnvironProvider interface.
 func (EnvironProvider) Version() int {
 	return 0
@@ -275,6 +310,7 @@ type Environ struct {
 	novaUnlocked    *nova.Client
 	neutronUnlocked *neutron.Client
 	volumeURL       *url.URL
+	destroyed       bool
 
 	// keystoneImageDataSource caches the result of getKeystoneImageSource.
 	keystoneImageDataSourceMutex sync.Mutex
@@ -341,6 +377,11 @@ func (inst *openstackInstance) Id() instance.Id {
 	return instance.Id(inst.getServerDetail().Id)
 }
 
+// Name returns the name of the server.
+func (inst *openstackInstance) Name() string {
+	return inst.getServerDetail().Name
+}
+
 func (inst *openstackInstance) Status() instance.InstanceStatus {
 	instStatus := inst.getServerDetail().Status
 	jujuStatus := status.Pending
@@ -493,6 +534,18 @@ func (e *Environ) neutron() *neutron.Client {
 	return neutron
 }
 
+// checkNotDestroyed returns ErrEnvironDestroyed if Destroy has
+// completed on the environ.
+func (e *Environ) checkNotDestroyed() error {
+	e.ecfgMutex.Lock()
+	destroyed := e.destroyed
+	e.ecfgMutex.Unlock()
+	if destroyed {
+		return ErrEnvironDestroyed
+	}
+	return nil
+}
+
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
@@ -608,21 +661,29 @@ func (e *Environ) PrecheckInstance(args environs.PrecheckInstanceParams) error {
 	if _, err := e.deriveAvailabilityZone(args.Placement, args.VolumeAttachments); err != nil {
 		return errors.Trace(err)
 	}
-	if !args.Constraints.HasInstanceType() {
+	cons := args.Constraints
+	if !cons.HasInstanceType() && cons.Mem == nil && cons.CpuCores == nil && cons.RootDisk == nil {
+		// Any flavour will do.
 		return nil
 	}
-	// Constraint has an instance-type constraint so let's see if it is valid.
-	novaClient := e.nova()
-	flavors, err := novaClient.ListFlavorsDetail()
+	arches := arch.AllSupportedArches
+	if cons.HasArch() {
+		arches = []string{*cons.Arch}
+	}
+	instanceTypes, err := e.flavorInstanceTypes(arches, cons.VirtType)
 	if err != nil {
 		return err
 	}
-	for _, flavor := range flavors {
-		if flavor.Name == *args.Constraints.InstanceType {
-			return nil
-		}
+	if cons.HasInstanceType() {
+		// Constraint has an instance-type constraint so let's see if it is valid.
+		return checkInstanceTypeExists(*cons.InstanceType, instanceTypes)
 	}
-	return errors.Errorf("invalid Openstack flavour %q specified", *args.Constraints.InstanceType)
+	// Check that some flavour satisfies the constraints, rather than
+	// waiting for the provisioner to fail to start the machine.
+	if _, err := instances.MatchingInstanceTypes(instanceTypes, e.cloud.Region, cons); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
 }
 
 // PrepareForBootstrap is part of the Environ interface.
@@ -644,6 +705,21 @@ func (e *Environ) PrepareForBootstrap(ctx environs.BootstrapContext) error {
 `,
 		)
 	}
+	if err := e.validateNetworks(); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// validateNetworks checks that the configured networks exist. Network
+// UUIDs are accepted without being looked up, as when starting an
+// instance.
+func (e *Environ) validateNetworks() error {
+	for _, name := range e.ecfg().networks() {
+		if _, err := e.networking.ResolveNetwork(name, false); err != nil {
+			return errors.Annotatef(err, "invalid network %q", name)
+		}
+	}
 	return nil
 }
 
@@ -682,18 +758,39 @@ func (e *Environ) ControllerInstances(controllerUUID string) ([]instance.Id, err
 		return nil, errors.Trace(err)
 	}
 	ids := make([]instance.Id, 0, 1)
+	var inactive []instance.Id
 	for _, instance := range instances {
 		detail := instance.(*openstackInstance).getServerDetail()
-		if detail.Metadata[tags.JujuIsController] == "true" {
-			ids = append(ids, instance.Id())
+		if detail.Metadata[tags.JujuIsController] != "true" {
+			continue
+		}
+		// Instances that have been shut off or suspended cannot
+		// serve the API, so their addresses must not be used.
+		if !isRunningServer(*detail) {
+			inactive = append(inactive, instance.Id())
+			continue
 		}
+		ids = append(ids, instance.Id())
 	}
 	if len(ids) == 0 {
+		if len(inactive) > 0 {
+			return nil, errors.Trace(inactiveControllersError{inactive})
+		}
 		return nil, environs.ErrNoInstances
 	}
 	return ids, nil
 }
 
+// isRunningServer reports whether the server is active, or is still
+// being built and so will become active.
+func isRunningServer(server nova.ServerDetail) bool {
+	switch server.Status {
+	case nova.StatusActive, nova.StatusBuild, nova.StatusBuildSpawning:
+		return true
+	}
+	return false
+}
+
 func (e *Environ) Config() *config.Config {
 	return e.ecfg().Config
 }
@@ -914,7 +1011,7 @@ func (e *Environ) assignPublicIP(fip *string, serverId string) (err error) {
 	}
 	// At startup nw_info is not yet cached so this may fail
 	// temporarily while the server is being built
-	for a := common.LongAttempt.Start(); a.Next(); {
+	for a := jitteredAttempt(common.LongAttempt).Start(); a.Next(); {
 		err = e.nova().AddServerFloatingIP(serverId, *fip)
 		if err == nil {
 			return nil
@@ -937,6 +1034,9 @@ func (*Environ) MaintainInstance(args environs.StartInstanceParams) error {
 
 // StartInstance is specified in the InstanceBroker interface.
 func (e *Environ) StartInstance(args environs.StartInstanceParams) (_ *environs.StartInstanceResult, err error) {
+	if err := e.checkNotDestroyed(); err != nil {
+		return nil, common.ZoneIndependentError(err)
+	}
 	if args.AvailabilityZone != "" {
 		// args.AvailabilityZone should only be set if this OpenStack
 		// supports zones; validate the zone.
@@ -986,13 +1086,15 @@ func (e *Environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 		return nil, common.ZoneIndependentError(errors.Annotate(err, "cannot make user data"))
 	}
 	logger.Debugf("openstack user data; %d bytes", len(userData))
+	if err := checkUserDataSize(userData); err != nil {
+		return nil, common.ZoneIndependentError(err)
+	}
 
 	networks, err := e.networking.DefaultNetworks()
 	if err != nil {
 		return nil, common.ZoneIndependentError(errors.Annotate(err, "getting initial networks"))
 	}
-	usingNetwork := e.ecfg().network()
-	if usingNetwork != "" {
+	for _, usingNetwork := range e.ecfg().networks() {
 		networkId, err := e.networking.ResolveNetwork(usingNetwork, false)
 		if err != nil {
 			return nil, common.ZoneIndependentError(err)
@@ -1024,14 +1126,15 @@ func (e *Environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 
 	var novaGroupNames = []nova.SecurityGroupName{}
 	if createSecurityGroups {
-		var apiPort int
+		var apiPort, statePort int
 		if args.InstanceConfig.Controller != nil {
 			apiPort = args.InstanceConfig.Controller.Config.APIPort()
+			statePort = args.InstanceConfig.Controller.Config.StatePort()
 		} else {
 			// All ports are the same so pick the first.
 			apiPort = args.InstanceConfig.APIInfo.Ports()[0]
 		}
-		groupNames, err := e.firewaller.SetUpGroups(args.ControllerUUID, args.InstanceConfig.MachineId, apiPort)
+		groupNames, err := e.firewaller.SetUpGroups(args.ControllerUUID, args.InstanceConfig.MachineId, apiPort, statePort)
 		if err != nil {
 			return nil, common.ZoneIndependentError(errors.Annotate(err, "cannot set up groups"))
 		}
@@ -1126,7 +1229,7 @@ func (e *Environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 	}
 	e.configurator.ModifyRunServerOptions(&opts)
 
-	server, err := tryStartNovaInstance(shortAttempt, e.nova(), opts)
+	server, err := tryStartNovaInstance(jitteredAttempt(shortAttempt), e.nova(), opts)
 	if err != nil {
 		// 'No valid host available' is typically a resource error,
 		// let the provisioner know it is a good idea to try another
@@ -1134,7 +1237,7 @@ func (e *Environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 		err := errors.Annotate(err, "cannot run instance")
 		zoneSpecific := isNoValidHostsError(err)
 		if !zoneSpecific {
-			err = common.ZoneIndependentError(err)
+			err = zoneIndependentError(err)
 		}
 		return nil, err
 	}
@@ -1161,7 +1264,7 @@ func (e *Environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 		var publicIP *string
 		logger.Debugf("allocating public IP address for openstack node")
 		if fip, err := e.networking.AllocatePublicIP(inst.Id()); err != nil {
-			return nil, common.ZoneIndependentError(errors.Annotate(err, "cannot allocate a public IP as needed"))
+			return nil, zoneIndependentError(errors.Annotate(err, "cannot allocate a public IP as needed"))
 		} else {
 			publicIP = fip
 			logger.Infof("allocated public IP %s", *publicIP)
@@ -1327,13 +1430,75 @@ func (e *Environ) listServers(ids []instance.Id) ([]nova.ServerDetail, error) {
 	return wantedServers, nil
 }
 
+// listServersPageSize is the number of servers requested from nova in
+// each page when listing all servers.
+var listServersPageSize = 500
+
+// listServersDetail returns the details of all servers matching
+// jujuMachineFilter. The servers are requested a page at a time, so
+// that tenants with many servers do not need a single large request.
+func (e *Environ) listServersDetail() ([]nova.ServerDetail, error) {
+	list := func(marker string, limit int) ([]nova.ServerDetail, error) {
+		filter := jujuMachineFilter()
+		filter.Set(nova.FilterLimit, strconv.Itoa(limit))
+		if marker != "" {
+			filter.Set(nova.FilterMarker, marker)
+		}
+		return e.nova().ListServersDetail(filter)
+	}
+	return listServersDetailPaged(list, listServersPageSize)
+}
+
+// listServersDetailPaged lists servers using nova's marker and limit
+// parameters, requesting pageSize servers at a time. Each page is
+// requested with the id of the last server of the previous page as its
+// marker, so pages are necessarily fetched in order. A short page does
+// not mean there are no more servers, as nova silently caps the limit
+// at its osapi_max_limit, so listing continues until a page holds no
+// servers that have not already been seen. That also guards against
+// clouds that ignore the marker.
+func listServersDetailPaged(
+	list func(marker string, limit int) ([]nova.ServerDetail, error),
+	pageSize int,
+) ([]nova.ServerDetail, error) {
+	var servers []nova.ServerDetail
+	seen := make(map[string]bool)
+	marker := ""
+	for {
+		page, err := list(marker, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		added := 0
+		for _, server := range page {
+			if seen[server.Id] {
+				continue
+			}
+			seen[server.Id] = true
+			servers = append(servers, server)
+			added++
+		}
+		if added == 0 {
+			return servers, nil
+		}
+		marker = page[len(page)-1].Id
+	}
+}
+
 // updateFloatingIPAddresses updates the instances with any floating IP address
 // that have been assigned to those instances.
 func (e *Environ) updateFloatingIPAddresses(instances map[string]instance.Instance) error {
-	servers, err := e.nova().ListServersDetail(jujuMachineFilter())
+	servers, err := e.listServersDetail()
 	if err != nil {
 		return err
 	}
+	setFloatingIPAddresses(servers, instances)
+	return nil
+}
+
+// setFloatingIPAddresses updates the instances with any floating IP
+// address that the given servers report as assigned to them.
+func setFloatingIPAddresses(servers []nova.ServerDetail, instances map[string]instance.Instance) {
 	for _, server := range servers {
 		// server.Addresses is a map with entries containing []nova.IPAddress
 		for _, net := range server.Addresses {
@@ -1348,10 +1513,52 @@ func (e *Environ) updateFloatingIPAddresses(instances map[string]instance.Instan
 			}
 		}
 	}
-	return nil
 }
 
 func (e *Environ) Instances(ids []instance.Id) ([]instance.Instance, error) {
+	results, err := e.InstancesWithErrors(ids)
+	if err != nil || len(results) == 0 {
+		return nil, err
+	}
+	insts := make([]instance.Instance, len(results))
+	found := 0
+	for i, result := range results {
+		if result.Err == nil {
+			insts[i] = result.Instance
+			found++
+		}
+	}
+	switch found {
+	case 0:
+		return nil, environs.ErrNoInstances
+	case len(ids):
+		return insts, nil
+	}
+	return insts, environs.ErrPartialInstances
+}
+
+// InstanceResult holds the outcome of looking up a single instance
+// with InstancesWithErrors.
+type InstanceResult struct {
+	// Id is the id of the instance that was requested.
+	Id instance.Id
+
+	// Instance holds the instance, if it was found.
+	Instance instance.Instance
+
+	// Err holds the reason the instance could not be returned. It
+	// satisfies errors.IsNotFound if no live server has the id.
+	Err error
+}
+
+// InstancesWithErrors returns a result for each of the given ids, in the
+// same order, holding either the instance or the reason it could not be
+// found. The returned error is non-nil only if the lookup as a whole
+// failed.
+func (e *Environ) InstancesWithErrors(ids []instance.Id) ([]InstanceResult, error) {
+	if err := e.checkNotDestroyed(); err != nil {
+		return nil, err
+	}
 	if len(ids) == 0 {
 		return nil, nil
 	}
@@ -1359,7 +1566,7 @@ func (e *Environ) Instances(ids []instance.Id) ([]instance.Instance, error) {
 	// Each request will attempt to add more instances to the requested
 	// set.
 	var foundServers []nova.ServerDetail
-	for a := shortAttempt.Start(); a.Next(); {
+	for a := jitteredAttempt(shortAttempt).Start(); a.Next(); {
 		var err error
 		foundServers, err = e.listServers(ids)
 		if err != nil {
@@ -1373,9 +1580,6 @@ func (e *Environ) Instances(ids []instance.Id) ([]instance.Instance, error) {
 		}
 	}
 	logger.Tracef("%d/%d live servers found", len(foundServers), len(ids))
-	if len(foundServers) == 0 {
-		return nil, environs.ErrNoInstances
-	}
 
 	instsById := make(map[string]instance.Instance, len(foundServers))
 	for i, server := range foundServers {
@@ -1388,22 +1592,22 @@ func (e *Environ) Instances(ids []instance.Id) ([]instance.Instance, error) {
 	}
 
 	// Update the instance structs with any floating IP address that has been assigned to the instance.
-	if e.ecfg().useFloatingIP() {
+	if len(instsById) > 0 && e.ecfg().useFloatingIP() {
 		if err := e.updateFloatingIPAddresses(instsById); err != nil {
 			return nil, err
 		}
 	}
 
-	insts := make([]instance.Instance, len(ids))
-	var err error
+	results := make([]InstanceResult, len(ids))
 	for i, id := range ids {
+		results[i].Id = id
 		if inst := instsById[string(id)]; inst != nil {
-			insts[i] = inst
+			results[i].Instance = inst
 		} else {
-			err = environs.ErrPartialInstances
+			results[i].Err = errors.NotFoundf("instance %q", id)
 		}
 	}
-	return insts, err
+	return results, nil
 }
 
 // AdoptResources is part of the Environ interface.
@@ -1475,6 +1679,9 @@ func (e *Environ) adoptVolumes(controllerTag map[string]string) ([]string, error
 
 // AllInstances returns all instances in this environment.
 func (e *Environ) AllInstances() ([]instance.Instance, error) {
+	if err := e.checkNotDestroyed(); err != nil {
+		return nil, err
+	}
 	tagFilter := tagValue{tags.JujuModel, e.ecfg().UUID()}
 	return e.allInstances(tagFilter, e.ecfg().useFloatingIP())
 }
@@ -1493,7 +1700,7 @@ type tagValue struct {
 // allControllerManagedInstances returns all instances managed by this
 // environment's controller, matching the optionally specified filter.
 func (e *Environ) allInstances(tagFilter tagValue, updateFloatingIPAddresses bool) ([]instance.Instance, error) {
-	servers, err := e.nova().ListServersDetail(jujuMachineFilter())
+	servers, err := e.listServersDetail()
 	if err != nil {
 		return nil, err
 	}
@@ -1509,9 +1716,9 @@ func (e *Environ) allInstances(tagFilter tagValue, updateFloatingIPAddresses boo
 		}
 	}
 	if updateFloatingIPAddresses {
-		if err := e.updateFloatingIPAddresses(instsById); err != nil {
-			return nil, err
-		}
+		// The servers already listed hold the floating IP addresses,
+		// so there is no need to list them again.
+		setFloatingIPAddresses(servers, instsById)
 	}
 	insts := make([]instance.Instance, 0, len(instsById))
 	for _, inst := range instsById {
@@ -1520,13 +1727,25 @@ func (e *Environ) allInstances(tagFilter tagValue, updateFloatingIPAddresses boo
 	return insts, nil
 }
 
+// Destroy is specified in the Environ interface. Once it succeeds,
+// StartInstance, Instances and AllInstances fail with ErrEnvironDestroyed;
+// calling Destroy again is a no-op.
 func (e *Environ) Destroy() error {
+	if e.checkNotDestroyed() != nil {
+		return nil
+	}
 	err := common.Destroy(e)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	// Delete all security groups remaining in the model.
-	return e.firewaller.DeleteAllModelGroups()
+	if err := e.firewaller.DeleteAllModelGroups(); err != nil {
+		return errors.Trace(err)
+	}
+	e.ecfgMutex.Lock()
+	e.destroyed = true
+	e.ecfgMutex.Unlock()
+	return nil
 }
 
 // DestroyController implements the Environ interface.
@@ -1613,12 +1832,24 @@ func rulesToRuleInfo(groupId string, rules []network.IngressRule) []neutron.Rule
 		}
 		for _, sr := range sourceCIDRs {
 			ruleInfo.RemoteIPPrefix = sr
+			ruleInfo.EthernetType = cidrEthernetType(sr)
 			result = append(result, ruleInfo)
 		}
 	}
 	return result
 }
 
+// cidrEthernetType returns the neutron ethertype, "IPv4" or "IPv6",
+// matching the address family of the given CIDR. Neutron reports the
+// ethertype of every rule, so it must be set for desired rules to
+// compare equal to the ones already in a group.
+func cidrEthernetType(cidr string) string {
+	if ip, _, err := net.ParseCIDR(cidr); err == nil && ip.To4() == nil {
+		return "IPv6"
+	}
+	return "IPv4"
+}
+
 func (e *Environ) OpenPorts(rules []network.IngressRule) error {
 	return e.firewaller.OpenPorts(rules)
 }
@@ -1641,6 +1872,11 @@ func (e *Environ) terminateInstances(ids []instance.Id) error {
 	}
 	var firstErr error
 	novaClient := e.nova()
+	// Failing to remove floating IPs must not prevent the
+	// instances from being terminated.
+	if err := e.networking.RemovePublicIPs(ids, e.ecfg().releaseFloatingIP()); err != nil {
+		logger.Warningf("cannot remove floating IPs: %v", err)
+	}
 	for _, id := range ids {
 		err := novaClient.DeleteServer(string(id))
 		if gooseerrors.IsNotFound(err) {
@@ -1648,7 +1884,7 @@ func (e *Environ) terminateInstances(ids []instance.Id) error {
 		}
 		if err != nil && firstErr == nil {
 			logger.Debugf("error terminating instance %q: %v", id, err)
-			firstErr = err
+			firstErr = classifyError(err)
 		}
 	}
 	return firstErr
@@ -5,9 +5,12 @@ package openstack
 
 import (
 	"fmt"
+	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
+	"github.com/juju/errors"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -209,6 +212,7 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			PortRangeMax:   80,
 			RemoteIPPrefix: "0.0.0.0/0",
 			ParentGroupId:  groupId,
+			EthernetType:   "IPv4",
 		}},
 	}, {
 		about: "multiple ports",
@@ -220,6 +224,7 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			PortRangeMax:   82,
 			RemoteIPPrefix: "0.0.0.0/0",
 			ParentGroupId:  groupId,
+			EthernetType:   "IPv4",
 		}},
 	}, {
 		about: "multiple port ranges",
@@ -234,6 +239,7 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			PortRangeMax:   82,
 			RemoteIPPrefix: "0.0.0.0/0",
 			ParentGroupId:  groupId,
+			EthernetType:   "IPv4",
 		}, {
 			Direction:      "ingress",
 			IPProtocol:     "tcp",
@@ -241,6 +247,7 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			PortRangeMax:   120,
 			RemoteIPPrefix: "0.0.0.0/0",
 			ParentGroupId:  groupId,
+			EthernetType:   "IPv4",
 		}},
 	}, {
 		about: "source range",
@@ -253,6 +260,7 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			PortRangeMax:   100,
 			RemoteIPPrefix: "192.168.1.0/24",
 			ParentGroupId:  groupId,
+			EthernetType:   "IPv4",
 		}, {
 			Direction:      "ingress",
 			IPProtocol:     "tcp",
@@ -260,6 +268,28 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			PortRangeMax:   100,
 			RemoteIPPrefix: "0.0.0.0/0",
 			ParentGroupId:  groupId,
+			EthernetType:   "IPv4",
+		}},
+	}, {
+		about: "ipv6 source range",
+		rules: []network.IngressRule{network.MustNewIngressRule(
+			"tcp", 443, 443, "10.0.0.0/8", "2001:db8::/32")},
+		expected: []neutron.RuleInfoV2{{
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   443,
+			PortRangeMax:   443,
+			RemoteIPPrefix: "10.0.0.0/8",
+			ParentGroupId:  groupId,
+			EthernetType:   "IPv4",
+		}, {
+			Direction:      "ingress",
+			IPProtocol:     "tcp",
+			PortRangeMin:   443,
+			PortRangeMax:   443,
+			RemoteIPPrefix: "2001:db8::/32",
+			ParentGroupId:  groupId,
+			EthernetType:   "IPv6",
 		}},
 	}}
 
@@ -584,3 +614,116 @@ func (s *providerUnitTests) TestIdentityClientVersion_ParsesGoodURL(c *gc.C) {
 	_, err = identityClientVersion("https://keystone.internal/")
 	c.Check(err, jc.ErrorIsNil)
 }
+
+func (s *localTests) TestJitteredAttempt(c *gc.C) {
+	s.PatchValue(&jitterSource, rand.New(rand.NewSource(42)))
+	strategy := utils.AttemptStrategy{
+		Total: time.Minute,
+		Delay: time.Second,
+	}
+	var delays []time.Duration
+	for i := 0; i < 100; i++ {
+		jittered := jitteredAttempt(strategy)
+		c.Assert(jittered.Total, gc.Equals, strategy.Total)
+		c.Assert(jittered.Delay >= 800*time.Millisecond, jc.IsTrue, gc.Commentf("delay %v", jittered.Delay))
+		c.Assert(jittered.Delay <= 1200*time.Millisecond, jc.IsTrue, gc.Commentf("delay %v", jittered.Delay))
+		delays = append(delays, jittered.Delay)
+	}
+	c.Assert(strategy.Delay, gc.Equals, time.Second)
+
+	// The same seed produces the same delays.
+	s.PatchValue(&jitterSource, rand.New(rand.NewSource(42)))
+	for _, delay := range delays {
+		c.Assert(jitteredAttempt(strategy).Delay, gc.Equals, delay)
+	}
+}
+
+func (s *localTests) TestListServersDetailPaged(c *gc.C) {
+	all := []nova.ServerDetail{
+		{Id: "1", Status: nova.StatusActive},
+		{Id: "2", Status: nova.StatusBuild},
+		{Id: "3", Status: "ERROR"},
+		{Id: "4", Status: nova.StatusActive},
+		{Id: "5", Status: nova.StatusDeleted},
+		{Id: "6", Status: nova.StatusBuild},
+		{Id: "7", Status: nova.StatusActive},
+	}
+	var markers []string
+	list := func(marker string, limit int) ([]nova.ServerDetail, error) {
+		c.Assert(limit, gc.Equals, 3)
+		markers = append(markers, marker)
+		start := 0
+		for i, server := range all {
+			if server.Id == marker {
+				start = i + 1
+			}
+		}
+		end := start + limit
+		if end > len(all) {
+			end = len(all)
+		}
+		return all[start:end], nil
+	}
+	servers, err := listServersDetailPaged(list, 3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(servers, jc.DeepEquals, all)
+	c.Assert(markers, jc.DeepEquals, []string{"", "3", "6", "7"})
+
+	var alive []string
+	for _, server := range servers {
+		if (&Environ{}).isAliveServer(server) {
+			alive = append(alive, server.Id)
+		}
+	}
+	c.Assert(alive, jc.DeepEquals, []string{"1", "2", "4", "6", "7"})
+}
+
+func (s *localTests) TestListServersDetailPagedCappedLimit(c *gc.C) {
+	// Nova caps the limit at osapi_max_limit without saying so; short
+	// pages must not stop the listing.
+	all := []nova.ServerDetail{{Id: "1"}, {Id: "2"}, {Id: "3"}, {Id: "4"}, {Id: "5"}}
+	var markers []string
+	list := func(marker string, limit int) ([]nova.ServerDetail, error) {
+		markers = append(markers, marker)
+		start := 0
+		for i, server := range all {
+			if server.Id == marker {
+				start = i + 1
+			}
+		}
+		end := start + 2
+		if end > len(all) {
+			end = len(all)
+		}
+		return all[start:end], nil
+	}
+	servers, err := listServersDetailPaged(list, 500)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(servers, jc.DeepEquals, all)
+	c.Assert(markers, jc.DeepEquals, []string{"", "2", "4", "5"})
+}
+
+func (s *localTests) TestListServersDetailPagedIgnoredMarker(c *gc.C) {
+	// A cloud that ignores the marker returns the same page again;
+	// listing stops rather than looping forever.
+	calls := 0
+	list := func(marker string, limit int) ([]nova.ServerDetail, error) {
+		calls++
+		return []nova.ServerDetail{{Id: "1"}, {Id: "2"}}, nil
+	}
+	servers, err := listServersDetailPaged(list, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(servers, gc.HasLen, 2)
+	c.Assert(calls, gc.Equals, 2)
+}
+
+func (s *localTests) TestListServersDetailPagedError(c *gc.C) {
+	list := func(marker string, limit int) ([]nova.ServerDetail, error) {
+		if marker != "" {
+			return nil, errors.New("boom")
+		}
+		return []nova.ServerDetail{{Id: "1"}}, nil
+	}
+	_, err := listServersDetailPaged(list, 1)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -5,6 +5,9 @@
 package openstack
 
 import (
+	"encoding/base64"
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/utils"
 	jujuos "github.com/juju/utils/os"
@@ -25,3 +28,35 @@ func (OpenstackRenderer) Render(cfg cloudinit.CloudConfig, os jujuos.OSType) ([]
 		return nil, errors.Errorf("Cannot encode userdata for OS: %s", os.String())
 	}
 }
+
+// userDataSizeLimit is the maximum size in bytes of the base64-encoded
+// user data that nova will accept for an instance.
+var userDataSizeLimit = 65535
+
+// UserDataTooLargeError is returned when the user data composed for an
+// instance is larger than nova will accept.
+type UserDataTooLargeError struct {
+	// Size is the size in bytes of the encoded user data.
+	Size int
+
+	// Limit is the maximum size in bytes that nova accepts.
+	Limit int
+}
+
+// Error is part of the error interface.
+func (e *UserDataTooLargeError) Error() string {
+	return fmt.Sprintf(
+		"user data is %d bytes when encoded, exceeding the nova limit of %d bytes",
+		e.Size, e.Limit,
+	)
+}
+
+// checkUserDataSize returns a *UserDataTooLargeError if the given user
+// data, once base64-encoded for nova, exceeds userDataSizeLimit.
+func checkUserDataSize(userData []byte) error {
+	size := base64.StdEncoding.EncodedLen(len(userData))
+	if size > userDataSizeLimit {
+		return &UserDataTooLargeError{Size: size, Limit: userDataSizeLimit}
+	}
+	return nil
+}
@@ -51,3 +51,16 @@ func (s *UserdataSuite) TestOpenstackUnknownOS(c *gc.C) {
 	c.Assert(result, gc.IsNil)
 	c.Assert(err, gc.ErrorMatches, "Cannot encode userdata for OS: GenericLinux")
 }
+
+func (s *UserdataSuite) TestCheckUserDataSize(c *gc.C) {
+	s.PatchValue(openstack.UserDataSizeLimit, 8)
+
+	// 6 bytes encode to 8 bytes of base64.
+	err := openstack.CheckUserDataSize([]byte("123456"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = openstack.CheckUserDataSize([]byte("1234567"))
+	c.Assert(err, gc.FitsTypeOf, &openstack.UserDataTooLargeError{})
+	c.Assert(err, jc.DeepEquals, &openstack.UserDataTooLargeError{Size: 12, Limit: 8})
+	c.Assert(err, gc.ErrorMatches, "user data is 12 bytes when encoded, exceeding the nova limit of 8 bytes")
+}
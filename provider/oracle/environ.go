@@ -650,6 +650,7 @@ func (o *OracleEnviron) ConstraintsValidator() (constraints.Validator, error) {
 		constraints.CpuPower,
 		constraints.RootDisk,
 		constraints.VirtType,
+		constraints.Zones,
 	}
 
 	// we choose to use the default validator implementation
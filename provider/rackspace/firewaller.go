@@ -69,7 +69,7 @@ func (c *rackspaceFirewaller) GetSecurityGroups(ids ...instance.Id) ([]string, e
 }
 
 // SetUpGroups implements OpenstackFirewaller interface.
-func (c *rackspaceFirewaller) SetUpGroups(controllerUUID, machineId string, apiPort int) ([]string, error) {
+func (c *rackspaceFirewaller) SetUpGroups(controllerUUID, machineId string, apiPort, statePort int) ([]string, error) {
 	return nil, nil
 }
 
@@ -53,9 +53,11 @@ func (c *rackspaceConfigurator) GetCloudConfig(args environs.StartInstanceParams
 // GetConfigDefaults implements ProviderConfigurator interface.
 func (c *rackspaceConfigurator) GetConfigDefaults() schema.Defaults {
 	return schema.Defaults{
-		"use-floating-ip":      false,
-		"use-default-secgroup": false,
-		"network":              "",
-		"external-network":     "",
+		"use-floating-ip":           false,
+		"use-default-secgroup":      false,
+		"network":                   "",
+		"external-network":          "",
+		"restrict-internal-traffic": false,
+		"release-floating-ip":       false,
 	}
 }
@@ -155,6 +155,26 @@ func (st *State) AddOneMachine(template MachineTemplate) (*Machine, error) {
 	return ms[0], nil
 }
 
+// AddOneMachineWithWarnings behaves like AddOneMachine, but also
+// reports, as warnings, any model constraints which the template's
+// constraints override. No warnings are reported for a template with
+// a placement directive, as model constraints are ignored for those.
+func (st *State) AddOneMachineWithWarnings(template MachineTemplate) (*Machine, []constraints.MergeConflict, error) {
+	var warnings []constraints.MergeConflict
+	if template.Placement == "" {
+		var err error
+		warnings, err = st.constraintsConflicts(template.Constraints)
+		if err != nil {
+			return nil, nil, errors.Annotate(err, "cannot add a new machine")
+		}
+	}
+	m, err := st.AddOneMachine(template)
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, warnings, nil
+}
+
 // AddMachines adds new machines configured according to the
 // given templates.
 func (st *State) AddMachines(templates ...MachineTemplate) (_ []*Machine, err error) {
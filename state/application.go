@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/schema"
@@ -27,6 +28,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/status"
 )
 
@@ -56,6 +58,7 @@ type applicationDoc struct {
 	TxnRevno             int64      `bson:"txn-revno"`
 	MetricCredentials    []byte     `bson:"metric-credentials"`
 	PasswordHash         string     `bson:"passwordhash"`
+	PasswordChanged      time.Time  `bson:"passwordchanged,omitempty"`
 }
 
 func newApplication(st *State, doc *applicationDoc) *Application {
@@ -307,6 +310,69 @@ func (a *Application) destroyOps(destroyStorage, removeOffers bool) ([]txn.Op, e
 	}), nil
 }
 
+// RemoveApplicationForce removes a Dying application that has no units
+// left, together with its settings and any relations that still refer to
+// it. It is intended for applications left Dying because their last
+// references were never cleaned up; relations with units in scope must
+// still be left by those units first. An Alive application must be
+// destroyed before it can be force-removed.
+func (st *State) RemoveApplicationForce(name string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot force remove application %q", name)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		app, err := st.Application(name)
+		if errors.IsNotFound(err) && attempt > 0 {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return app.forceRemoveOps()
+	}
+	return st.db().Run(buildTxn)
+}
+
+// forceRemoveOps returns the operations required to remove the Dying
+// application, and its relations, without waiting for its unit and
+// relation counts to drop to zero.
+func (a *Application) forceRemoveOps() ([]txn.Op, error) {
+	if a.doc.Life == Alive {
+		return nil, errors.New("application is alive")
+	}
+	// Any units still in the model refer to the application when they
+	// are removed, so they must be gone first.
+	units, err := a.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(units); n > 0 {
+		return nil, errors.Errorf("application has %d unit%s", n, plural(n))
+	}
+	rels, err := a.Relations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := []txn.Op{minUnitsRemoveOp(a.st, a.doc.Name)}
+	for _, rel := range rels {
+		if rel.doc.UnitCount > 0 {
+			return nil, errors.Errorf("relation %q has units in scope", rel)
+		}
+		relOps, err := rel.forceRemoveOps(a.doc.Name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, relOps...)
+	}
+	resOps, err := removeResourcesOps(a.st, a.doc.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, resOps...)
+	removeOps, err := a.removeOps(bson.D{{"life", bson.D{{"$ne", Alive}}}})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(ops, removeOps...), nil
+}
+
 func removeResourcesOps(st *State, applicationID string) ([]txn.Op, error) {
 	persist, err := st.ResourcesPersistence()
 	if errors.IsNotSupported(err) {
@@ -1593,6 +1659,36 @@ func (a *Application) AddUnit(args AddUnitParams) (unit *Unit, err error) {
 	return a.st.Unit(name)
 }
 
+// AddUnitWithPlacement adds a new principal unit to the application and
+// assigns it according to the given placement: a machine id such as
+// "0", a container type and host machine such as "lxd:2", a container
+// type alone for a container on a new machine, or "" for a new
+// machine. If the unit cannot be assigned, it is removed again.
+func (a *Application) AddUnitWithPlacement(placement string) (_ *Unit, err error) {
+	p, err := instance.ParsePlacement(placement)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot add unit to application %q", a)
+	}
+	unit, err := a.AddUnit(AddUnitParams{})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Any new machine is added in the same transaction that assigns the
+	// unit, so only the unit needs removing if assignment fails.
+	if p == nil {
+		err = a.st.AssignUnit(unit, AssignNew)
+	} else {
+		err = unit.assignWithPlacement(p)
+	}
+	if err != nil {
+		if err := unit.Destroy(); err != nil {
+			logger.Errorf("cannot remove unassigned unit %q: %v", unit, err)
+		}
+		return nil, errors.Annotatef(err, "cannot assign unit %q", unit)
+	}
+	return unit, nil
+}
+
 // removeUnitOps returns the operations necessary to remove the supplied unit,
 // assuming the supplied asserts apply to the unit document.
 func (a *Application) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
@@ -1802,6 +1898,65 @@ func (a *Application) UpdateCharmConfig(changes charm.Settings) error {
 	return err
 }
 
+// CharmConfigRevision returns the revision of the application's charm
+// config settings. The revision increases every time the settings
+// change, and can be passed to UpdateCharmConfigAtRevision to make a
+// read-modify-write of the settings safe against concurrent updates.
+func (a *Application) CharmConfigRevision() (int64, error) {
+	revision, err := readSettingsVersion(a.st.db(), settingsC, a.charmConfigKey())
+	if err != nil {
+		return 0, errors.Annotatef(err, "cannot read config revision for application %q", a.doc.Name)
+	}
+	return revision, nil
+}
+
+// UpdateCharmConfigAtRevision changes an application's charm config
+// settings as UpdateCharmConfig does, but only if the settings are still
+// at the expected revision. If they have been changed since, an error
+// satisfying IsSettingsRevisionConflictError is returned and nothing
+// is written.
+func (a *Application) UpdateCharmConfigAtRevision(changes charm.Settings, expectedRevision int64) error {
+	charm, _, err := a.Charm()
+	if err != nil {
+		return err
+	}
+	changes, err = charm.Config().ValidateSettings(changes)
+	if err != nil {
+		return err
+	}
+	node, err := readSettings(a.st.db(), settingsC, a.charmConfigKey())
+	if err != nil {
+		return err
+	}
+	if node.version != expectedRevision {
+		return &ErrSettingsRevisionConflict{Expected: expectedRevision, Actual: node.version}
+	}
+	for name, value := range changes {
+		if value == nil {
+			node.Delete(name)
+		} else {
+			node.Set(name, value)
+		}
+	}
+	_, ops := node.settingsUpdateOps()
+	if len(ops) == 0 {
+		return nil
+	}
+	ops[0].Assert = bson.D{{"version", expectedRevision}}
+	err = a.st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		actual, err := readSettingsVersion(a.st.db(), settingsC, a.charmConfigKey())
+		if err != nil {
+			return errors.Annotatef(err, "cannot update config for application %q", a.doc.Name)
+		}
+		return &ErrSettingsRevisionConflict{Expected: expectedRevision, Actual: actual}
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot update config for application %q", a.doc.Name)
+	}
+	return nil
+}
+
 // ApplicationConfig returns the configuration for the application itself.
 func (a *Application) ApplicationConfig() (application.ConfigAttributes, error) {
 	config, err := readSettings(a.st.db(), settingsC, a.applicationConfigKey())
@@ -2233,20 +2388,31 @@ func (a *Application) SetPassword(password string) error {
 		return fmt.Errorf("password is only %d bytes long, and is not a valid Agent password", len(password))
 	}
 	passwordHash := utils.AgentPasswordHash(password)
+	changed := a.st.nowToTheSecond()
 	ops := []txn.Op{{
 		C:      applicationsC,
 		Id:     a.doc.DocID,
 		Assert: notDeadDoc,
-		Update: bson.D{{"$set", bson.D{{"passwordhash", passwordHash}}}},
+		Update: bson.D{{"$set", bson.D{
+			{"passwordhash", passwordHash},
+			{"passwordchanged", changed},
+		}}},
 	}}
 	err := a.st.db().RunTransaction(ops)
 	if err != nil {
 		return fmt.Errorf("cannot set password of application %q: %v", a, onAbort(err, ErrDead))
 	}
 	a.doc.PasswordHash = passwordHash
+	a.doc.PasswordChanged = changed
 	return nil
 }
 
+// PasswordChangedTime returns when the password for the application's
+// agent was last set, or the zero time if it never has been.
+func (a *Application) PasswordChangedTime() time.Time {
+	return a.doc.PasswordChanged.UTC()
+}
+
 // PasswordValid returns whether the given password is valid
 // for the given application.
 func (a *Application) PasswordValid(password string) bool {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -23,6 +24,7 @@ import (
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/feature"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/resource/resourcetesting"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/testing"
@@ -932,6 +934,120 @@ func (s *ApplicationSuite) TestUpdateCharmConfig(c *gc.C) {
 	}
 }
 
+func (s *ApplicationSuite) TestUpdateCharmConfigAtRevision(c *gc.C) {
+	app := s.AddTestingApplication(c, "dummy-application", s.AddTestingCharm(c, "dummy"))
+	revision, err := app.CharmConfigRevision()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.UpdateCharmConfigAtRevision(charm.Settings{"outlook": "good"}, revision)
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := app.CharmConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["outlook"], gc.Equals, "good")
+
+	newRevision, err := app.CharmConfigRevision()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newRevision, jc.GreaterThan, revision)
+
+	// Writing nothing new leaves the revision alone.
+	err = app.UpdateCharmConfigAtRevision(charm.Settings{"outlook": "good"}, newRevision)
+	c.Assert(err, jc.ErrorIsNil)
+	unchanged, err := app.CharmConfigRevision()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unchanged, gc.Equals, newRevision)
+}
+
+func (s *ApplicationSuite) TestUpdateCharmConfigAtRevisionConflict(c *gc.C) {
+	app := s.AddTestingApplication(c, "dummy-application", s.AddTestingCharm(c, "dummy"))
+	revision, err := app.CharmConfigRevision()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.UpdateCharmConfig(charm.Settings{"outlook": "theirs"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.UpdateCharmConfigAtRevision(charm.Settings{"outlook": "ours"}, revision)
+	c.Assert(err, jc.Satisfies, state.IsSettingsRevisionConflictError)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf("settings changed: expected revision %d, found %d", revision, revision+1))
+	settings, err := app.CharmConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["outlook"], gc.Equals, "theirs")
+}
+
+func (s *ApplicationSuite) TestUpdateCharmConfigAtRevisionConcurrentChange(c *gc.C) {
+	app := s.AddTestingApplication(c, "dummy-application", s.AddTestingCharm(c, "dummy"))
+	revision, err := app.CharmConfigRevision()
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := app.UpdateCharmConfig(charm.Settings{"outlook": "theirs"})
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = app.UpdateCharmConfigAtRevision(charm.Settings{"outlook": "ours"}, revision)
+	c.Assert(err, jc.Satisfies, state.IsSettingsRevisionConflictError)
+	settings, err := app.CharmConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["outlook"], gc.Equals, "theirs")
+}
+
+func (s *ApplicationSuite) TestExportImportConfig(c *gc.C) {
+	ch := s.AddTestingCharm(c, "dummy")
+	source := s.AddTestingApplication(c, "source", ch)
+	err := source.UpdateCharmConfig(charm.Settings{"title": "exported", "skill-level": int64(9)})
+	c.Assert(err, jc.ErrorIsNil)
+	err = source.SetConstraints(constraints.MustParse("mem=4G cores=2"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = source.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = source.SetMinUnits(3)
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := source.ExportConfig()
+	c.Assert(err, jc.ErrorIsNil)
+
+	target := s.AddTestingApplication(c, "target", ch)
+	err = target.UpdateCharmConfig(charm.Settings{"outlook": "replaced"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ImportApplicationConfig("target", data)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = target.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := target.CharmConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{
+		"title":       "exported",
+		"skill-level": int64(9),
+		"username":    "admin001",
+	})
+	cons, err := target.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("mem=4G cores=2"))
+	c.Assert(target.IsExposed(), jc.IsTrue)
+	c.Assert(target.MinUnits(), gc.Equals, 3)
+
+	// Exporting the target yields the same document.
+	targetData, err := target.ExportConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(targetData), gc.Equals, string(data))
+}
+
+func (s *ApplicationSuite) TestImportConfigInvalidSettings(c *gc.C) {
+	app := s.AddTestingApplication(c, "dummy-application", s.AddTestingCharm(c, "dummy"))
+	err := s.State.ImportApplicationConfig("dummy-application", []byte("settings:\n  yummy: didgeridoo\nexposed: true\n"))
+	c.Assert(err, gc.ErrorMatches, `cannot import config for application "dummy-application": unknown option "yummy"`)
+
+	// Nothing was applied.
+	err = app.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.IsExposed(), jc.IsFalse)
+}
+
+func (s *ApplicationSuite) TestImportConfigNegativeMinUnits(c *gc.C) {
+	err := s.State.ImportApplicationConfig("mysql", []byte("min-units: -1\n"))
+	c.Assert(err, gc.ErrorMatches, `cannot import config for application "mysql": cannot set a negative minimum number of units`)
+}
+
 func (s *ApplicationSuite) TestUpdateApplicationSeries(c *gc.C) {
 	ch := state.AddTestingCharmMultiSeries(c, s.State, "multi-series")
 	app := state.AddTestingApplicationForSeries(c, s.State, "precise", "multi-series", ch)
@@ -1294,7 +1410,7 @@ func (s *ApplicationSuite) TestSettingsRefCountWorks(c *gc.C) {
 	// invoke them now and check that the charms are cleaned up
 	// correctly -- and that a storm of cleanups for the same
 	// charm are not a problem.
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 	err = oldCh.Refresh()
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
@@ -1739,6 +1855,13 @@ func (s *ApplicationSuite) TestSetPassword(c *gc.C) {
 	})
 }
 
+func (s *ApplicationSuite) TestPasswordChangedTime(c *gc.C) {
+	c.Assert(s.mysql.PasswordChangedTime().IsZero(), jc.IsTrue)
+	testPasswordChangedTime(c, s.Clock, func() (passwordChangedTimer, error) {
+		return s.State.Application(s.mysql.Name())
+	})
+}
+
 func (s *ApplicationSuite) TestServiceExposed(c *gc.C) {
 	// Check that querying for the exposed flag works correctly.
 	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
@@ -1838,6 +1961,85 @@ func (s *ApplicationSuite) TestAddUnit(c *gc.C) {
 	c.Assert(id, gc.Equals, m.Id())
 }
 
+func (s *ApplicationSuite) TestAddUnitWithPlacementNewMachine(c *gc.C) {
+	unit, err := s.mysql.AddUnitWithPlacement("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.Name(), gc.Equals, "mysql/0")
+
+	id, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.Machine(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.IsContainer(), jc.IsFalse)
+}
+
+func (s *ApplicationSuite) TestAddUnitWithPlacementExistingMachine(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.mysql.AddUnitWithPlacement(m.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, m.Id())
+}
+
+func (s *ApplicationSuite) TestAddUnitWithPlacementNewContainer(c *gc.C) {
+	host, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.mysql.AddUnitWithPlacement("lxd:" + host.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, host.Id()+"/lxd/0")
+	m, err := s.State.Machine(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.ContainerType(), gc.Equals, instance.LXD)
+}
+
+func (s *ApplicationSuite) TestAddUnitWithPlacementInvalid(c *gc.C) {
+	_, err := s.mysql.AddUnitWithPlacement("lxd:-1")
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to application "mysql": invalid value "-1" for "lxd" scope: expected machine-id`)
+	units, err := s.mysql.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
+
+func (s *ApplicationSuite) TestAddUnitWithPlacementRemovesUnassignedUnit(c *gc.C) {
+	_, err := s.mysql.AddUnitWithPlacement("lxd:42")
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "mysql/0": .*machine 42 not found`)
+
+	_, err = s.State.Unit("mysql/0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	units, err := s.mysql.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
+
+func (s *ApplicationSuite) TestAddUnitWithPlacementLeavesNoMachine(c *gc.C) {
+	before, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Destroy the unit after it is added but before it is assigned.
+	defer state.SetBeforeHooks(c, s.State, nil, func() {
+		unit, err := s.State.Unit("mysql/0")
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.Destroy()
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	_, err = s.mysql.AddUnitWithPlacement("lxd")
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "mysql/0": .*`)
+
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, len(before))
+	units, err := s.mysql.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
+
 func (s *ApplicationSuite) TestAddUnitWhenNotAlive(c *gc.C) {
 	u, err := s.mysql.AddUnit(state.AddUnitParams{})
 	c.Assert(err, jc.ErrorIsNil)
@@ -2101,6 +2303,90 @@ func (s *ApplicationSuite) assertDestroyWithReferencedRelation(c *gc.C, refresh
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *ApplicationSuite) TestRemoveApplicationForce(c *gc.C) {
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Leave mysql Dying with a unit count but no units, as if the
+	// last unit's removal had not been accounted for.
+	ops := []txn.Op{{
+		C:      state.ApplicationsC,
+		Id:     state.DocID(s.State, s.mysql.Name()),
+		Update: bson.D{{"$set", bson.D{{"life", state.Dying}, {"unitcount", 1}}}},
+	}}
+	err = state.RunTransaction(s.State, ops)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveApplicationForce("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The other application no longer counts the relation, so it can be
+	// removed as soon as it is destroyed.
+	rels, err := wordpress.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 0)
+	err = wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ApplicationSuite) TestRemoveApplicationForceRemovesDyingCounterpart(c *gc.C) {
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Leave both applications Dying, each held only by the relation
+	// between them.
+	var ops []txn.Op
+	for _, name := range []string{"mysql", "wordpress"} {
+		ops = append(ops, txn.Op{
+			C:      state.ApplicationsC,
+			Id:     state.DocID(s.State, name),
+			Update: bson.D{{"$set", bson.D{{"life", state.Dying}}}},
+		})
+	}
+	err = state.RunTransaction(s.State, ops)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Removing one application drops the last reference to the other,
+	// so both are removed.
+	err = s.State.RemoveApplicationForce("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = wordpress.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ApplicationSuite) TestRemoveApplicationForceAlive(c *gc.C) {
+	err := s.State.RemoveApplicationForce("mysql")
+	c.Assert(err, gc.ErrorMatches, `cannot force remove application "mysql": application is alive`)
+	assertLife(c, s.mysql, state.Alive)
+}
+
+func (s *ApplicationSuite) TestRemoveApplicationForceWithUnits(c *gc.C) {
+	_, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveApplicationForce("mysql")
+	c.Assert(err, gc.ErrorMatches, `cannot force remove application "mysql": application has 1 unit`)
+	assertLife(c, s.mysql, state.Dying)
+}
+
 func (s *ApplicationSuite) TestDestroyQueuesUnitCleanup(c *gc.C) {
 	// Add 5 units; block quick-remove of mysql/1 and mysql/3
 	units := make([]*state.Unit, 5)
@@ -2125,7 +2411,7 @@ func (s *ApplicationSuite) TestDestroyQueuesUnitCleanup(c *gc.C) {
 	s.assertNeedsCleanup(c)
 
 	// Run the cleanup and check the units.
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 	for i, unit := range units {
 		if i%2 != 0 {
@@ -2137,7 +2423,7 @@ func (s *ApplicationSuite) TestDestroyQueuesUnitCleanup(c *gc.C) {
 
 	// Check for queued unit cleanups, and run them.
 	s.assertNeedsCleanup(c)
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 
 	// Check we're now clean.
@@ -2155,7 +2441,8 @@ func (s *ApplicationSuite) TestRemoveServiceMachine(c *gc.C) {
 	assertLife(c, s.mysql, state.Dying)
 
 	// Service.Destroy adds units to cleanup, make it happen now.
-	c.Assert(s.State.Cleanup(), gc.IsNil)
+	_, err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
 
 	c.Assert(unit.Refresh(), jc.Satisfies, errors.IsNotFound)
 	assertLife(c, machine, state.Dying)
@@ -2199,7 +2486,7 @@ func (s *ApplicationSuite) TestRemoveQueuesLocalCharmCleanup(c *gc.C) {
 	s.assertNeedsCleanup(c)
 
 	// Run the cleanup
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 
 	// Check charm removed
@@ -2232,7 +2519,7 @@ func (s *ApplicationSuite) TestDestroyQueuesResourcesCleanup(c *gc.C) {
 	c.Assert(state.IsBlobStored(c, s.State, storagePath), jc.IsTrue)
 
 	// Run the cleanup.
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 
 	// Check we're now clean.
@@ -2573,6 +2860,83 @@ func removeAllUnits(c *gc.C, s *state.Application) {
 	}
 }
 
+func (s *ApplicationSuite) TestWatchRelationDetails(c *gc.C) {
+	w := s.mysql.WatchRelationDetails()
+	defer testing.AssertStop(c, w)
+	s.assertRelationDetailsChange(c, w)
+	s.assertNoRelationDetailsChange(c, w)
+
+	mysqlep, err := s.mysql.Endpoint("server")
+	c.Assert(err, jc.ErrorIsNil)
+	wp := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wpep, err := wp.Endpoint("db")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(mysqlep, wpep)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The event carries the relation's endpoints, so there is
+	// no need to read the relation again.
+	s.assertRelationDetailsChange(c, w, state.RelationDetails{
+		Id:        rel.Id(),
+		Key:       rel.String(),
+		Endpoints: rel.Endpoints(),
+		Life:      state.Alive,
+	})
+	s.assertNoRelationDetailsChange(c, w)
+
+	err = rel.SetSuspended(true, "")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRelationDetailsChange(c, w, state.RelationDetails{
+		Id:        rel.Id(),
+		Key:       rel.String(),
+		Endpoints: rel.Endpoints(),
+		Life:      state.Alive,
+		Suspended: true,
+	})
+	s.assertNoRelationDetailsChange(c, w)
+
+	// Once removed, the relation is reported dead with the
+	// details last known for it.
+	err = rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRelationDetailsChange(c, w, state.RelationDetails{
+		Id:        rel.Id(),
+		Key:       rel.String(),
+		Endpoints: rel.Endpoints(),
+		Life:      state.Dead,
+		Suspended: true,
+	})
+	s.assertNoRelationDetailsChange(c, w)
+
+	testing.AssertStop(c, w)
+	_, ok := <-w.Changes()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *ApplicationSuite) assertRelationDetailsChange(c *gc.C, w state.RelationDetailsWatcher, expect ...state.RelationDetails) {
+	s.State.StartSync()
+	select {
+	case details, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+		if len(expect) == 0 {
+			c.Assert(details, gc.HasLen, 0)
+			return
+		}
+		c.Assert(details, jc.DeepEquals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("no change")
+	}
+}
+
+func (s *ApplicationSuite) assertNoRelationDetailsChange(c *gc.C, w state.RelationDetailsWatcher) {
+	s.State.StartSync()
+	select {
+	case details, ok := <-w.Changes():
+		c.Fatalf("got unwanted change: %#v, %t", details, ok)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
 func (s *ApplicationSuite) TestWatchApplication(c *gc.C) {
 	w := s.mysql.Watch()
 	defer testing.AssertStop(c, w)
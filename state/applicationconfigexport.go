@@ -0,0 +1,152 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/constraints"
+)
+
+// applicationConfigSnapshot holds the parts of an application's
+// configuration that are exported by ExportConfig and restored by
+// ImportApplicationConfig.
+type applicationConfigSnapshot struct {
+	Settings    charm.Settings     `yaml:"settings,omitempty"`
+	Constraints *constraints.Value `yaml:"constraints,omitempty"`
+	Exposed     bool               `yaml:"exposed"`
+	MinUnits    int                `yaml:"min-units"`
+}
+
+// ExportConfig returns a YAML document holding the application's charm
+// config settings, constraints, exposed flag and minimum units. Only
+// settings that have been explicitly set are included; charm defaults
+// are not. The document can be applied to an application with
+// ImportApplicationConfig.
+func (a *Application) ExportConfig() ([]byte, error) {
+	settings, err := readSettings(a.st.db(), settingsC, a.charmConfigKey())
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot export config for application %q", a)
+	}
+	snapshot := applicationConfigSnapshot{
+		Settings: settings.Map(),
+		Exposed:  a.doc.Exposed,
+		MinUnits: a.doc.MinUnits,
+	}
+	if !a.doc.Subordinate {
+		cons, err := a.Constraints()
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot export config for application %q", a)
+		}
+		snapshot.Constraints = &cons
+	}
+	data, err := yaml.Marshal(snapshot)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot export config for application %q", a)
+	}
+	return data, nil
+}
+
+// ImportApplicationConfig replaces the charm config settings,
+// constraints, exposed flag and minimum units of the named application
+// with those held in data, as produced by Application.ExportConfig.
+// The values are validated as they would be by the individual setters,
+// and are applied in a single transaction.
+func (st *State) ImportApplicationConfig(name string, data []byte) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot import config for application %q", name)
+	var snapshot applicationConfigSnapshot
+	if err := yaml.Unmarshal(data, &snapshot); err != nil {
+		return errors.Trace(err)
+	}
+	if snapshot.MinUnits < 0 {
+		return errors.New("cannot set a negative minimum number of units")
+	}
+	if snapshot.Constraints != nil {
+		unsupported, err := st.validateConstraints(*snapshot.Constraints)
+		if len(unsupported) > 0 {
+			logger.Warningf(
+				"importing constraints for application %q: unsupported constraints: %v", name, strings.Join(unsupported, ","))
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	app, err := st.Application(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := app.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if app.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		ops := []txn.Op{{
+			C:      applicationsC,
+			Id:     app.doc.DocID,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"exposed", snapshot.Exposed}}}},
+		}}
+		if snapshot.MinUnits != app.doc.MinUnits {
+			ops = append(ops, setMinUnitsOps(app, snapshot.MinUnits)...)
+		}
+		if snapshot.Constraints != nil {
+			if app.doc.Subordinate {
+				return nil, ErrSubordinateConstraints
+			}
+			ops = append(ops, setConstraintsOp(app.globalKey(), *snapshot.Constraints))
+		}
+		settingsOps, err := app.replaceCharmConfigOps(snapshot.Settings)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, settingsOps...), nil
+	}
+	return st.db().Run(buildTxn)
+}
+
+// replaceCharmConfigOps returns the operations required to replace the
+// application's charm config settings with the given settings, after
+// validating them against the application's charm. The operations
+// assert that the settings have not changed since they were read.
+func (a *Application) replaceCharmConfigOps(settings charm.Settings) ([]txn.Op, error) {
+	ch, _, err := a.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	settings, err = ch.Config().ValidateSettings(settings)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	node, err := readSettings(a.st.db(), settingsC, a.charmConfigKey())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, key := range node.Keys() {
+		if _, ok := settings[key]; !ok {
+			node.Delete(key)
+		}
+	}
+	for key, value := range settings {
+		if value == nil {
+			node.Delete(key)
+		} else {
+			node.Set(key, value)
+		}
+	}
+	_, ops := node.settingsUpdateOps()
+	if len(ops) == 0 {
+		return []txn.Op{node.assertUnchangedOp()}, nil
+	}
+	ops[0].Assert = bson.D{{"version", node.version}}
+	return ops, nil
+}
@@ -1105,6 +1105,61 @@ func (s *assignCleanSuite) TestAssignUnitWithDynamicStorageCleanAvailable(c *gc.
 	c.Assert(volumeStorageInstance, gc.Equals, storageAttachments[0].StorageInstance())
 }
 
+func (s *assignCleanSuite) assignAndRemoveUnit(c *gc.C) *state.Machine {
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, s.policy)
+	c.Assert(err, jc.ErrorIsNil)
+	mid, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.Machine(mid)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Clean(), jc.IsFalse)
+	return m
+}
+
+func (s *assignCleanSuite) TestAssignUnitPolicyReusesMachineMarkedClean(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageModel) // bootstrap machine
+	c.Assert(err, jc.ErrorIsNil)
+	m := s.assignAndRemoveUnit(c)
+	err = m.SetClean(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Clean(), jc.IsTrue)
+
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, s.policy)
+	c.Assert(err, jc.ErrorIsNil)
+	mid, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mid, gc.Equals, m.Id())
+	assertMachineCount(c, s.State, 2)
+
+	// Assigning the unit marks the machine dirty again.
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Clean(), jc.IsFalse)
+}
+
+func (s *assignCleanSuite) TestAssignUnitPolicySkipsDirtyMachine(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageModel) // bootstrap machine
+	c.Assert(err, jc.ErrorIsNil)
+	m := s.assignAndRemoveUnit(c)
+
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, s.policy)
+	c.Assert(err, jc.ErrorIsNil)
+	mid, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mid, gc.Not(gc.Equals), m.Id())
+	assertMachineCount(c, s.State, 3)
+}
+
 func (s *assignCleanSuite) TestAssignUnitPolicy(c *gc.C) {
 	_, err := s.State.AddMachine("quantal", state.JobManageModel) // bootstrap machine
 	c.Assert(err, jc.ErrorIsNil)
@@ -96,10 +96,77 @@ func (st *State) NeedsCleanup() (bool, error) {
 	return count > 0, nil
 }
 
+// CleanupReport summarises what a call to Cleanup did.
+type CleanupReport struct {
+	// Units is the number of units removed while running the cleanups.
+	Units int
+
+	// Machines is the number of machines removed while running the
+	// cleanups. Force-destroyed machines are left Dead for the
+	// provisioner to remove, so only their containers are counted.
+	Machines int
+
+	// Relations is the number of relations removed while running the
+	// cleanups.
+	Relations int
+}
+
 // Cleanup removes all documents that were previously marked for removal, if
 // any such exist. It should be called periodically by at least one element
-// of the system.
-func (st *State) Cleanup() (err error) {
+// of the system. The returned report counts the entities removed from the
+// model while the cleanups ran.
+func (st *State) Cleanup() (CleanupReport, error) {
+	before, err := st.cleanupEntityCounts()
+	if err != nil {
+		return CleanupReport{}, errors.Trace(err)
+	}
+	if err := st.runCleanups(); err != nil {
+		return CleanupReport{}, err
+	}
+	after, err := st.cleanupEntityCounts()
+	if err != nil {
+		return CleanupReport{}, errors.Trace(err)
+	}
+	removed := func(before, after int) int {
+		// Entities may be added concurrently; never report a
+		// negative number of removals.
+		if after > before {
+			return 0
+		}
+		return before - after
+	}
+	return CleanupReport{
+		Units:     removed(before.Units, after.Units),
+		Machines:  removed(before.Machines, after.Machines),
+		Relations: removed(before.Relations, after.Relations),
+	}, nil
+}
+
+// cleanupEntityCounts returns the number of units, machines and
+// relations in the model.
+func (st *State) cleanupEntityCounts() (CleanupReport, error) {
+	var counts CleanupReport
+	for _, item := range []struct {
+		collection string
+		count      *int
+	}{
+		{unitsC, &counts.Units},
+		{machinesC, &counts.Machines},
+		{relationsC, &counts.Relations},
+	} {
+		coll, closer := st.db().GetCollection(item.collection)
+		n, err := coll.Count()
+		closer()
+		if err != nil {
+			return CleanupReport{}, errors.Annotatef(err, "cannot count %s", item.collection)
+		}
+		*item.count = n
+	}
+	return counts, nil
+}
+
+// runCleanups runs, and then removes, every queued cleanup document.
+func (st *State) runCleanups() (err error) {
 	var doc cleanupDoc
 	cleanups, closer := st.db().GetCollection(cleanupsC)
 	defer closer()
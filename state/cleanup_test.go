@@ -443,6 +443,71 @@ func (s *CleanupSuite) TestCleanupForceDestroyedMachineWithContainer(c *gc.C) {
 	assertLife(c, machine, state.Dead)
 }
 
+func (s *CleanupSuite) TestCleanupReportForceDestroyedMachine(c *gc.C) {
+	// Create a machine with a container.
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, machine.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Host a unit on each, with the one on the machine keeping a
+	// dying relation alive.
+	prr := newProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
+	err = prr.pu0.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.pu1.AssignToMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.pru0.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	preventProReqUnitsDestroyRemove(c, prr)
+	err = prr.rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	assertLife(c, prr.rel, state.Dying)
+	s.assertDoesNotNeedCleanup(c)
+
+	err = machine.ForceDestroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Run the cleanups until there are none left, adding up what
+	// they report.
+	var total state.CleanupReport
+	for i := 0; i < 5; i++ {
+		needsCleanup, err := s.State.NeedsCleanup()
+		c.Assert(err, jc.ErrorIsNil)
+		if !needsCleanup {
+			break
+		}
+		report, err := s.State.Cleanup()
+		c.Assert(err, jc.ErrorIsNil)
+		total.Units += report.Units
+		total.Machines += report.Machines
+		total.Relations += report.Relations
+	}
+	s.assertDoesNotNeedCleanup(c)
+
+	// Both units, the container and the relation have been removed;
+	// the machine itself is left Dead for the provisioner.
+	c.Assert(total, gc.Equals, state.CleanupReport{
+		Units:     2,
+		Machines:  1,
+		Relations: 1,
+	})
+	assertRemoved(c, prr.pu0)
+	assertRemoved(c, prr.pu1)
+	err = prr.rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	assertLife(c, machine, state.Dead)
+}
+
+func (s *CleanupSuite) TestCleanupReportNothingToCleanup(c *gc.C) {
+	report, err := s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report, gc.Equals, state.CleanupReport{})
+}
+
 func (s *CleanupSuite) TestCleanupDyingUnit(c *gc.C) {
 	// Create active unit, in a relation.
 	prr := newProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
@@ -785,7 +850,7 @@ func (s *CleanupSuite) TestNothingToCleanup(c *gc.C) {
 }
 
 func (s *CleanupSuite) assertCleanupRuns(c *gc.C) {
-	err := s.State.Cleanup()
+	_, err := s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 }
 
@@ -82,6 +82,42 @@ func (s *ConfigValidatorSuite) TestUpdateModelConfigFailsOnConfigValidateError(c
 	c.Assert(err, gc.ErrorMatches, " not found")
 }
 
+// immutableKeyValidator rejects any change to the value of key.
+type immutableKeyValidator struct {
+	key string
+}
+
+func (v immutableKeyValidator) Validate(cfg, old *config.Config) (*config.Config, error) {
+	if old != nil && cfg.AllAttrs()[v.key] != old.AllAttrs()[v.key] {
+		return nil, errors.Errorf("cannot change %s", v.key)
+	}
+	return cfg, nil
+}
+
+func (s *ConfigValidatorSuite) TestUpdateModelConfigRejectedTransition(c *gc.C) {
+	s.policy.GetConfigValidator = func() (config.Validator, error) {
+		return immutableKeyValidator{key: "authorized-keys"}, nil
+	}
+	oldCfg, err := s.IAASModel.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The provider vetoes the change, and nothing is persisted.
+	err = s.updateModelConfig(c)
+	c.Assert(err, gc.ErrorMatches, "cannot change authorized-keys")
+	cfg, err := s.IAASModel.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs(), jc.DeepEquals, oldCfg.AllAttrs())
+
+	// Changes the provider allows are persisted.
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"arbitrary-key": "shazam!",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err = s.IAASModel.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["arbitrary-key"], gc.Equals, "shazam!")
+}
+
 func (s *ConfigValidatorSuite) TestUpdateModelConfigUpdatesState(c *gc.C) {
 	s.updateModelConfig(c)
 	stateCfg, err := s.IAASModel.ModelConfig()
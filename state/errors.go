@@ -41,6 +41,140 @@ func IsCharmAlreadyUploadedError(err interface{}) bool {
 	return ok
 }
 
+// ErrUnknownCollection is returned by watchers asked to watch a
+// collection that is not part of the database schema.
+type ErrUnknownCollection struct {
+	name string
+}
+
+func (e *ErrUnknownCollection) Error() string {
+	return fmt.Sprintf("cannot watch unknown collection %q", e.name)
+}
+
+// IsUnknownCollectionError returns if the given error or its cause is
+// ErrUnknownCollection.
+func IsUnknownCollectionError(err interface{}) bool {
+	if err == nil {
+		return false
+	}
+	// In case of a wrapped error, check the cause first.
+	value := err
+	cause := errors.Cause(err.(error))
+	if cause != nil {
+		value = cause
+	}
+	_, ok := value.(*ErrUnknownCollection)
+	return ok
+}
+
+// ErrSettingsRevisionConflict is returned when settings are updated
+// conditionally on a revision that is no longer current.
+type ErrSettingsRevisionConflict struct {
+	Expected int64
+	Actual   int64
+}
+
+func (e *ErrSettingsRevisionConflict) Error() string {
+	return fmt.Sprintf("settings changed: expected revision %d, found %d", e.Expected, e.Actual)
+}
+
+// IsSettingsRevisionConflictError returns if the given error or its cause
+// is ErrSettingsRevisionConflict.
+func IsSettingsRevisionConflictError(err interface{}) bool {
+	if err == nil {
+		return false
+	}
+	// In case of a wrapped error, check the cause first.
+	value := err
+	cause := errors.Cause(err.(error))
+	if cause != nil {
+		value = cause
+	}
+	_, ok := value.(*ErrSettingsRevisionConflict)
+	return ok
+}
+
+// ErrConstraintsRevisionConflict is returned when constraints are set
+// conditionally on a revision that is no longer current.
+type ErrConstraintsRevisionConflict struct {
+	Expected int64
+	Actual   int64
+}
+
+func (e *ErrConstraintsRevisionConflict) Error() string {
+	return fmt.Sprintf("constraints changed: expected revision %d, found %d", e.Expected, e.Actual)
+}
+
+// IsConstraintsRevisionConflictError returns if the given error or its
+// cause is ErrConstraintsRevisionConflict.
+func IsConstraintsRevisionConflictError(err interface{}) bool {
+	if err == nil {
+		return false
+	}
+	// In case of a wrapped error, check the cause first.
+	value := err
+	cause := errors.Cause(err.(error))
+	if cause != nil {
+		value = cause
+	}
+	_, ok := value.(*ErrConstraintsRevisionConflict)
+	return ok
+}
+
+// ErrSettingsTooLarge is returned when settings are written whose
+// serialized size exceeds the limit for the settings node.
+type ErrSettingsTooLarge struct {
+	Size  int
+	Limit int
+}
+
+func (e *ErrSettingsTooLarge) Error() string {
+	return fmt.Sprintf("settings too large: %d bytes exceeds limit of %d bytes", e.Size, e.Limit)
+}
+
+// IsSettingsTooLargeError returns if the given error or its cause
+// is ErrSettingsTooLarge.
+func IsSettingsTooLargeError(err interface{}) bool {
+	if err == nil {
+		return false
+	}
+	// In case of a wrapped error, check the cause first.
+	value := err
+	cause := errors.Cause(err.(error))
+	if cause != nil {
+		value = cause
+	}
+	_, ok := value.(*ErrSettingsTooLarge)
+	return ok
+}
+
+// ErrSettingsReadOnly is returned when settings are written through a
+// read-only view, such as a relation unit's view of a counterpart's
+// settings.
+type ErrSettingsReadOnly struct {
+	Key string
+}
+
+func (e *ErrSettingsReadOnly) Error() string {
+	return fmt.Sprintf("settings %q are read-only", e.Key)
+}
+
+// IsSettingsReadOnlyError returns if the given error or its cause
+// is ErrSettingsReadOnly.
+func IsSettingsReadOnlyError(err interface{}) bool {
+	if err == nil {
+		return false
+	}
+	// In case of a wrapped error, check the cause first.
+	value := err
+	cause := errors.Cause(err.(error))
+	if cause != nil {
+		value = cause
+	}
+	_, ok := value.(*ErrSettingsReadOnly)
+	return ok
+}
+
 // ErrCharmRevisionAlreadyModified is returned when a pending or
 // placeholder charm is no longer pending or a placeholder, signaling
 // the charm is available in state with its full information.
@@ -38,6 +38,7 @@ import (
 const (
 	MachinesC         = machinesC
 	ApplicationsC     = applicationsC
+	UnitsC            = unitsC
 	EndpointBindingsC = endpointBindingsC
 	ControllersC      = controllersC
 	UsersC            = usersC
@@ -272,6 +273,21 @@ func RunTransaction(st *State, ops []txn.Op) error {
 	return st.db().RunTransaction(ops)
 }
 
+// RewriteRelationUnitSettings writes the current values of the relation
+// unit's settings back to its settings document, bumping the version
+// without changing any value.
+func RewriteRelationUnitSettings(ru *RelationUnit) error {
+	node, err := ru.Settings()
+	if err != nil {
+		return err
+	}
+	op, _, err := replaceSettingsOp(ru.st.db(), settingsC, ru.key(), node.Map())
+	if err != nil {
+		return err
+	}
+	return ru.st.db().RunTransaction([]txn.Op{op})
+}
+
 // Return the PasswordSalt that goes along with the PasswordHash
 func GetUserPasswordSaltAndHash(u *User) (string, string) {
 	return u.doc.PasswordSalt, u.doc.PasswordHash
@@ -293,6 +309,10 @@ func WatcherMakeIdFilter(st *State, marker string, receivers ...ActionReceiver)
 	return makeIdFilter(st, marker, receivers...)
 }
 
+func NewLifecycleWatcher(st *State, collName string) StringsWatcher {
+	return newLifecycleWatcher(st, collName, nil, nil, nil)
+}
+
 func NewActionStatusWatcher(st *State, receivers []ActionReceiver, statuses ...ActionStatus) StringsWatcher {
 	return newActionStatusWatcher(st, receivers, statuses...)
 }
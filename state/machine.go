@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -108,6 +109,10 @@ type machineDoc struct {
 	PasswordHash  string
 	Clean         bool
 
+	// PasswordChanged holds when the machine agent's password was
+	// last set.
+	PasswordChanged time.Time `bson:",omitempty"`
+
 	// Volumes contains the names of volumes attached to the machine.
 	Volumes []string `bson:"volumes,omitempty"`
 	// Filesystems contains the names of filesystems attached to the machine.
@@ -452,7 +457,21 @@ func (m *Machine) SetMongoPassword(password string) error {
 	if !m.IsManager() {
 		return errors.NotSupportedf("setting mongo password for non-controller machine %v", m)
 	}
-	return mongo.SetAdminMongoPassword(m.st.session, m.Tag().String(), password)
+	if err := mongo.SetAdminMongoPassword(m.st.session, m.Tag().String(), password); err != nil {
+		return err
+	}
+	changed := m.st.nowToTheSecond()
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"passwordchanged", changed}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot record mongo password change for machine %v", m)
+	}
+	m.doc.PasswordChanged = changed
+	return nil
 }
 
 // SetPassword sets the password for the machine's agent.
@@ -467,10 +486,17 @@ func (m *Machine) SetPassword(password string) error {
 		return errors.Trace(err)
 	}
 	m.doc.PasswordHash = passwordHash
+	m.doc.PasswordChanged = op.passwordChanged
 	return nil
 }
 
-func (m *Machine) setPasswordHashOps(passwordHash string) ([]txn.Op, error) {
+// PasswordChangedTime returns when the password for the machine's
+// agent was last set, or the zero time if it never has been.
+func (m *Machine) PasswordChangedTime() time.Time {
+	return m.doc.PasswordChanged.UTC()
+}
+
+func (m *Machine) setPasswordHashOps(passwordHash string, changed time.Time) ([]txn.Op, error) {
 	if m.doc.Life == Dead {
 		return nil, ErrDead
 	}
@@ -478,7 +504,10 @@ func (m *Machine) setPasswordHashOps(passwordHash string) ([]txn.Op, error) {
 		C:      machinesC,
 		Id:     m.doc.DocID,
 		Assert: notDeadDoc,
-		Update: bson.D{{"$set", bson.D{{"passwordhash", passwordHash}}}},
+		Update: bson.D{{"$set", bson.D{
+			{"passwordhash", passwordHash},
+			{"passwordchanged", changed},
+		}}},
 	}}
 	return ops, nil
 }
@@ -496,7 +525,8 @@ func (m *Machine) PasswordValid(password string) bool {
 // If the machine has assigned units, Destroy will return
 // a HasAssignedUnitsError.
 func (m *Machine) Destroy() error {
-	return m.advanceLifecycle(Dying)
+	_, err := m.advanceLifecycle(Dying)
+	return err
 }
 
 // ForceDestroy queues the machine for complete removal, including the
@@ -532,6 +562,14 @@ func (m *Machine) forceDestroyOps() ([]txn.Op, error) {
 // If the machine has assigned units, EnsureDead will return
 // a HasAssignedUnitsError.
 func (m *Machine) EnsureDead() error {
+	_, err := m.advanceLifecycle(Dead)
+	return err
+}
+
+// EnsureDeadChanged behaves like EnsureDead, but also reports whether
+// this call moved the machine to Dead. It returns false if the machine
+// was already Dead or has been removed.
+func (m *Machine) EnsureDeadChanged() (bool, error) {
 	return m.advanceLifecycle(Dead)
 }
 
@@ -566,6 +604,64 @@ func (m *Machine) Containers() ([]string, error) {
 	return nil, err
 }
 
+// ContainerMachines returns the machines for the containers directly
+// inside this machine, ordered by id.
+func (m *Machine) ContainerMachines() ([]*Machine, error) {
+	ids, err := m.Containers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	machines, closer := m.st.db().GetCollection(machinesC)
+	defer closer()
+
+	var mdocs machineDocSlice
+	sel := bson.D{{"machineid", bson.D{{"$in", ids}}}}
+	if err := machines.Find(sel).All(&mdocs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get containers of machine %v", m.Id())
+	}
+	sort.Sort(mdocs)
+	result := make([]*Machine, len(mdocs))
+	for i, doc := range mdocs {
+		result[i] = newMachine(m.st, &doc)
+	}
+	return result, nil
+}
+
+// AddContainer adds a new container of the given type inside the
+// machine, configured according to the given template. The machine
+// must be alive.
+func (m *Machine) AddContainer(containerType instance.ContainerType, template MachineTemplate) (_ *Machine, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add container to machine %v", m.Id())
+	if m.doc.Life != Alive {
+		return nil, errors.New("machine is not alive")
+	}
+	mdoc, ops, err := m.st.addMachineInsideMachineOps(template, m.Id(), containerType)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append([]txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: isAliveDoc,
+	}}, ops...)
+	container, err := m.st.addMachine(mdoc, ops)
+	if errors.Cause(err) == txn.ErrAborted {
+		if err := m.Refresh(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if m.doc.Life != Alive {
+			return nil, errors.New("machine is not alive")
+		}
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return container, nil
+}
+
 // ParentId returns the Id of the host machine if this machine is a container.
 func (m *Machine) ParentId() (string, bool) {
 	parentId := ParentId(m.Id())
@@ -621,14 +717,15 @@ func IsHasAttachmentsError(err error) bool {
 // than the supplied value. If the machine already has that lifecycle
 // value, or a later one, no changes will be made to remote state. If
 // the machine has any responsibilities that preclude a valid change in
-// lifecycle, it will return an error.
-func (original *Machine) advanceLifecycle(life Life) (err error) {
+// lifecycle, it will return an error. It reports whether the machine's
+// lifecycle was changed by this call.
+func (original *Machine) advanceLifecycle(life Life) (changed bool, err error) {
 	containers, err := original.Containers()
 	if err != nil {
-		return err
+		return false, err
 	}
 	if len(containers) > 0 {
-		return &HasContainersError{
+		return false, &HasContainersError{
 			MachineId:    original.doc.Id,
 			ContainerIds: containers,
 		}
@@ -668,6 +765,9 @@ func (original *Machine) advanceLifecycle(life Life) (err error) {
 			{"jobs", bson.D{{"$nin", []MachineJob{JobManageModel}}}},
 			{"hasvote", bson.D{{"$ne", true}}},
 		}
+		// Only the final attempt determines whether
+		// this call changed the machine's life.
+		changed = false
 		// Grab a fresh copy of the machine data.
 		// We don't write to original, because the expectation is that state-
 		// changing methods only set the requested change on the receiver; a case
@@ -752,6 +852,7 @@ func (original *Machine) advanceLifecycle(life Life) (err error) {
 						{{"children", bson.D{{"$exists", false}}}},
 					}}},
 				}
+				changed = true
 				return []txn.Op{op, containerCheck, cleanupOp}, nil
 			}
 		}
@@ -776,12 +877,13 @@ func (original *Machine) advanceLifecycle(life Life) (err error) {
 
 		// Add the additional asserts needed for this transaction.
 		op.Assert = advanceAsserts
+		changed = true
 		return []txn.Op{op, cleanupOp}, nil
 	}
 	if err = m.st.db().Run(buildTxn); err == jujutxn.ErrExcessiveContention {
 		err = errors.Annotatef(err, "machine %s cannot advance lifecycle", m)
 	}
-	return err
+	return err == nil && changed, err
 }
 
 // assertNoPersistentStorage ensures that there are no persistent volumes or
@@ -1738,6 +1840,49 @@ func (m *Machine) Clean() bool {
 	return m.doc.Clean
 }
 
+// SetClean marks the machine as clean or dirty. A clean machine may be
+// chosen by the AssignClean and AssignCleanEmpty policies, so it should
+// only be marked clean once it is known to be safe to reuse. A machine
+// that hosts principal units cannot be marked clean; assigning a unit to
+// a machine always marks it dirty again.
+func (m *Machine) SetClean(clean bool) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set clean to %v on machine %v", clean, m)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.doc.Life != Alive {
+			return nil, errors.New("machine is not alive")
+		}
+		if m.doc.Clean == clean {
+			return nil, jujutxn.ErrNoOperations
+		}
+		assert := isAliveDoc
+		if clean {
+			if len(m.doc.Principals) > 0 {
+				return nil, errors.Errorf("machine has units %v", m.doc.Principals)
+			}
+			assert = append(bson.D{{"$or", []bson.D{
+				{{"principals", bson.D{{"$size", 0}}}},
+				{{"principals", bson.D{{"$exists", false}}}},
+			}}}, isAliveDoc...)
+		}
+		return []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: assert,
+			Update: bson.D{{"$set", bson.D{{"clean", clean}}}},
+		}}, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return err
+	}
+	m.doc.Clean = clean
+	return nil
+}
+
 // SupportedContainers returns any containers this machine is capable of hosting, and a bool
 // indicating if the supported containers have been determined or not.
 func (m *Machine) SupportedContainers() ([]instance.ContainerType, bool) {
@@ -1995,6 +2140,9 @@ type UpdateMachineOperation struct {
 	MachineAddresses  *[]network.Address
 	ProviderAddresses *[]network.Address
 	PasswordHash      *string
+
+	// passwordChanged records when PasswordHash was set.
+	passwordChanged time.Time
 }
 
 // Build is part of the ModelOperation interface.
@@ -2040,7 +2188,8 @@ func (op *UpdateMachineOperation) Build(attempt int) ([]txn.Op, error) {
 	}
 
 	if op.PasswordHash != nil {
-		ops, err := op.m.setPasswordHashOps(*op.PasswordHash)
+		op.passwordChanged = op.m.st.nowToTheSecond()
+		ops, err := op.m.setPasswordHashOps(*op.PasswordHash, op.passwordChanged)
 		if err != nil {
 			return nil, errors.Annotate(err, "cannot set password")
 		}
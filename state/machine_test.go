@@ -129,6 +129,42 @@ func (s *MachineSuite) TestSetKeepInstance(c *gc.C) {
 	c.Assert(keep, jc.IsTrue)
 }
 
+func (s *MachineSuite) TestSetClean(c *gc.C) {
+	c.Assert(s.machine.Clean(), jc.IsTrue)
+	err := s.machine.SetClean(false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Clean(), jc.IsFalse)
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Clean(), jc.IsFalse)
+
+	err = s.machine.SetClean(true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Clean(), jc.IsTrue)
+}
+
+func (s *MachineSuite) TestSetCleanWithUnits(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetClean(true)
+	c.Assert(err, gc.ErrorMatches, `cannot set clean to true on machine 1: machine has units \[wordpress/0\]`)
+	c.Assert(s.machine.Clean(), jc.IsFalse)
+}
+
+func (s *MachineSuite) TestSetCleanDeadMachine(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetClean(false)
+	c.Assert(err, gc.ErrorMatches, `cannot set clean to false on machine 1: machine is not alive`)
+}
+
 func (s *MachineSuite) TestAddMachineInsideMachineModelDying(c *gc.C) {
 	model, err := s.State.Model()
 	c.Assert(err, jc.ErrorIsNil)
@@ -224,6 +260,67 @@ func (s *MachineSuite) TestParentId(c *gc.C) {
 	c.Assert(ok, jc.IsTrue)
 }
 
+func (s *MachineSuite) TestAddContainer(c *gc.C) {
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	container0, err := s.machine.AddContainer(instance.LXD, template)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container0.Id(), gc.Equals, s.machine.Id()+"/lxd/0")
+	container1, err := s.machine.AddContainer(instance.KVM, template)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container1.Id(), gc.Equals, s.machine.Id()+"/kvm/0")
+
+	// Containers nested inside a container, and containers on other
+	// machines, are not listed.
+	_, err = container0.AddContainer(instance.LXD, template)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machine0.AddContainer(instance.LXD, template)
+	c.Assert(err, jc.ErrorIsNil)
+
+	containers, err := s.machine.ContainerMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	var ids []string
+	for _, container := range containers {
+		ids = append(ids, container.Id())
+	}
+	c.Assert(ids, jc.DeepEquals, []string{container1.Id(), container0.Id()})
+}
+
+func (s *MachineSuite) TestContainerMachinesNoContainers(c *gc.C) {
+	containers, err := s.machine.ContainerMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *MachineSuite) TestAddContainerNotAlive(c *gc.C) {
+	err := s.machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machine.AddContainer(instance.LXD, state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add container to machine 1: machine is not alive`)
+}
+
+func (s *MachineSuite) TestAddContainerBecomesNotAlive(c *gc.C) {
+	defer state.SetBeforeHooks(c, s.State, func() {
+		m, err := s.State.Machine(s.machine.Id())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(m.Destroy(), jc.ErrorIsNil)
+	}).Check()
+	_, err := s.machine.AddContainer(instance.LXD, state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add container to machine 1: machine is not alive`)
+
+	containers, err := s.machine.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
 func (s *MachineSuite) TestMachineIsManager(c *gc.C) {
 	c.Assert(s.machine0.IsManager(), jc.IsTrue)
 	c.Assert(s.machine.IsManager(), jc.IsFalse)
@@ -508,6 +605,46 @@ func (s *MachineSuite) TestDestroyFailsWhenNewContainerAdded(c *gc.C) {
 	c.Assert(life, gc.Equals, state.Alive)
 }
 
+func (s *MachineSuite) TestEnsureDeadChanged(c *gc.C) {
+	changed, err := s.machine.EnsureDeadChanged()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsTrue)
+	c.Assert(s.machine.Life(), gc.Equals, state.Dead)
+
+	changed, err = s.machine.EnsureDeadChanged()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsFalse)
+
+	// A stale copy of the machine sees the same result.
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	changed, err = m.EnsureDeadChanged()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsFalse)
+}
+
+func (s *MachineSuite) TestEnsureDeadChangedFromDying(c *gc.C) {
+	err := s.machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	changed, err := s.machine.EnsureDeadChanged()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsTrue)
+	c.Assert(s.machine.Life(), gc.Equals, state.Dead)
+}
+
+func (s *MachineSuite) TestEnsureDeadChangedError(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	changed, err := s.machine.EnsureDeadChanged()
+	c.Assert(err, jc.Satisfies, state.IsHasAssignedUnitsError)
+	c.Assert(changed, jc.IsFalse)
+	c.Assert(s.machine.Life(), gc.Equals, state.Alive)
+}
+
 func (s *MachineSuite) TestRemove(c *gc.C) {
 	err := s.State.SetSSHHostKeys(s.machine.MachineTag(), state.SSHHostKeys{"rsa", "dsa"})
 	c.Assert(err, jc.ErrorIsNil)
@@ -700,6 +837,13 @@ func (s *MachineSuite) TestSetPassword(c *gc.C) {
 	})
 }
 
+func (s *MachineSuite) TestPasswordChangedTime(c *gc.C) {
+	c.Assert(s.machine.PasswordChangedTime().IsZero(), jc.IsTrue)
+	testPasswordChangedTime(c, s.Clock, func() (passwordChangedTimer, error) {
+		return s.State.Machine(s.machine.Id())
+	})
+}
+
 func (s *MachineSuite) TestMachineWaitAgentPresence(c *gc.C) {
 	alive, err := s.machine.AgentPresence()
 	c.Assert(err, jc.ErrorIsNil)
@@ -729,6 +873,33 @@ func (s *MachineSuite) TestMachineWaitAgentPresence(c *gc.C) {
 	c.Assert(alive, jc.IsFalse)
 }
 
+func (s *MachineSuite) TestWatchAgentPresence(c *gc.C) {
+	w := s.machine.WatchAgentPresence()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	// Initial event.
+	wc.AssertOneChange()
+
+	pinger, err := s.machine.SetAgentPresence()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// The pinger dying is reported as the agent going away.
+	err = pinger.KillForTesting()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	pinger, err = s.machine.SetAgentPresence()
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		c.Assert(worker.Stop(pinger), jc.ErrorIsNil)
+	}()
+	wc.AssertOneChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *MachineSuite) TestMachineInstanceIdCorrupt(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -955,6 +1126,19 @@ func (s *MachineSuite) TestMachineSetCheckProvisioned(c *gc.C) {
 	c.Assert(s.machine.CheckProvisioned("not-really"), jc.IsFalse)
 }
 
+func (s *MachineSuite) TestMachineCheckProvisionedCustomNonce(c *gc.C) {
+	// The nonce format is chosen by the provisioner; state only
+	// requires the agent to present exactly the stored value.
+	nonce := "custom/provisioner-7#a1b2c3"
+	err := s.machine.SetProvisioned("umbrella/0", nonce, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.CheckProvisioned(nonce), jc.IsTrue)
+
+	for _, bad := range []string{"", "custom/provisioner-7", nonce + " ", "CUSTOM/PROVISIONER-7#A1B2C3"} {
+		c.Check(s.machine.CheckProvisioned(bad), jc.IsFalse, gc.Commentf("nonce %q", bad))
+	}
+}
+
 func (s *MachineSuite) TestSetProvisionedDupInstanceId(c *gc.C) {
 	var logWriter loggo.TestWriter
 	c.Assert(loggo.RegisterWriter("dupe-test", &logWriter), gc.IsNil)
@@ -1277,6 +1461,62 @@ func (s *MachineSuite) TestWatchDiesOnStateClose(c *gc.C) {
 	})
 }
 
+func (s *MachineSuite) TestWatchMachinesWithTransientErrors(c *gc.C) {
+	setInstanceStatus := func(m *state.Machine, st status.Status, message string) {
+		now := coretesting.ZeroTime()
+		err := m.SetInstanceStatus(status.StatusInfo{
+			Status:  st,
+			Message: message,
+			Since:   &now,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// A machine already in error is reported in the initial event.
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	setInstanceStatus(m, status.ProvisioningError, "no capacity")
+
+	w := s.State.WatchMachinesWithTransientErrors()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange(m.Id())
+	wc.AssertNoChange()
+
+	// Other instance statuses are not reported.
+	setInstanceStatus(s.machine, status.Provisioning, "")
+	wc.AssertNoChange()
+
+	// Entering ProvisioningError is reported.
+	setInstanceStatus(s.machine, status.ProvisioningError, "quota exceeded")
+	wc.AssertChange(s.machine.Id())
+	wc.AssertNoChange()
+
+	// Staying in ProvisioningError is not reported again.
+	setInstanceStatus(s.machine, status.ProvisioningError, "still over quota")
+	wc.AssertNoChange()
+
+	// Agent status errors are not reported.
+	now := coretesting.ZeroTime()
+	err = s.machine.SetStatus(status.StatusInfo{
+		Status:  status.Error,
+		Message: "agent failed",
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Leaving and re-entering ProvisioningError is reported.
+	setInstanceStatus(s.machine, status.Provisioning, "")
+	wc.AssertNoChange()
+	setInstanceStatus(s.machine, status.ProvisioningError, "quota exceeded")
+	wc.AssertChange(s.machine.Id())
+	wc.AssertNoChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *MachineSuite) TestWatchPrincipalUnits(c *gc.C) {
 	// TODO(mjs) - MODELUUID - test with multiple models with
 	// identically named units and ensure there's no leakage.
@@ -1501,6 +1741,74 @@ func (s *MachineSuite) TestWatchUnits(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *MachineSuite) TestWatchPrincipalUnitsOnly(c *gc.C) {
+	w := s.machine.WatchPrincipalUnitsOnly()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange()
+	wc.AssertNoChange()
+
+	// Assign a unit; change detected.
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	mysql0, err := mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql0.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange("mysql/0")
+	wc.AssertNoChange()
+
+	// Add a subordinate to the unit; no change.
+	s.AddTestingApplication(c, "logging", s.AddTestingCharm(c, "logging"))
+	eps, err := s.State.InferEndpoints("mysql", "logging")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	mysqlru0, err := rel.Unit(mysql0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysqlru0.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	logging0, err := s.State.Unit("logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Destroy and remove the subordinate; no change.
+	err = logging0.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+	err = logging0.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Make the principal Dying; change detected.
+	err = mysql0.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange("mysql/0")
+	wc.AssertNoChange()
+
+	// A fresh watcher reports only the principal.
+	mysql1, err := mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql1.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange("mysql/1")
+	wc.AssertNoChange()
+	mysqlru1, err := rel.Unit(mysql1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysqlru1.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+
+	w = s.machine.WatchPrincipalUnitsOnly()
+	defer testing.AssertStop(c, w)
+	wc = testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange("mysql/0", "mysql/1")
+	wc.AssertNoChange()
+}
+
 func (s *MachineSuite) TestWatchUnitsDiesOnStateClose(c *gc.C) {
 	testWatcherDiesWhenStateCloses(c, s.Session, s.modelTag, s.State.ControllerTag(), func(c *gc.C, st *state.State) waiter {
 		m, err := st.Machine(s.machine.Id())
@@ -9,6 +9,7 @@ import (
 	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
 )
 
 type MinUnitsSuite struct {
@@ -330,7 +331,7 @@ func (s *MinUnitsSuite) TestEnsureMinUnitsServiceNotAlive(c *gc.C) {
 	c.Assert(s.application.EnsureMinUnits(), gc.ErrorMatches, expectedErr)
 
 	// An error is returned if the application was removed.
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(s.application.EnsureMinUnits(), gc.ErrorMatches, expectedErr)
 }
@@ -421,3 +422,53 @@ func (s *MinUnitsSuite) TestEnsureMinUnitsDecreaseMinUnitsAfter(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	assertAllUnits(c, service, 3)
 }
+
+func (s *MinUnitsSuite) TestWatchMinUnits(c *gc.C) {
+	w := s.application.WatchMinUnits()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Changing the minimum units triggers a change.
+	err := s.application.SetMinUnits(2)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Setting the same value is a no-op.
+	err = s.application.SetMinUnits(2)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Unrelated changes to the application are ignored.
+	err = s.application.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+	s.addUnits(c, 1)
+	wc.AssertNoChange()
+
+	// Restoring the minimum units to zero triggers a change.
+	err = s.application.SetMinUnits(0)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *MinUnitsSuite) TestWatchMinUnitsFromStaleApplication(c *gc.C) {
+	stale, err := s.State.Application(s.application.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.application.SetMinUnits(2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := stale.WatchMinUnits()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// The watcher starts from the current minimum units, not the
+	// stale application's, so unrelated changes are still ignored.
+	err = s.application.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
@@ -1496,7 +1496,7 @@ func (s *ModelCloudValidationSuite) initializeState(
 }
 
 func assertCleanupRuns(c *gc.C, st *state.State) {
-	err := st.Cleanup()
+	_, err := st.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 }
 
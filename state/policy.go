@@ -140,6 +140,20 @@ func (st *State) resolveConstraints(cons constraints.Value) (constraints.Value,
 	return validator.Merge(envCons, cons)
 }
 
+// constraintsConflicts returns the model constraints that would be
+// overridden by cons when it is resolved by resolveConstraints.
+func (st *State) constraintsConflicts(cons constraints.Value) ([]constraints.MergeConflict, error) {
+	validator, err := st.constraintsValidator()
+	if err != nil {
+		return nil, err
+	}
+	envCons, err := st.ModelConstraints()
+	if err != nil {
+		return nil, err
+	}
+	return validator.MergeConflicts(envCons, cons)
+}
+
 // validateConstraints returns an error if the given constraints are not valid for the
 // current model, and also any unsupported attributes.
 func (st *State) validateConstraints(cons constraints.Value) ([]string, error) {
@@ -349,7 +349,14 @@ func (r *Relation) removeOps(ignoreService string, departingUnitName string) ([]
 			ops = append(ops, epOps...)
 		}
 	}
-	ops = append(ops, removeStatusOp(r.st, r.globalScope()))
+	return append(ops, r.removeReferencesOps()...), nil
+}
+
+// removeReferencesOps returns the operations that remove the documents
+// referring to the relation, to be run alongside the removal of the
+// relation document itself.
+func (r *Relation) removeReferencesOps() []txn.Op {
+	ops := []txn.Op{removeStatusOp(r.st, r.globalScope())}
 	ops = append(ops, removeRelationNetworksOps(r.st, r.doc.Key)...)
 	re := r.st.RemoteEntities()
 	tokenOps := re.removeRemoteEntityOps(r.Tag())
@@ -357,7 +364,41 @@ func (r *Relation) removeOps(ignoreService string, departingUnitName string) ([]
 	offerOps := removeOfferConnectionsForRelationOps(r.Id())
 	ops = append(ops, offerOps...)
 	cleanupOp := newCleanupOp(cleanupRelationSettings, fmt.Sprintf("r#%d#", r.Id()))
-	return append(ops, cleanupOp), nil
+	return append(ops, cleanupOp)
+}
+
+// forceRemoveOps returns the operations required to remove a relation
+// with no units in scope, regardless of its life or the life of the
+// applications it relates. The relation count of every application
+// other than ignoreApplication is decremented, and any of them that is
+// Dying with no other references left is removed too.
+func (r *Relation) forceRemoveOps(ignoreApplication string) ([]txn.Op, error) {
+	ops := []txn.Op{{
+		C:      relationsC,
+		Id:     r.doc.DocID,
+		Assert: bson.D{{"unitcount", 0}},
+		Remove: true,
+	}}
+	for _, ep := range r.doc.Endpoints {
+		if ep.ApplicationName == ignoreApplication {
+			continue
+		}
+		app, err := applicationByName(r.st, ep.ApplicationName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var epOps []txn.Op
+		if app.IsRemote() {
+			epOps, err = r.removeRemoteEndpointOps(ep, true)
+		} else {
+			epOps, err = r.removeCounterpartEndpointOps(ep)
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, epOps...)
+	}
+	return append(ops, r.removeReferencesOps()...), nil
 }
 
 func (r *Relation) removeLocalEndpointOps(ep Endpoint, departingUnitName string) ([]txn.Op, error) {
@@ -378,26 +419,41 @@ func (r *Relation) removeLocalEndpointOps(ep Endpoint, departingUnitName string)
 		cannotDieYet := bson.D{{"unitcount", bson.D{{"$gt", 0}}}}
 		asserts = append(hasRelation, cannotDieYet...)
 	} else {
-		// This service may require immediate removal.
-		applications, closer := r.st.db().GetCollection(applicationsC)
-		defer closer()
+		return r.removeCounterpartEndpointOps(ep)
+	}
+	return []txn.Op{{
+		C:      applicationsC,
+		Id:     r.st.docID(ep.ApplicationName),
+		Assert: asserts,
+		Update: bson.D{{"$inc", bson.D{{"relationcount", -1}}}},
+	}}, nil
+}
 
-		svc := &Application{st: r.st}
-		hasLastRef := bson.D{{"life", Dying}, {"unitcount", 0}, {"relationcount", 1}}
-		removable := append(bson.D{{"_id", ep.ApplicationName}}, hasLastRef...)
-		if err := applications.Find(removable).One(&svc.doc); err == nil {
-			return svc.removeOps(hasLastRef)
-		} else if err != mgo.ErrNotFound {
-			return nil, err
-		}
-		// If not, we must check that this is still the case when the
-		// transaction is applied.
-		asserts = bson.D{{"$or", []bson.D{
-			{{"life", Alive}},
-			{{"unitcount", bson.D{{"$gt", 0}}}},
-			{{"relationcount", bson.D{{"$gt", 1}}}},
-		}}}
+// removeCounterpartEndpointOps returns the operations that drop the
+// relation's reference from the local application at ep, when that
+// application is not the one being removed or departed. If the relation
+// is the application's last reference and it is Dying, the application
+// is removed as well.
+func (r *Relation) removeCounterpartEndpointOps(ep Endpoint) ([]txn.Op, error) {
+	// This service may require immediate removal.
+	applications, closer := r.st.db().GetCollection(applicationsC)
+	defer closer()
+
+	svc := &Application{st: r.st}
+	hasLastRef := bson.D{{"life", Dying}, {"unitcount", 0}, {"relationcount", 1}}
+	removable := append(bson.D{{"_id", ep.ApplicationName}}, hasLastRef...)
+	if err := applications.Find(removable).One(&svc.doc); err == nil {
+		return svc.removeOps(hasLastRef)
+	} else if err != mgo.ErrNotFound {
+		return nil, err
 	}
+	// If not, we must check that this is still the case when the
+	// transaction is applied.
+	asserts := bson.D{{"$or", []bson.D{
+		{{"life", Alive}},
+		{{"unitcount", bson.D{{"$gt", 0}}}},
+		{{"relationcount", bson.D{{"$gt", 1}}}},
+	}}}
 	return []txn.Op{{
 		C:      applicationsC,
 		Id:     r.st.docID(ep.ApplicationName),
@@ -492,6 +548,156 @@ func (r *Relation) RelatedEndpoints(applicationname string) ([]Endpoint, error)
 	return eps, nil
 }
 
+// ReplaceEndpoint replaces one of the relation's endpoints with an
+// endpoint of the same application, role, interface and scope, as when
+// a charm upgrade renames the endpoint. The relation keeps its id and
+// key, and so its tag: agents and API clients holding the relation are
+// unaffected, as are units in scope and their settings. Cross-model
+// relations are not supported.
+func (r *Relation) ReplaceEndpoint(oldEp, newEp Endpoint) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot replace endpoint %q of relation %q", oldEp, r)
+	switch {
+	case newEp.ApplicationName != oldEp.ApplicationName:
+		return errors.Errorf("endpoint %q belongs to a different application", newEp)
+	case newEp.Role != oldEp.Role:
+		return errors.Errorf("endpoint %q has role %q, expected %q", newEp, newEp.Role, oldEp.Role)
+	case newEp.Interface != oldEp.Interface:
+		return errors.Errorf("endpoint %q has interface %q, expected %q", newEp, newEp.Interface, oldEp.Interface)
+	case newEp.Scope != oldEp.Scope:
+		return errors.Errorf("endpoint %q has scope %q, expected %q", newEp, newEp.Scope, oldEp.Scope)
+	}
+	if newEp == oldEp {
+		return nil
+	}
+	crossModel, err := r.IsCrossModel()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if crossModel {
+		return errors.NotSupportedf("replacing endpoints of cross-model relations")
+	}
+	var endpoints []Endpoint
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := r.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if r.doc.Life != Alive {
+			return nil, errors.New("relation is not alive")
+		}
+		endpoints = make([]Endpoint, len(r.doc.Endpoints))
+		found := false
+		for i, ep := range r.doc.Endpoints {
+			if ep == oldEp {
+				ep = newEp
+				found = true
+			}
+			endpoints[i] = ep
+		}
+		if !found {
+			return nil, errors.NotFoundf("endpoint %q", oldEp)
+		}
+		ops, err := endpointsUnusedOps(r.st, endpoints, r.doc.DocID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:  relationsC,
+			Id: r.doc.DocID,
+			Assert: bson.D{
+				{"life", Alive},
+				{"endpoints", r.doc.Endpoints},
+			},
+			Update: bson.D{{"$set", bson.D{{"endpoints", endpoints}}}},
+		}), nil
+	}
+	if err := r.st.db().Run(buildTxn); err != nil {
+		return err
+	}
+	r.doc.Endpoints = endpoints
+	return nil
+}
+
+// endpointsRelationDoc returns the document of the relation between
+// exactly the given endpoints, matching on the endpoints the relation
+// records rather than on its key, which keeps the original endpoint
+// names if an endpoint has since been replaced.
+func endpointsRelationDoc(st *State, endpoints []Endpoint) (*relationDoc, error) {
+	relations, closer := st.db().GetCollection(relationsC)
+	defer closer()
+
+	sel := bson.D{{"endpoints", bson.D{{"$size", len(endpoints)}}}}
+	var all []bson.D
+	for _, ep := range endpoints {
+		all = append(all, bson.D{{"endpoints", bson.D{{"$elemMatch", bson.D{
+			{"applicationname", ep.ApplicationName},
+			{"relation.name", ep.Name},
+		}}}}})
+	}
+	sel = append(sel, bson.DocElem{"$and", all})
+	var doc relationDoc
+	err := relations.Find(sel).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("relation %q", relationKey(endpoints))
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get relation %q", relationKey(endpoints))
+	}
+	return &doc, nil
+}
+
+// endpointsUnusedOps returns an error satisfying errors.IsAlreadyExists
+// if any relation not yet dead, other than the one with the given
+// document id, is between exactly the given endpoints. Otherwise it
+// returns operations that assert this still holds when the transaction
+// runs: neither ReplaceEndpoint nor AddRelation may concurrently give
+// the endpoints to another relation.
+func endpointsUnusedOps(st *State, endpoints []Endpoint, docID string) ([]txn.Op, error) {
+	relations, closer := st.db().GetCollection(relationsC)
+	defer closer()
+
+	// A relation keeps its applications when an endpoint is replaced,
+	// so only those between the endpoints' applications can conflict.
+	var appNames []string
+	for _, ep := range endpoints {
+		appNames = append(appNames, ep.ApplicationName)
+	}
+	sel := bson.D{{"endpoints.applicationname", bson.D{{"$all", appNames}}}}
+	key := relationKey(endpoints)
+	var docs []relationDoc
+	if err := relations.Find(sel).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get relations of %q", key)
+	}
+	keyDocID := st.docID(key)
+	keyDocSeen := keyDocID == docID
+	var ops []txn.Op
+	for _, doc := range docs {
+		if doc.DocID == docID {
+			continue
+		}
+		if doc.Life != Dead && relationKey(doc.Endpoints) == key {
+			return nil, errors.AlreadyExistsf("relation %v", key)
+		}
+		if doc.DocID == keyDocID {
+			keyDocSeen = true
+		}
+		ops = append(ops, txn.Op{
+			C:      relationsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"endpoints", doc.Endpoints}},
+		})
+	}
+	if !keyDocSeen {
+		ops = append(ops, txn.Op{
+			C:      relationsC,
+			Id:     keyDocID,
+			Assert: txn.DocMissing,
+		})
+	}
+	return ops, nil
+}
+
 // Unit returns a RelationUnit for the supplied unit.
 func (r *Relation) Unit(u *Unit) (*RelationUnit, error) {
 	const isLocalUnit = true
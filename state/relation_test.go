@@ -268,6 +268,186 @@ func (s *RelationSuite) TestDestroyRelation(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *RelationSuite) TestReplaceEndpoint(c *gc.C) {
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wordpressEP, err := wordpress.Endpoint("db")
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	mysqlEP, err := mysql.Endpoint("server")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(wordpressEP, mysqlEP)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Put a unit of each application in scope, using a separate copy
+	// of the relation which will be stale after the endpoint is
+	// replaced, as the units' agents would.
+	stale, err := s.State.Relation(rel.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	var relUnits []*state.RelationUnit
+	for i, app := range []*state.Application{wordpress, mysql} {
+		unit, err := app.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		ru, err := stale.Unit(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ru.EnterScope(map[string]interface{}{"unit": i})
+		c.Assert(err, jc.ErrorIsNil)
+		relUnits = append(relUnits, ru)
+	}
+
+	renamedEP := mysqlEP
+	renamedEP.Name = "database"
+	tag := rel.Tag()
+	err = rel.ReplaceEndpoint(mysqlEP, renamedEP)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rel.Endpoints(), jc.SameContents, []state.Endpoint{wordpressEP, renamedEP})
+
+	// The relation keeps its id, key and tag, so clients holding the
+	// tag still find it, and it is found by its new endpoints only.
+	c.Assert(rel.String(), gc.Equals, "wordpress:db mysql:server")
+	c.Assert(rel.Tag(), gc.Equals, tag)
+	renamed, err := s.State.KeyRelation(tag.(names.RelationTag).Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(renamed.Id(), gc.Equals, rel.Id())
+	c.Assert(renamed.Life(), gc.Equals, state.Alive)
+	c.Assert(renamed.Endpoints(), jc.SameContents, []state.Endpoint{wordpressEP, renamedEP})
+	err = stale.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stale.Endpoints(), jc.SameContents, []state.Endpoint{wordpressEP, renamedEP})
+	_, err = s.State.EndpointsRelation(wordpressEP, mysqlEP)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	byEndpoints, err := s.State.EndpointsRelation(wordpressEP, renamedEP)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(byEndpoints.Id(), gc.Equals, rel.Id())
+
+	// The renamed endpoints cannot be related a second time.
+	_, err = s.State.AddRelation(wordpressEP, renamedEP)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	// The units remain in scope, with their settings.
+	for i, ru := range relUnits {
+		inScope, err := ru.InScope()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(inScope, jc.IsTrue)
+		joined, err := ru.Joined()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(joined, jc.IsTrue)
+		settings, err := ru.Settings()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(settings.Map(), jc.DeepEquals, map[string]interface{}{"unit": i})
+	}
+	count, err := relUnits[0].CounterpartUnitCount()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 1)
+
+	// Units leaving scope through the stale copy still remove the
+	// relation once it is destroyed.
+	err = renamed.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, ru := range relUnits {
+		err = ru.LeaveScope()
+		c.Assert(err, jc.ErrorIsNil)
+		inScope, err := ru.InScope()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(inScope, jc.IsFalse)
+	}
+	err = renamed.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RelationSuite) TestReplaceEndpointIncompatible(c *gc.C) {
+	s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	mysqlEP, err := rel.Endpoint("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+
+	otherInterface := mysqlEP
+	otherInterface.Name = "database"
+	otherInterface.Interface = "pgsql"
+	err = rel.ReplaceEndpoint(mysqlEP, otherInterface)
+	c.Assert(err, gc.ErrorMatches, `cannot replace endpoint "mysql:server" of relation "wordpress:db mysql:server": `+
+		`endpoint "mysql:database" has interface "pgsql", expected "mysql"`)
+
+	otherRole := mysqlEP
+	otherRole.Name = "database"
+	otherRole.Role = charm.RoleRequirer
+	err = rel.ReplaceEndpoint(mysqlEP, otherRole)
+	c.Assert(err, gc.ErrorMatches, `.*endpoint "mysql:database" has role "requirer", expected "provider"`)
+
+	otherApp := mysqlEP
+	otherApp.ApplicationName = "wordpress"
+	err = rel.ReplaceEndpoint(mysqlEP, otherApp)
+	c.Assert(err, gc.ErrorMatches, `.*endpoint "wordpress:server" belongs to a different application`)
+
+	missing := mysqlEP
+	missing.Name = "nonsense"
+	renamed := mysqlEP
+	renamed.Name = "database"
+	err = rel.ReplaceEndpoint(missing, renamed)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Nothing has changed.
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rel.String(), gc.Equals, "wordpress:db mysql:server")
+}
+
+func (s *RelationSuite) setupReplaceEndpoint(c *gc.C) (*state.Relation, state.Endpoint, state.Endpoint, state.Endpoint) {
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wordpressEP, err := wordpress.Endpoint("db")
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql-alternative"))
+	prodEP, err := mysql.Endpoint("prod")
+	c.Assert(err, jc.ErrorIsNil)
+	devEP, err := mysql.Endpoint("dev")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(wordpressEP, prodEP)
+	c.Assert(err, jc.ErrorIsNil)
+	return rel, wordpressEP, prodEP, devEP
+}
+
+func (s *RelationSuite) TestReplaceEndpointExistingRelation(c *gc.C) {
+	rel, wordpressEP, prodEP, devEP := s.setupReplaceEndpoint(c)
+	_, err := s.State.AddRelation(wordpressEP, devEP)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = rel.ReplaceEndpoint(prodEP, devEP)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `cannot replace endpoint "mysql:prod" of relation "wordpress:db mysql:prod": `+
+		`relation wordpress:db mysql:dev already exists`)
+}
+
+func (s *RelationSuite) TestReplaceEndpointAddRelationRace(c *gc.C) {
+	rel, wordpressEP, prodEP, devEP := s.setupReplaceEndpoint(c)
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := s.State.AddRelation(wordpressEP, devEP)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err := rel.ReplaceEndpoint(prodEP, devEP)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rel.Endpoints(), jc.SameContents, []state.Endpoint{wordpressEP, prodEP})
+}
+
+func (s *RelationSuite) TestAddRelationReplaceEndpointRace(c *gc.C) {
+	rel, wordpressEP, prodEP, devEP := s.setupReplaceEndpoint(c)
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := rel.ReplaceEndpoint(prodEP, devEP)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	_, err := s.State.AddRelation(wordpressEP, devEP)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	byEndpoints, err := s.State.EndpointsRelation(wordpressEP, devEP)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(byEndpoints.Id(), gc.Equals, rel.Id())
+}
+
 func (s *RelationSuite) TestDestroyPeerRelation(c *gc.C) {
 	// Check that a peer relation cannot be destroyed directly.
 	riakch := s.AddTestingCharm(c, "riak")
@@ -528,6 +708,52 @@ func (s *RelationSuite) TestWatchLifeSuspendedStatusDead(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *RelationSuite) TestWatchLifeSuspendedStatusOneEventPerChange(c *gc.C) {
+	rel := s.setupRelationStatus(c)
+	mysql, err := s.State.Application("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	u, err := mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	m := s.Factory.MakeMachine(c, &factory.MachineParams{})
+	err = u.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+	relUnit, err := rel.Unit(u)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := rel.WatchLifeSuspendedStatus()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange(rel.Tag().Id())
+	wc.AssertNoChange()
+
+	// Changes to other fields of the relation are not reported.
+	err = relUnit.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = rel.SetSuspended(true, "reason")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(rel.Tag().Id())
+	wc.AssertNoChange()
+
+	err = rel.SetSuspended(false, "")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(rel.Tag().Id())
+	wc.AssertNoChange()
+
+	// The unit in scope keeps the relation Dying.
+	err = rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(rel.Tag().Id())
+	wc.AssertNoChange()
+
+	// Leaving scope removes the relation, which is reported as Dead.
+	err = relUnit.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(rel.Tag().Id())
+	wc.AssertNoChange()
+}
+
 func (s *RelationSuite) setupRelationStatus(c *gc.C) *state.Relation {
 	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
 	wordpressEP, err := wordpress.Endpoint("db")
@@ -618,6 +844,24 @@ func (s *RelationSuite) TestSetSuspendFalse(c *gc.C) {
 	c.Assert(rel.Suspended(), jc.IsFalse)
 }
 
+func (s *RelationSuite) TestSetSuspendedKeepsRelationAlive(c *gc.C) {
+	rel := s.setupRelationStatus(c)
+	state.RemoveOfferConnectionsForRelation(c, rel)
+	for _, suspended := range []bool{true, false, true} {
+		reason := ""
+		if suspended {
+			reason = "paused by operator"
+		}
+		err := rel.SetSuspended(suspended, reason)
+		c.Assert(err, jc.ErrorIsNil)
+		rel, err = s.State.Relation(rel.Id())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(rel.Suspended(), gc.Equals, suspended)
+		c.Assert(rel.SuspendedReason(), gc.Equals, reason)
+		c.Assert(rel.Life(), gc.Equals, state.Alive)
+	}
+}
+
 func (s *RelationSuite) TestResumeRelationNoConsumeAccess(c *gc.C) {
 	rel := s.setupRelationStatus(c)
 	err := rel.SetSuspended(true, "reason")
@@ -77,6 +77,13 @@ var ErrCannotEnterScopeYet = stderrors.New("cannot enter scope yet: non-alive su
 // intervention; the relation will not be able to become Dead until all units
 // have departed its scopes.
 func (ru *RelationUnit) EnterScope(settings map[string]interface{}) error {
+	maxSize, err := relationSettingsSizeLimit(ru.st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := checkSettingsSize(settings, maxSize); err != nil {
+		return errors.Annotatef(err, "cannot enter scope for unit %q in relation %q", ru.unitName, ru.relation)
+	}
 	db, closer := ru.st.newDB()
 	defer closer()
 	relationScopes, closer := db.GetCollection(relationScopesC)
@@ -434,6 +441,55 @@ func (ru *RelationUnit) inScope(sel bson.D) (bool, error) {
 	return count > 0, nil
 }
 
+// CounterpartUnitCount returns the number of counterpart units that
+// are in the unit's scope and have not prepared to leave it. In a peer
+// relation the unit itself is not counted.
+func (ru *RelationUnit) CounterpartUnitCount() (int, error) {
+	relationScopes, closer := ru.st.db().GetCollection(relationScopesC)
+	defer closer()
+
+	prefix := ru._key(string(counterpartRole(ru.endpoint.Role)), "")
+	sel := bson.D{
+		{"key", bson.D{{"$regex", "^" + prefix}, {"$ne", ru.key()}}},
+		{"departing", bson.D{{"$ne", true}}},
+	}
+	count, err := relationScopes.Find(sel).Count()
+	if err != nil {
+		return 0, errors.Annotatef(err, "cannot count counterpart units of %q in relation %q", ru.unitName, ru.relation)
+	}
+	return count, nil
+}
+
+// CounterpartInScope returns whether the named unit is a counterpart of
+// the unit that is in the unit's scope and has not prepared to leave
+// it. A unit is never its own counterpart, even in a peer relation.
+func (ru *RelationUnit) CounterpartInScope(uname string) (_ bool, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot check scope of unit %q in relation %q", uname, ru.relation)
+	if !names.IsValidUnit(uname) {
+		return false, fmt.Errorf("%q is not a valid unit name", uname)
+	}
+	key, err := ru.unitKey(uname)
+	if err != nil {
+		return false, err
+	}
+	prefix := ru._key(string(counterpartRole(ru.endpoint.Role)), "")
+	if uname == ru.unitName || !strings.HasPrefix(key, prefix) {
+		return false, nil
+	}
+	relationScopes, closer := ru.st.db().GetCollection(relationScopesC)
+	defer closer()
+
+	sel := bson.D{
+		{"_id", key},
+		{"departing", bson.D{{"$ne", true}}},
+	}
+	count, err := relationScopes.Find(sel).Count()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // WatchScope returns a watcher which notifies of counterpart units
 // entering and leaving the unit's scope.
 func (ru *RelationUnit) WatchScope() *RelationScopeWatcher {
@@ -448,10 +504,58 @@ func watchRelationScope(
 	return newRelationScopeWatcher(st, scope, ignore)
 }
 
+// relationSettingsSizeLimit returns the largest serialized size, in
+// bytes, of a unit's settings within a relation, as configured for the
+// model. Every relation watcher reads these settings, so one oversized
+// node slows all of them down.
+func relationSettingsSizeLimit(st *State) (int, error) {
+	cfg, err := getModelConfig(st.db())
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return int(cfg.MaxRelationSettingsSizeMB()) * 1024 * 1024, nil
+}
+
 // Settings returns a Settings which allows access to the unit's settings
-// within the relation.
+// within the relation. Writing settings whose serialized size exceeds
+// the model's max-relation-settings-size fails with an error satisfying
+// IsSettingsTooLargeError.
 func (ru *RelationUnit) Settings() (*Settings, error) {
-	return readSettings(ru.st.db(), settingsC, ru.key())
+	maxSize, err := relationSettingsSizeLimit(ru.st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	node, err := readSettings(ru.st.db(), settingsC, ru.key())
+	if err != nil {
+		return nil, err
+	}
+	node.maxSize = maxSize
+	return node, nil
+}
+
+// CounterpartSettings returns a Settings holding the settings of the
+// unit with the supplied name within this relation. Only a unit's own
+// settings may be changed: if uname is not the name of this relation
+// unit's unit, the returned Settings is read-only, and writing it fails
+// with an error satisfying IsSettingsReadOnlyError.
+func (ru *RelationUnit) CounterpartSettings(uname string) (_ *Settings, err error) {
+	if uname == ru.unitName {
+		return ru.Settings()
+	}
+	defer errors.DeferredAnnotatef(&err, "cannot read settings for unit %q in relation %q", uname, ru.relation)
+	if !names.IsValidUnit(uname) {
+		return nil, fmt.Errorf("%q is not a valid unit name", uname)
+	}
+	key, err := ru.unitKey(uname)
+	if err != nil {
+		return nil, err
+	}
+	node, err := readSettings(ru.st.db(), settingsC, key)
+	if err != nil {
+		return nil, err
+	}
+	node.readOnly = true
+	return node, nil
 }
 
 // ReadSettings returns a map holding the settings of the unit with the
@@ -477,6 +581,23 @@ func (ru *RelationUnit) ReadSettings(uname string) (m map[string]interface{}, er
 	return node.Map(), nil
 }
 
+// SettingsRevision returns the revision of the settings of the unit with
+// the supplied name within this relation. The revision increases every
+// time the settings change, so it can be used to detect changes without
+// reading the settings themselves. A not-found error is returned if the
+// unit has never entered scope in the relation.
+func (ru *RelationUnit) SettingsRevision(uname string) (revision int64, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot read settings revision for unit %q in relation %q", uname, ru.relation)
+	if !names.IsValidUnit(uname) {
+		return 0, fmt.Errorf("%q is not a valid unit name", uname)
+	}
+	key, err := ru.unitKey(uname)
+	if err != nil {
+		return 0, err
+	}
+	return readSettingsVersion(ru.st.db(), settingsC, key)
+}
+
 // PublicAddressRetryArgs returns the retry strategy for getting a unit's public address.
 // Override for testing to use a different clock.
 var PublicAddressRetryArgs = func() retry.CallArgs {
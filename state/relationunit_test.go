@@ -8,6 +8,7 @@ import (
 	"math/rand"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/testing"
@@ -79,6 +81,254 @@ func (s *RelationUnitSuite) TestReadSettingsErrors(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot read settings for unit "riak/1" in relation "riak:ring": settings not found`)
 }
 
+func (s *RelationUnitSuite) TestSettingsRevision(c *gc.C) {
+	pr := newPeerRelation(c, s.State)
+
+	// Check the revision of missing settings cannot be read.
+	_, err := pr.ru1.SettingsRevision("riak/0")
+	c.Assert(err, gc.ErrorMatches, `cannot read settings revision for unit "riak/0" in relation "riak:ring": settings not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = pr.ru0.EnterScope(map[string]interface{}{"gene": "kelly"})
+	c.Assert(err, jc.ErrorIsNil)
+	rev0, err := pr.ru1.SettingsRevision("riak/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Writing the settings increments the revision.
+	node, err := pr.ru0.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	node.Set("meme", "socially-awkward-penguin")
+	_, err = node.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	rev1, err := pr.ru1.SettingsRevision("riak/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rev1, gc.Equals, rev0+1)
+
+	// Writing unchanged settings does not.
+	_, err = node.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	rev2, err := pr.ru0.SettingsRevision("riak/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rev2, gc.Equals, rev1)
+}
+
+func (s *RelationUnitSuite) TestSettingsRevisionErrors(c *gc.C) {
+	pr := newPeerRelation(c, s.State)
+
+	_, err := pr.ru0.SettingsRevision("nonsense")
+	c.Assert(err, gc.ErrorMatches, `cannot read settings revision for unit "nonsense" in relation "riak:ring": "nonsense" is not a valid unit name`)
+	_, err = pr.ru0.SettingsRevision("unknown/0")
+	c.Assert(err, gc.ErrorMatches, `cannot read settings revision for unit "unknown/0" in relation "riak:ring": application "unknown" is not a member of "riak:ring"`)
+}
+
+func (s *RelationUnitSuite) TestSettingsSizeLimit(c *gc.C) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"max-relation-settings-size": "1M",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	pr := newPeerRelation(c, s.State)
+	blob := strings.Repeat("x", 1<<20)
+
+	// Entering scope with oversized settings fails.
+	err = pr.ru0.EnterScope(map[string]interface{}{"blob": blob})
+	c.Assert(err, gc.ErrorMatches, `cannot enter scope for unit "riak/0" in relation "riak:ring": settings too large: \d+ bytes exceeds limit of 1048576 bytes`)
+	c.Assert(err, jc.Satisfies, state.IsSettingsTooLargeError)
+	assertNotInScope(c, pr.ru0)
+
+	// Settings under the limit can be written.
+	err = pr.ru0.EnterScope(map[string]interface{}{"gene": "kelly"})
+	c.Assert(err, jc.ErrorIsNil)
+	node, err := pr.ru0.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	node.Set("meme", "socially-awkward-penguin")
+	_, err = node.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Writes that would take the settings over the limit fail, and
+	// leave the stored settings untouched.
+	node.Set("blob", blob)
+	_, err = node.Write()
+	c.Assert(err, jc.Satisfies, state.IsSettingsTooLargeError)
+	settings, err := pr.ru1.ReadSettings("riak/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]interface{}{
+		"gene": "kelly",
+		"meme": "socially-awkward-penguin",
+	})
+}
+
+func (s *RelationUnitSuite) TestCounterpartSettings(c *gc.C) {
+	prr := newProReqRelation(c, &s.ConnSuite, charm.ScopeContainer)
+	err := prr.pru0.EnterScope(map[string]interface{}{"gene": "hackman"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.rru0.EnterScope(map[string]interface{}{"gene": "kelly"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The subordinate can write its own settings.
+	node, err := prr.rru0.CounterpartSettings("logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+	node.Set("meme", "foul-bachelor-frog")
+	_, err = node.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := prr.pru0.ReadSettings("logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m, gc.DeepEquals, map[string]interface{}{
+		"gene": "kelly",
+		"meme": "foul-bachelor-frog",
+	})
+
+	// It can read, but not write, its principal's settings.
+	node, err = prr.rru0.CounterpartSettings("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(node.Map(), gc.DeepEquals, map[string]interface{}{"gene": "hackman"})
+	node.Set("meme", "socially-awkward-penguin")
+	_, err = node.Write()
+	c.Assert(err, gc.ErrorMatches, `settings ".*#mysql/0" are read-only`)
+	c.Assert(err, jc.Satisfies, state.IsSettingsReadOnlyError)
+
+	// Nor can the principal write the subordinate's settings.
+	node, err = prr.pru0.CounterpartSettings("logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+	node.Delete("meme")
+	_, err = node.Write()
+	c.Assert(err, jc.Satisfies, state.IsSettingsReadOnlyError)
+
+	// The stored settings are untouched.
+	m, err = prr.rru0.ReadSettings("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m, gc.DeepEquals, map[string]interface{}{"gene": "hackman"})
+	m, err = prr.pru0.ReadSettings("logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m, gc.DeepEquals, map[string]interface{}{
+		"gene": "kelly",
+		"meme": "foul-bachelor-frog",
+	})
+}
+
+func (s *RelationUnitSuite) TestCounterpartSettingsErrors(c *gc.C) {
+	pr := newPeerRelation(c, s.State)
+
+	_, err := pr.ru0.CounterpartSettings("nonsense")
+	c.Assert(err, gc.ErrorMatches, `cannot read settings for unit "nonsense" in relation "riak:ring": "nonsense" is not a valid unit name`)
+	_, err = pr.ru0.CounterpartSettings("unknown/0")
+	c.Assert(err, gc.ErrorMatches, `cannot read settings for unit "unknown/0" in relation "riak:ring": application "unknown" is not a member of "riak:ring"`)
+	_, err = pr.ru0.CounterpartSettings("riak/1")
+	c.Assert(err, gc.ErrorMatches, `cannot read settings for unit "riak/1" in relation "riak:ring": settings not found`)
+}
+
+func (s *RelationUnitSuite) TestCounterpartUnitCountPeer(c *gc.C) {
+	pr := newPeerRelation(c, s.State)
+	assertCount := func(ru *state.RelationUnit, expect int) {
+		count, err := ru.CounterpartUnitCount()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(count, gc.Equals, expect)
+	}
+	assertCount(pr.ru0, 0)
+
+	// A peer unit does not count itself.
+	err := pr.ru0.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	assertCount(pr.ru0, 0)
+	assertCount(pr.ru1, 1)
+
+	err = pr.ru1.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = pr.ru2.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	assertCount(pr.ru0, 2)
+	assertCount(pr.ru3, 3)
+
+	// Departing and departed units are not counted.
+	err = pr.ru1.PrepareLeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	assertCount(pr.ru0, 1)
+	err = pr.ru2.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	assertCount(pr.ru0, 0)
+	assertCount(pr.ru3, 1)
+}
+
+func (s *RelationUnitSuite) TestCounterpartUnitCountProviderRequirer(c *gc.C) {
+	prr := newProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
+	assertCount := func(ru *state.RelationUnit, expect int) {
+		count, err := ru.CounterpartUnitCount()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(count, gc.Equals, expect)
+	}
+
+	err := prr.pru0.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.pru1.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.rru0.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Units on the same side of the relation are not counterparts.
+	assertCount(prr.pru0, 1)
+	assertCount(prr.rru0, 2)
+	assertCount(prr.rru1, 2)
+
+	err = prr.pru1.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	assertCount(prr.rru0, 1)
+}
+
+func (s *RelationUnitSuite) TestCounterpartInScope(c *gc.C) {
+	prr := newProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
+	assertInScope := func(ru *state.RelationUnit, uname string, expect bool) {
+		inScope, err := ru.CounterpartInScope(uname)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(inScope, gc.Equals, expect)
+	}
+	assertInScope(prr.rru0, "mysql/0", false)
+
+	err := prr.pru0.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.rru0.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	assertInScope(prr.rru0, "mysql/0", true)
+	assertInScope(prr.rru0, "mysql/1", false)
+	assertInScope(prr.pru0, "wordpress/0", true)
+
+	// Units on the same side of the relation are never counterparts.
+	assertInScope(prr.pru1, "mysql/0", false)
+	assertInScope(prr.rru0, "wordpress/0", false)
+
+	err = prr.pru0.PrepareLeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	assertInScope(prr.rru0, "mysql/0", false)
+}
+
+func (s *RelationUnitSuite) TestCounterpartInScopePeer(c *gc.C) {
+	pr := newPeerRelation(c, s.State)
+	err := pr.ru0.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	inScope, err := pr.ru1.CounterpartInScope("riak/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inScope, jc.IsTrue)
+
+	// A peer unit is not its own counterpart.
+	inScope, err = pr.ru0.CounterpartInScope("riak/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inScope, jc.IsFalse)
+
+	err = pr.ru0.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	inScope, err = pr.ru1.CounterpartInScope("riak/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inScope, jc.IsFalse)
+}
+
+func (s *RelationUnitSuite) TestCounterpartInScopeErrors(c *gc.C) {
+	pr := newPeerRelation(c, s.State)
+
+	_, err := pr.ru0.CounterpartInScope("nonsense")
+	c.Assert(err, gc.ErrorMatches, `cannot check scope of unit "nonsense" in relation "riak:ring": "nonsense" is not a valid unit name`)
+	_, err = pr.ru0.CounterpartInScope("unknown/0")
+	c.Assert(err, gc.ErrorMatches, `cannot check scope of unit "unknown/0" in relation "riak:ring": application "unknown" is not a member of "riak:ring"`)
+}
+
 func (s *RelationUnitSuite) TestPeerSettings(c *gc.C) {
 	pr := newPeerRelation(c, s.State)
 	rus := RUs{pr.ru0, pr.ru1}
@@ -385,7 +635,7 @@ func (s *RelationUnitSuite) TestDestroyRelationWithUnitsInScope(c *gc.C) {
 
 	// Check that unit settings for the original unit still exist, and have
 	// not yet been marked for deletion.
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 	assertSettings := func() {
 		settings, err := pr.ru1.ReadSettings("riak/0")
@@ -406,7 +656,7 @@ func (s *RelationUnitSuite) TestDestroyRelationWithUnitsInScope(c *gc.C) {
 	assertSettings()
 
 	// ...but they were scheduled for deletion.
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 	_, err = pr.ru1.ReadSettings("riak/0")
 	c.Assert(err, gc.ErrorMatches, `cannot read settings for unit "riak/0" in relation "riak:ring": settings not found`)
@@ -532,6 +782,47 @@ func (s *RelationUnitSuite) TestPeerWatchScope(c *gc.C) {
 	assertNotInScope(c, pr.ru1)
 }
 
+func (s *RelationUnitSuite) TestPeerWatchScopeWithSettings(c *gc.C) {
+	pr := newPeerRelation(c, s.State)
+	err := pr.ru1.EnterScope(map[string]interface{}{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Initial event includes the settings of units already in scope.
+	w0 := pr.ru0.WatchScopeWithSettings()
+	defer testing.AssertStop(c, w0)
+	s.assertScopeSettingsChange(c, w0, map[string]map[string]interface{}{
+		"riak/1": {"foo": "bar"},
+	}, nil)
+	s.assertNoScopeSettingsChange(c, w0)
+
+	// ru0 enters; the watcher ignores its own unit.
+	err = pr.ru0.EnterScope(map[string]interface{}{"baz": "qux"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoScopeSettingsChange(c, w0)
+
+	// ru2 enters; its settings are delivered with the event.
+	err = pr.ru2.EnterScope(map[string]interface{}{"hello": "world"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertScopeSettingsChange(c, w0, map[string]map[string]interface{}{
+		"riak/2": {"hello": "world"},
+	}, nil)
+	s.assertNoScopeSettingsChange(c, w0)
+
+	// Settings changes after entry are not reported.
+	node, err := pr.ru2.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	node.Set("hello", "again")
+	_, err = node.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoScopeSettingsChange(c, w0)
+
+	// ru1 leaves; only its name is reported.
+	err = pr.ru1.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertScopeSettingsChange(c, w0, nil, []string{"riak/1"})
+	s.assertNoScopeSettingsChange(c, w0)
+}
+
 func (s *RelationUnitSuite) TestProReqWatchScope(c *gc.C) {
 	prr := newProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
 	s.testProReqWatchScope(c, prr.pru0, prr.pru1, prr.rru0, prr.rru1, prr.watches)
@@ -815,6 +1106,32 @@ func (s *RelationUnitSuite) assertNoScopeChange(c *gc.C, ws ...*state.RelationSc
 	}
 }
 
+func (s *RelationUnitSuite) assertScopeSettingsChange(
+	c *gc.C, w *state.RelationScopeSettingsWatcher,
+	entered map[string]map[string]interface{}, left []string,
+) {
+	s.State.StartSync()
+	select {
+	case ch, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(ch.Entered, jc.DeepEquals, entered)
+		sort.Strings(left)
+		sort.Strings(ch.Left)
+		c.Assert(ch.Left, gc.DeepEquals, left)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("no change")
+	}
+}
+
+func (s *RelationUnitSuite) assertNoScopeSettingsChange(c *gc.C, w *state.RelationScopeSettingsWatcher) {
+	s.State.StartSync()
+	select {
+	case ch, ok := <-w.Changes():
+		c.Fatalf("got unwanted change: %#v, %t", ch, ok)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
 func (s *RelationUnitSuite) TestNetworksForRelation(c *gc.C) {
 	prr := newProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
 	err := prr.pu0.AssignToNewMachine()
@@ -1733,6 +2050,146 @@ func (s *WatchUnitsSuite) TestProviderRequirerGlobal(c *gc.C) {
 	mysqlWatcherC.AssertNoChange()
 }
 
+func (s *WatchUnitsSuite) TestWatchWithChangedKeys(c *gc.C) {
+	riak := s.AddTestingApplication(c, "riak", s.AddTestingCharm(c, "riak"))
+	rels, err := riak.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+	addUnit := func() *state.RelationUnit {
+		unit, err := riak.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		ru, err := rels[0].Unit(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		return ru
+	}
+	ru0 := addUnit()
+	ru1 := addUnit()
+
+	w := ru0.WatchWithChangedKeys()
+	defer testing.AssertStop(c, w)
+	nextChange := func() params.RelationUnitsChange {
+		s.State.StartSync()
+		select {
+		case change, ok := <-w.Changes():
+			c.Assert(ok, jc.IsTrue)
+			return change
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("watcher did not send change")
+		}
+		panic("unreachable")
+	}
+	assertNoChange := func() {
+		s.State.StartSync()
+		select {
+		case change := <-w.Changes():
+			c.Fatalf("watcher sent unexpected change: %#v", change)
+		case <-time.After(coretesting.ShortWait):
+		}
+	}
+	change := nextChange()
+	c.Assert(change.ChangedKeys, gc.HasLen, 0)
+	assertNoChange()
+
+	// Entering scope reports every key of the new unit.
+	err = ru1.EnterScope(map[string]interface{}{"a": "foo", "b": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	change = nextChange()
+	c.Assert(change.Changed, gc.HasLen, 1)
+	c.Assert(change.ChangedKeys["riak/1"], jc.SameContents, []string{"a", "b"})
+	assertNoChange()
+
+	// Subsequent changes report only the keys that changed.
+	node, err := ru1.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	node.Set("a", "baz")
+	node.Delete("b")
+	_, err = node.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	change = nextChange()
+	c.Assert(change.ChangedKeys, jc.DeepEquals, map[string][]string{
+		"riak/1": {"a", "b"},
+	})
+	assertNoChange()
+
+	// A write that leaves every value unchanged is still reported
+	// as a settings change, but without any changed keys.
+	err = state.RewriteRelationUnitSettings(ru1)
+	c.Assert(err, jc.ErrorIsNil)
+	change = nextChange()
+	c.Assert(change.Changed, gc.HasLen, 1)
+	c.Assert(change.ChangedKeys, gc.HasLen, 0)
+	assertNoChange()
+
+	// Leaving scope clears the snapshot, so re-entering reports
+	// every key again.
+	err = ru1.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	change = nextChange()
+	c.Assert(change.Departed, jc.DeepEquals, []string{"riak/1"})
+	c.Assert(change.ChangedKeys, gc.HasLen, 0)
+	assertNoChange()
+}
+
+func (s *WatchUnitsSuite) TestWatchCounterpart(c *gc.C) {
+	riak := s.AddTestingApplication(c, "riak", s.AddTestingCharm(c, "riak"))
+	rels, err := riak.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+	addUnit := func() *state.RelationUnit {
+		unit, err := riak.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		ru, err := rels[0].Unit(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		return ru
+	}
+	ru0 := addUnit()
+	ru1 := addUnit()
+	ru2 := addUnit()
+	err = ru1.EnterScope(map[string]interface{}{"a": "foo"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The initial event is empty, as riak/2 is not yet in scope.
+	w := ru0.WatchCounterpart("riak/2")
+	defer testing.AssertStop(c, w)
+	wc := testing.NewRelationUnitsWatcherC(c, s.State, w)
+	wc.AssertChange(nil, nil)
+	wc.AssertNoChange()
+
+	// Activity of other units is not reported.
+	changeSettings(c, ru1)
+	wc.AssertNoChange()
+	err = ru1.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// The counterpart joining, changing settings and departing is.
+	err = ru2.EnterScope(map[string]interface{}{"a": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange([]string{"riak/2"}, nil)
+	wc.AssertNoChange()
+	changeSettings(c, ru2)
+	wc.AssertChange([]string{"riak/2"}, nil)
+	wc.AssertNoChange()
+	err = ru1.EnterScope(map[string]interface{}{"a": "baz"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// A new watcher includes the counterpart in its initial event when
+	// it is already in scope.
+	w2 := ru0.WatchCounterpart("riak/2")
+	defer testing.AssertStop(c, w2)
+	w2c := testing.NewRelationUnitsWatcherC(c, s.State, w2)
+	w2c.AssertChange([]string{"riak/2"}, nil)
+	w2c.AssertNoChange()
+
+	err = ru2.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(nil, []string{"riak/2"})
+	wc.AssertNoChange()
+	w2c.AssertChange(nil, []string{"riak/2"})
+	w2c.AssertNoChange()
+}
+
 func (s *WatchUnitsSuite) TestProviderRequirerContainer(c *gc.C) {
 	// Create a pair of services and a relation between them.
 	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
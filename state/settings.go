@@ -112,6 +112,13 @@ type Settings struct {
 	// the value of the version field in the status document
 	// when it was read.
 	version int64
+
+	// maxSize, if non-zero, is the largest serialized size of the
+	// settings that Write will accept.
+	maxSize int
+	// readOnly, if true, causes Write to fail with an error
+	// satisfying IsSettingsReadOnlyError.
+	readOnly bool
 }
 
 // Keys returns the current keys in alphabetical order.
@@ -221,6 +228,12 @@ func (s *Settings) write(ops []txn.Op) error {
 // as a delta applied on top of the latest version of the node, to prevent
 // overwriting unrelated changes made to the node since it was last read.
 func (s *Settings) Write() ([]ItemChange, error) {
+	if s.readOnly {
+		return nil, &ErrSettingsReadOnly{Key: s.key}
+	}
+	if err := checkSettingsSize(s.core, s.maxSize); err != nil {
+		return nil, errors.Trace(err)
+	}
 	changes, ops := s.settingsUpdateOps()
 	if len(ops) > 0 {
 		err := s.write(ops)
@@ -231,6 +244,23 @@ func (s *Settings) Write() ([]ItemChange, error) {
 	return changes, nil
 }
 
+// checkSettingsSize returns an error satisfying IsSettingsTooLargeError
+// if the serialized size of settings exceeds limit. A zero limit
+// disables the check.
+func checkSettingsSize(settings map[string]interface{}, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	data, err := bson.Marshal(settings)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(data) > limit {
+		return &ErrSettingsTooLarge{Size: len(data), Limit: limit}
+	}
+	return nil
+}
+
 func newSettings(db Database, collection, key string) *Settings {
 	return &Settings{
 		db:         db,
@@ -304,6 +334,24 @@ func readSettingsDocInto(db Database, collection, key string, out interface{}) e
 	return err
 }
 
+// readSettingsVersion returns the version of the settings doc with the
+// given key, without reading the settings themselves.
+func readSettingsVersion(db Database, collection, key string) (int64, error) {
+	settings, closer := db.GetCollection(collection)
+	defer closer()
+
+	var doc struct {
+		Version int64 `bson:"version"`
+	}
+	err := settings.FindId(key).Select(bson.D{{"version", 1}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return 0, errors.NotFoundf("settings")
+	} else if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return doc.Version, nil
+}
+
 // ReadSettings returns the settings for the given key.
 func (st *State) ReadSettings(collection, key string) (*Settings, error) {
 	return readSettings(st.db(), collection, key)
@@ -318,6 +366,38 @@ func readSettings(db Database, collection, key string) (*Settings, error) {
 	return s, nil
 }
 
+// readSettingsBulk returns the settings for each of the given keys,
+// read with a single query. Keys without settings are absent from the
+// result.
+func readSettingsBulk(backend modelBackend, collection string, keys []string) (map[string]*Settings, error) {
+	result := make(map[string]*Settings, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+	docIDs := make([]string, len(keys))
+	for i, key := range keys {
+		docIDs[i] = backend.docID(key)
+	}
+	db := backend.db()
+	settings, closer := db.GetCollection(collection)
+	defer closer()
+
+	var docs []settingsDoc
+	query := settings.Find(bson.D{{"_id", bson.D{{"$in", docIDs}}}})
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read settings")
+	}
+	for _, doc := range docs {
+		key := backend.localID(doc.DocID)
+		s := newSettings(db, collection, key)
+		s.version = doc.Version
+		s.disk = doc.Settings
+		s.core = copyMap(s.disk, nil)
+		result[key] = s
+	}
+	return result, nil
+}
+
 var errSettingsExist = errors.New("cannot overwrite existing settings")
 
 func createSettingsOp(collection, key string, values map[string]interface{}) txn.Op {
@@ -4,10 +4,16 @@
 package state
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/mgo.v2/txn"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/mongo"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type SettingsSuite struct {
@@ -64,6 +70,47 @@ func (s *SettingsSuite) TestCannotWriteMissing(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *SettingsSuite) TestWatchSurvivesRemoveAndRecreate(c *gc.C) {
+	_, err := s.createSettings(s.key, map[string]interface{}{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := newEntityWatcher(s.state, s.collection, s.state.docID(s.key))
+	defer w.Stop()
+	assertOneChange := func() {
+		s.state.StartSync()
+		select {
+		case _, ok := <-w.Changes():
+			c.Assert(ok, jc.IsTrue)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("watcher did not send change")
+		}
+		s.state.StartSync()
+		select {
+		case <-w.Changes():
+			c.Fatalf("watcher sent unexpected change")
+		case <-time.After(coretesting.ShortWait):
+		}
+	}
+	assertOneChange()
+
+	// Removing the settings node is reported, and does not stop the
+	// watcher; readers observe the removal as a NotFound error.
+	err = removeSettings(s.state.db(), s.collection, s.key)
+	c.Assert(err, jc.ErrorIsNil)
+	assertOneChange()
+	_, err = s.readSettings()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Recreating the node is reported by the same watcher.
+	_, err = s.createSettings(s.key, map[string]interface{}{"foo": "baz"})
+	c.Assert(err, jc.ErrorIsNil)
+	assertOneChange()
+	node, err := s.readSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(node.Map(), gc.DeepEquals, map[string]interface{}{"foo": "baz"})
+	c.Assert(w.Err(), gc.Equals, tomb.ErrStillAlive)
+}
+
 func (s *SettingsSuite) TestUpdateWithWrite(c *gc.C) {
 	node, err := s.createSettings(s.key, nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -484,3 +531,82 @@ func (s *SettingsSuite) TestList(c *gc.C) {
 		"key#2": {"foo2": "bar2"},
 	})
 }
+
+func (s *SettingsSuite) TestReadSettingsBulk(c *gc.C) {
+	_, err := s.createSettings("key#1", map[string]interface{}{"foo1": "bar1"})
+	c.Assert(err, jc.ErrorIsNil)
+	node, err := s.createSettings("key#2", map[string]interface{}{"foo2": "bar2"})
+	c.Assert(err, jc.ErrorIsNil)
+	node.Set("foo2", "baz2")
+	_, err = node.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	backend := newFindCountingBackend(s.state)
+	keys := []string{"key#1", "key#2", "missing"}
+	nodes, err := readSettingsBulk(backend, s.collection, keys)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(backend.finds, gc.Equals, 1)
+	c.Assert(nodes, gc.HasLen, 2)
+
+	// The result must match what reading each key in turn gives.
+	for _, key := range keys {
+		expected, err := readSettings(s.state.db(), s.collection, key)
+		if errors.IsNotFound(err) {
+			c.Check(nodes[key], gc.IsNil)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(nodes[key], gc.NotNil)
+		c.Check(nodes[key].key, gc.Equals, expected.key)
+		c.Check(nodes[key].version, gc.Equals, expected.version)
+		c.Check(nodes[key].Map(), jc.DeepEquals, expected.Map())
+	}
+}
+
+func (s *SettingsSuite) TestReadSettingsBulkNoKeys(c *gc.C) {
+	backend := newFindCountingBackend(s.state)
+	nodes, err := readSettingsBulk(backend, s.collection, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nodes, gc.HasLen, 0)
+	c.Assert(backend.finds, gc.Equals, 0)
+}
+
+// findCountingBackend is a modelBackend which counts the queries made
+// through the collections of its database.
+type findCountingBackend struct {
+	modelBackend
+	finds int
+}
+
+func newFindCountingBackend(backend modelBackend) *findCountingBackend {
+	return &findCountingBackend{modelBackend: backend}
+}
+
+func (b *findCountingBackend) db() Database {
+	return findCountingDatabase{Database: b.modelBackend.db(), finds: &b.finds}
+}
+
+type findCountingDatabase struct {
+	Database
+	finds *int
+}
+
+func (db findCountingDatabase) GetCollection(name string) (mongo.Collection, SessionCloser) {
+	coll, closer := db.Database.GetCollection(name)
+	return findCountingCollection{Collection: coll, finds: db.finds}, closer
+}
+
+type findCountingCollection struct {
+	mongo.Collection
+	finds *int
+}
+
+func (coll findCountingCollection) Find(query interface{}) mongo.Query {
+	*coll.finds++
+	return coll.Collection.Find(query)
+}
+
+func (coll findCountingCollection) FindId(id interface{}) mongo.Query {
+	*coll.finds++
+	return coll.Collection.FindId(id)
+}
@@ -745,6 +745,48 @@ func (st *State) SetModelConstraints(cons constraints.Value) error {
 	return writeConstraints(st, modelGlobalKey, cons)
 }
 
+// ModelConstraintsRevision returns the current revision of the model
+// constraints, which changes every time they are set. It may be passed
+// to SetModelConstraintsAtRevision.
+func (st *State) ModelConstraintsRevision() (int64, error) {
+	revision, err := readTxnRevno(st.db(), constraintsC, modelGlobalKey)
+	if errors.Cause(err) == mgo.ErrNotFound {
+		return 0, errors.NotFoundf("model constraints")
+	} else if err != nil {
+		return 0, errors.Annotate(err, "cannot read model constraints revision")
+	}
+	return revision, nil
+}
+
+// SetModelConstraintsAtRevision replaces the current model constraints
+// as SetModelConstraints does, but only if they are still at the
+// expected revision. If they have been changed since, an error
+// satisfying IsConstraintsRevisionConflictError is returned and nothing
+// is written.
+func (st *State) SetModelConstraintsAtRevision(cons constraints.Value, expectedRevision int64) error {
+	unsupported, err := st.validateConstraints(cons)
+	if len(unsupported) > 0 {
+		logger.Warningf(
+			"setting model constraints: unsupported constraints: %v", strings.Join(unsupported, ","))
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	op := setConstraintsOp(modelGlobalKey, cons)
+	op.Assert = bson.D{{"txn-revno", expectedRevision}}
+	err = st.db().RunTransaction([]txn.Op{op})
+	if err == txn.ErrAborted {
+		actual, err := st.ModelConstraintsRevision()
+		if err != nil {
+			return errors.Annotate(err, "cannot set model constraints")
+		}
+		return &ErrConstraintsRevisionConflict{Expected: expectedRevision, Actual: actual}
+	}
+	if err != nil {
+		return errors.Annotate(err, "cannot set model constraints")
+	}
+	return nil
+}
+
 func (st *State) allMachines(machinesCollection mongo.Collection) ([]*Machine, error) {
 	mdocs := machineDocSlice{}
 	err := machinesCollection.Find(nil).All(&mdocs)
@@ -1192,8 +1234,10 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 				return nil, errors.Trace(err)
 			}
 			ops = append(ops, unitOps...)
+			// Units without a placement directive of their own
+			// are assigned according to the default policy.
 			placement := instance.Placement{}
-			if x < len(args.Placement) {
+			if x < len(args.Placement) && args.Placement[x] != nil {
 				placement = *args.Placement[x]
 			}
 			ops = append(ops, assignUnitOps(unitName, placement)...)
@@ -1283,6 +1327,9 @@ func (st *State) processIAASModelApplicationArgs(args *AddApplicationArgs) error
 	}
 
 	for _, placement := range args.Placement {
+		if placement == nil || *placement == (instance.Placement{}) {
+			continue
+		}
 		data, err := st.parsePlacement(placement)
 		if err != nil {
 			return errors.Trace(err)
@@ -1605,6 +1652,18 @@ func (st *State) AllApplications() (applications []*Application, err error) {
 // uniquely specify a possible relation once all implicit relations have been
 // filtered, the endpoints corresponding to that relation will be returned.
 func (st *State) InferEndpoints(names ...string) ([]Endpoint, error) {
+	return st.inferEndpoints(names, false)
+}
+
+// InferEndpointsStrict returns the endpoints corresponding to the supplied
+// names, as InferEndpoints does, except that implicit relations such as
+// juju-info are never considered. If the names only match implicit
+// relations, an error is returned.
+func (st *State) InferEndpointsStrict(names ...string) ([]Endpoint, error) {
+	return st.inferEndpoints(names, true)
+}
+
+func (st *State) inferEndpoints(names []string, strict bool) ([]Endpoint, error) {
 	// Collect all possible sane endpoint lists.
 	var candidates [][]Endpoint
 	switch len(names) {
@@ -1639,6 +1698,13 @@ func (st *State) InferEndpoints(names ...string) ([]Endpoint, error) {
 	default:
 		return nil, errors.Errorf("cannot relate %d endpoints", len(names))
 	}
+	if strict {
+		explicit := withoutImplicitEndpoints(candidates)
+		if len(candidates) > 0 && len(explicit) == 0 {
+			return nil, errors.Errorf("only implicit relations found")
+		}
+		candidates = explicit
+	}
 	// If there's ambiguity, try discarding implicit relations.
 	switch len(candidates) {
 	case 0:
@@ -1646,16 +1712,7 @@ func (st *State) InferEndpoints(names ...string) ([]Endpoint, error) {
 	case 1:
 		return candidates[0], nil
 	}
-	var filtered [][]Endpoint
-outer:
-	for _, cand := range candidates {
-		for _, ep := range cand {
-			if ep.IsImplicit() {
-				continue outer
-			}
-		}
-		filtered = append(filtered, cand)
-	}
+	filtered := withoutImplicitEndpoints(candidates)
 	if len(filtered) == 1 {
 		return filtered[0], nil
 	}
@@ -1668,6 +1725,22 @@ outer:
 		strings.Join(names, " "), strings.Join(keys, "; "))
 }
 
+// withoutImplicitEndpoints returns the candidate endpoint lists that do
+// not include any implicit endpoint.
+func withoutImplicitEndpoints(candidates [][]Endpoint) [][]Endpoint {
+	var filtered [][]Endpoint
+outer:
+	for _, cand := range candidates {
+		for _, ep := range cand {
+			if ep.IsImplicit() {
+				continue outer
+			}
+		}
+		filtered = append(filtered, cand)
+	}
+	return filtered
+}
+
 func isPeer(ep Endpoint) bool {
 	return ep.Role == charm.RolePeer
 }
@@ -1812,8 +1885,14 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 		} else if exists {
 			return nil, errors.AlreadyExistsf("relation %v", key)
 		}
+		// A relation whose endpoint has been replaced keeps its
+		// original key, so also look for one by its endpoints, and
+		// make sure none is given them before the relation is added.
+		ops, err := endpointsUnusedOps(st, eps, st.docID(key))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 		// Collect per-application operations, checking sanity as we go.
-		var ops []txn.Op
 		var subordinateCount int
 		series := map[string]bool{}
 		for _, ep := range eps {
@@ -1906,7 +1985,11 @@ func aliveApplication(st *State, name string) (ApplicationEntity, error) {
 
 // EndpointsRelation returns the existing relation with the given endpoints.
 func (st *State) EndpointsRelation(endpoints ...Endpoint) (*Relation, error) {
-	return st.KeyRelation(relationKey(endpoints))
+	doc, err := endpointsRelationDoc(st, endpoints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newRelation(st, doc), nil
 }
 
 // KeyRelation returns the existing relation with the given key (which can
@@ -1959,6 +2042,25 @@ func (st *State) AllRelations() (relations []*Relation, err error) {
 	return
 }
 
+// RelationsForInterface returns all relations in the model with an
+// endpoint using the given interface, ordered by id.
+func (st *State) RelationsForInterface(iface string) ([]*Relation, error) {
+	all, err := st.AllRelations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var relations []*Relation
+	for _, rel := range all {
+		for _, ep := range rel.Endpoints() {
+			if ep.Interface == iface {
+				relations = append(relations, rel)
+				break
+			}
+		}
+	}
+	return relations, nil
+}
+
 type relationDocSlice []relationDoc
 
 func (rdc relationDocSlice) Len() int      { return len(rdc) }
@@ -20,6 +20,7 @@ import (
 	"github.com/juju/utils/arch"
 	"github.com/juju/utils/clock"
 	"github.com/juju/utils/series"
+	"github.com/juju/utils/set"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6"
@@ -561,7 +562,7 @@ func (s *MultiModelStateSuite) TestWatchTwoModels(c *gc.C) {
 				return false
 			},
 			triggerEvent: func(st *state.State) {
-				err := st.Cleanup()
+				_, err := st.Cleanup()
 				c.Assert(err, jc.ErrorIsNil)
 			},
 		}, {
@@ -1006,6 +1007,38 @@ func (s *StateSuite) TestAddMachineExtraConstraints(c *gc.C) {
 	c.Assert(mcons, gc.DeepEquals, expectedCons)
 }
 
+func (s *StateSuite) TestAddOneMachineWithWarnings(c *gc.C) {
+	err := s.State.SetModelConstraints(constraints.MustParse("mem=4G cores=2"))
+	c.Assert(err, jc.ErrorIsNil)
+	m, warnings, err := s.State.AddOneMachineWithWarnings(state.MachineTemplate{
+		Series:      "quantal",
+		Constraints: constraints.MustParse("mem=8G root-disk=8G"),
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	mcons, err := m.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mcons, gc.DeepEquals, constraints.MustParse("mem=8G cores=2 root-disk=8G"))
+
+	c.Assert(warnings, gc.HasLen, 1)
+	c.Check(warnings[0].Attribute, gc.Equals, "mem")
+	c.Check(warnings[0].Value.Mem, gc.DeepEquals, mcons.Mem)
+	c.Check(warnings[0].Overridden, gc.DeepEquals, constraints.MustParse("mem=4G"))
+}
+
+func (s *StateSuite) TestAddOneMachineWithWarningsIgnoresPlacement(c *gc.C) {
+	err := s.State.SetModelConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, warnings, err := s.State.AddOneMachineWithWarnings(state.MachineTemplate{
+		Series:      "quantal",
+		Constraints: constraints.MustParse("mem=8G"),
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Placement:   "theplacement",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(warnings, gc.HasLen, 0)
+}
+
 func (s *StateSuite) TestAddMachinePlacementIgnoresModelConstraints(c *gc.C) {
 	err := s.State.SetModelConstraints(constraints.MustParse("mem=4G tags=foo"))
 	c.Assert(err, jc.ErrorIsNil)
@@ -1481,6 +1514,53 @@ func (s *StateSuite) TestAllRelations(c *gc.C) {
 	}
 }
 
+func (s *StateSuite) TestRelationsForInterface(c *gc.C) {
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	wordpressCharm := s.AddTestingCharm(c, "wordpress")
+	s.AddTestingApplication(c, "wordpress0", wordpressCharm)
+	s.AddTestingApplication(c, "wordpress1", wordpressCharm)
+	s.AddTestingApplication(c, "logging", s.AddTestingCharm(c, "logging"))
+	s.AddTestingApplication(c, "riak", s.AddTestingCharm(c, "riak"))
+
+	for _, apps := range [][]string{
+		{"wordpress0", "mysql"},
+		{"mysql", "logging"},
+		{"wordpress1", "mysql"},
+	} {
+		eps, err := s.State.InferEndpoints(apps...)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = s.State.AddRelation(eps...)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	mysqlEP, err := mysql.Endpoint("server")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mysqlEP.Interface, gc.Equals, "mysql")
+
+	// The riak peer relation was added with the riak application.
+	all, err := s.State.AllRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 4)
+
+	relationKeys := func(iface string) []string {
+		relations, err := s.State.RelationsForInterface(iface)
+		c.Assert(err, jc.ErrorIsNil)
+		var keys []string
+		for _, rel := range relations {
+			keys = append(keys, rel.String())
+		}
+		return keys
+	}
+	c.Assert(relationKeys("mysql"), gc.DeepEquals, []string{
+		"wordpress0:db mysql:server",
+		"wordpress1:db mysql:server",
+	})
+	c.Assert(relationKeys("juju-info"), gc.DeepEquals, []string{
+		"logging:info mysql:juju-info",
+	})
+	c.Assert(relationKeys("riak"), gc.DeepEquals, []string{"riak:ring"})
+	c.Assert(relationKeys("http"), gc.HasLen, 0)
+}
+
 func (s *StateSuite) TestAddApplication(c *gc.C) {
 	ch := s.AddTestingCharm(c, "dummy")
 	_, err := s.State.AddApplication(state.AddApplicationArgs{Name: "haha/borken", Charm: ch})
@@ -1873,6 +1953,9 @@ var inferEndpointsTests = []struct {
 	inputs  [][]string
 	eps     []state.Endpoint
 	err     string
+	// strictErr, if set, is the error expected from
+	// InferEndpointsStrict in place of the result above.
+	strictErr string
 }{
 	{
 		summary: "insane args",
@@ -2007,6 +2090,7 @@ var inferEndpointsTests = []struct {
 			{"lg", "wp:juju-info"},
 			{"lg:info", "wp:juju-info"},
 		},
+		strictErr: `only implicit relations found`,
 		eps: []state.Endpoint{{
 			ApplicationName: "lg",
 			Relation: charm.Relation{
@@ -2026,8 +2110,9 @@ var inferEndpointsTests = []struct {
 			},
 		}},
 	}, {
-		summary: "implicit relations will be chosen if there are no other options",
-		inputs:  [][]string{{"lg", "ms"}},
+		summary:   "implicit relations will be chosen if there are no other options",
+		inputs:    [][]string{{"lg", "ms"}},
+		strictErr: `only implicit relations found`,
 		eps: []state.Endpoint{{
 			ApplicationName: "lg",
 			Relation: charm.Relation{
@@ -2049,7 +2134,7 @@ var inferEndpointsTests = []struct {
 	},
 }
 
-func (s *StateSuite) TestInferEndpoints(c *gc.C) {
+func (s *StateSuite) addInferEndpointsApplications(c *gc.C) {
 	s.AddTestingApplication(c, "ms", s.AddTestingCharm(c, "mysql-alternative"))
 	s.AddTestingApplication(c, "wp", s.AddTestingCharm(c, "wordpress"))
 	loggingCh := s.AddTestingCharm(c, "logging")
@@ -2059,7 +2144,10 @@ func (s *StateSuite) TestInferEndpoints(c *gc.C) {
 	s.AddTestingApplication(c, "rk1", riak)
 	s.AddTestingApplication(c, "rk2", riak)
 	s.AddTestingApplication(c, "lg-p", s.AddTestingCharm(c, "logging-principal"))
+}
 
+func (s *StateSuite) TestInferEndpoints(c *gc.C) {
+	s.addInferEndpointsApplications(c)
 	for i, t := range inferEndpointsTests {
 		c.Logf("test %d: %s", i, t.summary)
 		for j, input := range t.inputs {
@@ -2075,6 +2163,26 @@ func (s *StateSuite) TestInferEndpoints(c *gc.C) {
 	}
 }
 
+func (s *StateSuite) TestInferEndpointsStrict(c *gc.C) {
+	s.addInferEndpointsApplications(c)
+	for i, t := range inferEndpointsTests {
+		c.Logf("test %d: %s", i, t.summary)
+		for j, input := range t.inputs {
+			c.Logf("  input %d: %+v", j, input)
+			eps, err := s.State.InferEndpointsStrict(input...)
+			switch {
+			case t.strictErr != "":
+				c.Assert(err, gc.ErrorMatches, t.strictErr)
+			case t.err != "":
+				c.Assert(err, gc.ErrorMatches, t.err)
+			default:
+				c.Assert(err, jc.ErrorIsNil)
+				c.Assert(eps, gc.DeepEquals, t.eps)
+			}
+		}
+	}
+}
+
 func (s *StateSuite) TestModelConstraints(c *gc.C) {
 	// Environ constraints start out empty (for now).
 	cons, err := s.State.ModelConstraints()
@@ -2098,6 +2206,43 @@ func (s *StateSuite) TestModelConstraints(c *gc.C) {
 	c.Assert(cons5, gc.DeepEquals, cons4)
 }
 
+func (s *StateSuite) TestSetModelConstraintsAtRevision(c *gc.C) {
+	revision, err := s.State.ModelConstraintsRevision()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons := constraints.Value{Mem: uint64p(1024)}
+	err = s.State.SetModelConstraintsAtRevision(cons, revision)
+	c.Assert(err, jc.ErrorIsNil)
+	cons1, err := s.State.ModelConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons1, gc.DeepEquals, cons)
+
+	newRevision, err := s.State.ModelConstraintsRevision()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newRevision, gc.Not(gc.Equals), revision)
+}
+
+func (s *StateSuite) TestSetModelConstraintsAtRevisionConflict(c *gc.C) {
+	revision, err := s.State.ModelConstraintsRevision()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Someone else sets the constraints in the meantime.
+	cons := constraints.Value{CpuPower: uint64p(250)}
+	err = s.State.SetModelConstraints(cons)
+	c.Assert(err, jc.ErrorIsNil)
+	actual, err := s.State.ModelConstraintsRevision()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetModelConstraintsAtRevision(constraints.Value{Mem: uint64p(1024)}, revision)
+	c.Assert(err, jc.Satisfies, state.IsConstraintsRevisionConflictError)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf("constraints changed: expected revision %d, found %d", revision, actual))
+
+	// The other update is not lost.
+	cons1, err := s.State.ModelConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons1, gc.DeepEquals, cons)
+}
+
 func (s *StateSuite) TestSetInvalidConstraints(c *gc.C) {
 	cons := constraints.MustParse("mem=4G instance-type=foo")
 	err := s.State.SetModelConstraints(cons)
@@ -2277,6 +2422,175 @@ func (s *StateSuite) TestWatchApplicationsDiesOnStateClose(c *gc.C) {
 	})
 }
 
+func (s *StateSuite) assertEntitiesChange(c *gc.C, w *state.EntitiesWatcher, expect ...string) {
+	s.State.StartSync()
+	select {
+	case entities, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+		tags := make([]string, len(entities))
+		for i, entity := range entities {
+			tags[i] = entity.Tag
+		}
+		c.Assert(tags, jc.DeepEquals, expect)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("watcher did not send change")
+	}
+}
+
+func (s *StateSuite) assertNoEntitiesChange(c *gc.C, w *state.EntitiesWatcher) {
+	s.State.StartSync()
+	select {
+	case entities := <-w.Changes():
+		c.Fatalf("watcher sent unexpected change: %v", entities)
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *StateSuite) TestWatchModelEntitiesInitialEvent(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchModelEntities()
+	defer statetesting.AssertStop(c, w)
+	s.assertEntitiesChange(c, w,
+		machine.Tag().String(),
+		app.Tag().String(),
+		unit.Tag().String(),
+	)
+	s.assertNoEntitiesChange(c, w)
+}
+
+func (s *StateSuite) TestWatchModelEntitiesMergesChanges(c *gc.C) {
+	w := s.State.WatchModelEntities()
+	defer statetesting.AssertStop(c, w)
+	s.assertEntitiesChange(c, w)
+	s.assertNoEntitiesChange(c, w)
+
+	// Changes across all three collections are reported on the one
+	// watcher. They may be delivered over more than one event, but no
+	// event repeats a tag.
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	expect := set.NewStrings(
+		machine.Tag().String(),
+		app.Tag().String(),
+		unit.Tag().String(),
+	)
+	seen := set.NewStrings()
+	for seen.Size() < expect.Size() {
+		s.State.StartSync()
+		select {
+		case entities, ok := <-w.Changes():
+			c.Assert(ok, jc.IsTrue)
+			event := set.NewStrings()
+			for _, entity := range entities {
+				c.Assert(event.Contains(entity.Tag), jc.IsFalse)
+				event.Add(entity.Tag)
+			}
+			seen = seen.Union(event)
+		case <-time.After(testing.LongWait):
+			c.Fatalf("watcher did not send change")
+		}
+	}
+	c.Assert(seen.SortedValues(), jc.DeepEquals, expect.SortedValues())
+	s.assertNoEntitiesChange(c, w)
+
+	// Changes not affecting life are not reported.
+	err = machine.SetProvisioned("i-blah", "fake-nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoEntitiesChange(c, w)
+}
+
+func (s *StateSuite) TestWatchModelEntitiesDiesOnStateClose(c *gc.C) {
+	testWatcherDiesWhenStateCloses(c, s.Session, s.modelTag, s.State.ControllerTag(), func(c *gc.C, st *state.State) waiter {
+		w := st.WatchModelEntities()
+		<-w.Changes()
+		return w
+	})
+}
+
+func (s *StateSuite) assertMachinesChange(c *gc.C, w *state.MachinesDetailedWatcher, expect state.MachinesChange) {
+	s.State.StartSync()
+	select {
+	case change, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+		c.Check(change.Added, jc.SameContents, expect.Added)
+		c.Check(change.Changed, jc.SameContents, expect.Changed)
+		c.Check(change.Dead, jc.SameContents, expect.Dead)
+		c.Check(change.Removed, jc.SameContents, expect.Removed)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("watcher did not send change")
+	}
+}
+
+func (s *StateSuite) assertNoMachinesChange(c *gc.C, w *state.MachinesDetailedWatcher) {
+	s.State.StartSync()
+	select {
+	case change := <-w.Changes():
+		c.Fatalf("watcher sent unexpected change: %#v", change)
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *StateSuite) TestWatchMachinesDetailed(c *gc.C) {
+	alive, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	dead, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = dead.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Dead machines are reported as Dead from the start.
+	w := s.State.WatchMachinesDetailed()
+	defer statetesting.AssertStop(c, w)
+	s.assertMachinesChange(c, w, state.MachinesChange{
+		Added: []string{alive.Id()},
+		Dead:  []string{dead.Id()},
+	})
+	s.assertNoMachinesChange(c, w)
+
+	// Adding a machine is reported as such.
+	added, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMachinesChange(c, w, state.MachinesChange{Added: []string{added.Id()}})
+	s.assertNoMachinesChange(c, w)
+
+	// A change of life short of Dead is reported as a change.
+	err = alive.SetProvisioned(instance.Id("i-blah"), "fake-nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = alive.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMachinesChange(c, w, state.MachinesChange{Changed: []string{alive.Id()}})
+	s.assertNoMachinesChange(c, w)
+
+	// Becoming Dead is reported as such, and the machine then going
+	// away altogether as removal.
+	err = alive.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMachinesChange(c, w, state.MachinesChange{Dead: []string{alive.Id()}})
+	s.assertNoMachinesChange(c, w)
+	err = alive.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = dead.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMachinesChange(c, w, state.MachinesChange{Removed: []string{alive.Id(), dead.Id()}})
+	s.assertNoMachinesChange(c, w)
+}
+
+func (s *StateSuite) TestWatchMachinesDetailedDiesOnStateClose(c *gc.C) {
+	testWatcherDiesWhenStateCloses(c, s.Session, s.modelTag, s.State.ControllerTag(), func(c *gc.C, st *state.State) waiter {
+		w := st.WatchMachinesDetailed()
+		<-w.Changes()
+		return w
+	})
+}
+
 func (s *StateSuite) TestWatchMachinesBulkEvents(c *gc.C) {
 	// Alive machine...
 	alive, err := s.State.AddMachine("quantal", state.JobHostUnits)
@@ -2324,6 +2638,29 @@ func (s *StateSuite) TestWatchMachinesBulkEvents(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *StateSuite) TestLifecycleWatcherUnknownCollection(c *gc.C) {
+	w := state.NewLifecycleWatcher(s.State, "no-such-collection")
+	defer statetesting.AssertKillAndWait(c, w)
+	select {
+	case _, ok := <-w.Changes():
+		c.Assert(ok, jc.IsFalse)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("watcher did not stop")
+	}
+	err := w.Err()
+	c.Assert(err, jc.Satisfies, state.IsUnknownCollectionError)
+	c.Assert(err, gc.ErrorMatches, `cannot watch unknown collection "no-such-collection"`)
+}
+
+func (s *StateSuite) TestLifecycleWatcherEmptyCollection(c *gc.C) {
+	// A known collection with no documents yields an empty initial event.
+	w := state.NewLifecycleWatcher(s.State, "applications")
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange()
+	wc.AssertNoChange()
+}
+
 func (s *StateSuite) TestWatchMachinesLifecycle(c *gc.C) {
 	// Initial event is empty when no machines.
 	w := s.State.WatchModelMachines()
@@ -2420,6 +2757,60 @@ func (s *StateSuite) TestWatchMachinesIgnoresContainers(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *StateSuite) TestWatchHostMachines(c *gc.C) {
+	// A controller machine that cannot host units is never reported.
+	_, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	host, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchHostMachines()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange(host.Id())
+	wc.AssertNoChange()
+
+	// Add another controller machine: not reported.
+	controller, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Add a host machine: reported.
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits, state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(other.Id())
+	wc.AssertNoChange()
+
+	setJobs := func(m *state.Machine, jobs ...state.MachineJob) {
+		err := state.RunTransaction(s.State, []mgotxn.Op{{
+			C:      "machines",
+			Id:     state.DocID(s.State, m.Id()),
+			Update: bson.D{{"$set", bson.D{{"jobs", jobs}}}},
+		}})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// A machine that gains JobHostUnits is reported as added.
+	setJobs(controller, state.JobManageModel, state.JobHostUnits)
+	wc.AssertChange(controller.Id())
+	wc.AssertNoChange()
+
+	// A machine that loses JobHostUnits is reported as removed, and
+	// is not reported again while it cannot host units.
+	setJobs(other, state.JobManageModel)
+	wc.AssertChange(other.Id())
+	wc.AssertNoChange()
+	err = other.SetProvisioned(instance.Id("i-other"), "fake-nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Life changes of host machines are reported.
+	err = host.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(host.Id())
+	wc.AssertNoChange()
+}
+
 func (s *StateSuite) TestWatchContainerLifecycle(c *gc.C) {
 	// Add a host machine.
 	template := state.MachineTemplate{
@@ -2560,6 +2951,35 @@ func (s *StateSuite) TestWatchMachineHardwareCharacteristics(c *gc.C) {
 	err = machine.SetAgentVersion(vers)
 	c.Assert(err, jc.ErrorIsNil)
 	wc.AssertNoChange()
+
+	// Alter other instance data: not reported.
+	err = machine.SetKeepInstance(true)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
+func (s *StateSuite) TestWatchMachineHardwareCharacteristicsProvisionedWithHardware(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	hc := instance.MustParseHardware("arch=amd64 mem=4G cores=2")
+	err = machine.SetProvisioned(instance.Id("i-blah"), "fake-nonce", &hc)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A fresh watcher sends the initial event only.
+	w := machine.WatchHardwareCharacteristics()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err = machine.SetKeepInstance(true)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+	err = machine.SetKeepInstance(false)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
 }
 
 func (s *StateSuite) TestWatchControllerInfo(c *gc.C) {
@@ -3000,6 +3420,38 @@ func (s *StateSuite) TestWatchForModelConfigControllerChanges(c *gc.C) {
 	wc.AssertOneChange()
 }
 
+func (s *StateSuite) TestWatchForModelConfigKeyChanges(c *gc.C) {
+	w := s.model.WatchForModelConfigKeyChanges("http-proxy", "https-proxy")
+	defer statetesting.AssertStop(c, w)
+
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	// Initially we get one change notification.
+	wc.AssertOneChange()
+
+	// Changing a watched key triggers a change notification.
+	err := s.model.UpdateModelConfig(attrs{"http-proxy": "http://proxy.example.com"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Changing an unwatched key does not.
+	err = s.model.UpdateModelConfig(attrs{"ftp-proxy": "ftp://proxy.example.com"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Neither does writing the same value again.
+	err = s.model.UpdateModelConfig(attrs{"http-proxy": "http://proxy.example.com"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Removing a watched key triggers a change notification.
+	err = s.model.UpdateModelConfig(nil, []string{"http-proxy"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *StateSuite) TestAddAndGetEquivalence(c *gc.C) {
 	// The equivalence tested here isn't necessarily correct, and
 	// comparing private details is discouraged in the project.
@@ -3109,6 +3561,39 @@ func testSetPassword(c *gc.C, getEntity func() (state.Authenticator, error)) {
 	}
 }
 
+// passwordChangedTimer is implemented by entities that record when
+// their password was last set.
+type passwordChangedTimer interface {
+	state.Authenticator
+	PasswordChangedTime() time.Time
+}
+
+func testPasswordChangedTime(c *gc.C, clock *gitjujutesting.Clock, getEntity func() (passwordChangedTimer, error)) {
+	e, err := getEntity()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = e.SetPassword(goodPassword)
+	c.Assert(err, jc.ErrorIsNil)
+	first := e.PasswordChangedTime()
+	c.Assert(first.Equal(clock.Now().Round(time.Second)), jc.IsTrue, gc.Commentf("changed at %v", first))
+
+	// Check a newly-fetched entity has the same time.
+	e2, err := getEntity()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(e2.PasswordChangedTime().Equal(first), jc.IsTrue)
+
+	// Each new password advances the time.
+	clock.Advance(time.Minute)
+	err = e.SetPassword(alternatePassword)
+	c.Assert(err, jc.ErrorIsNil)
+	second := e.PasswordChangedTime()
+	c.Assert(second.Equal(first.Add(time.Minute)), jc.IsTrue, gc.Commentf("changed at %v", second))
+
+	err = e2.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(e2.PasswordChangedTime().Equal(second), jc.IsTrue)
+}
+
 type entity interface {
 	state.Entity
 	state.Lifer
@@ -3298,14 +3783,14 @@ func (s *StateSuite) TestWatchCleanups(c *gc.C) {
 	wc.AssertOneChange()
 
 	// Handle that cleanup doc and create another, check one change.
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 	err = relV.Destroy()
 	c.Assert(err, jc.ErrorIsNil)
 	wc.AssertOneChange()
 
 	// Clean up final doc, check change.
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 	wc.AssertOneChange()
 
@@ -3314,6 +3799,31 @@ func (s *StateSuite) TestWatchCleanups(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *StateSuite) TestWatchCleanupsForceDestroyMachine(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchCleanups()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Force-destroying the machine queues a cleanup.
+	err = m.ForceDestroy()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Running the cleanup removes its document, which is reported;
+	// once drained, the watcher stays quiet.
+	_, err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	needed, err := s.State.NeedsCleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(needed, jc.IsFalse)
+	wc.AssertOneChange()
+	wc.AssertNoChange()
+}
+
 func (s *StateSuite) TestWatchCleanupsDiesOnStateClose(c *gc.C) {
 	testWatcherDiesWhenStateCloses(c, s.Session, s.modelTag, s.State.ControllerTag(), func(c *gc.C, st *state.State) waiter {
 		w := st.WatchCleanups()
@@ -3346,7 +3856,7 @@ func (s *StateSuite) TestWatchCleanupsBulk(c *gc.C) {
 	wc.AssertOneChange()
 
 	// Clean them both up, check one change.
-	err = s.State.Cleanup()
+	_, err = s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
 	wc.AssertOneChange()
 }
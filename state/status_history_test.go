@@ -316,3 +316,32 @@ func (s *StatusHistorySuite) TestSameValueNotRepeated(c *gc.C) {
 	c.Assert(history[0].Message, gc.Equals, "current status")
 	c.Assert(history[1].Message, gc.Equals, "waiting for machine")
 }
+
+func (s *StatusHistorySuite) TestInstanceStatusHistoryRecordsTransitions(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+
+	now := time.Now()
+	for i, statusInfo := range []status.StatusInfo{
+		{Status: status.Provisioning, Message: "allocating node"},
+		{Status: status.ProvisioningError, Message: "no matching node"},
+		{Status: status.Provisioning, Message: "retrying"},
+	} {
+		when := now.Add(time.Duration(i) * time.Second)
+		statusInfo.Since = &when
+		err := machine.SetInstanceStatus(statusInfo)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	history, err := machine.InstanceStatusHistory(status.StatusHistoryFilter{Size: 3})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 3)
+	c.Check(history[0].Status, gc.Equals, status.Provisioning)
+	c.Check(history[0].Message, gc.Equals, "retrying")
+	c.Check(history[1].Status, gc.Equals, status.ProvisioningError)
+	c.Check(history[1].Message, gc.Equals, "no matching node")
+	c.Check(history[2].Status, gc.Equals, status.Provisioning)
+	c.Check(history[2].Message, gc.Equals, "allocating node")
+	for i := 1; i < len(history); i++ {
+		c.Check(history[i].Since.Before(*history[i-1].Since), jc.IsTrue)
+	}
+}
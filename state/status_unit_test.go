@@ -6,10 +6,12 @@ package state_test
 import (
 	"time" // Only used for time types.
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/testing"
 )
@@ -137,6 +139,69 @@ func (s *UnitStatusSuite) TestGetSetStatusGone(c *gc.C) {
 	c.Check(statusInfo, gc.DeepEquals, status.StatusInfo{})
 }
 
+func (s *UnitStatusSuite) TestSetUnitStatuses(c *gc.C) {
+	other := s.Factory.MakeUnit(c, nil)
+	gone := s.Factory.MakeUnit(c, nil)
+	err := gone.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := testing.ZeroTime()
+	err = s.State.SetUnitStatuses([]state.UnitStatusArg{{
+		UnitName: s.unit.Name(),
+		Status:   status.StatusInfo{Status: status.Active, Message: "one", Since: &now},
+	}, {
+		UnitName: gone.Name(),
+		Status:   status.StatusInfo{Status: status.Active, Message: "gone", Since: &now},
+	}, {
+		UnitName: other.Name(),
+		Status:   status.StatusInfo{Status: status.Blocked, Message: "two", Since: &now},
+	}})
+	c.Assert(err, gc.ErrorMatches, `cannot set unit statuses: units `+gone.Name()+` not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	statusInfo, err := s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(statusInfo.Status, gc.Equals, status.Active)
+	c.Check(statusInfo.Message, gc.Equals, "one")
+	statusInfo, err = other.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(statusInfo.Status, gc.Equals, status.Blocked)
+	c.Check(statusInfo.Message, gc.Equals, "two")
+}
+
+func (s *UnitStatusSuite) TestSetUnitStatusesUnitRemovedConcurrently(c *gc.C) {
+	gone := s.Factory.MakeUnit(c, nil)
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := gone.Destroy()
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	now := testing.ZeroTime()
+	err := s.State.SetUnitStatuses([]state.UnitStatusArg{{
+		UnitName: gone.Name(),
+		Status:   status.StatusInfo{Status: status.Active, Message: "gone", Since: &now},
+	}, {
+		UnitName: s.unit.Name(),
+		Status:   status.StatusInfo{Status: status.Active, Message: "still here", Since: &now},
+	}})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	statusInfo, err := s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(statusInfo.Status, gc.Equals, status.Active)
+	c.Check(statusInfo.Message, gc.Equals, "still here")
+}
+
+func (s *UnitStatusSuite) TestSetUnitStatusesInvalidStatus(c *gc.C) {
+	err := s.State.SetUnitStatuses([]state.UnitStatusArg{{
+		UnitName: s.unit.Name(),
+		Status:   status.StatusInfo{Status: status.Status("vliegkat")},
+	}})
+	c.Assert(err, gc.ErrorMatches, `cannot set unit statuses: cannot set invalid status "vliegkat" for unit ".*"`)
+
+	s.checkInitialStatus(c)
+}
+
 func (s *UnitStatusSuite) TestSetUnitStatusSince(c *gc.C) {
 	now := testing.ZeroTime()
 	sInfo := status.StatusInfo{
@@ -198,3 +263,57 @@ func (s *UnitStatusSuite) TestStatusHistoryLong(c *gc.C) {
 		checkPrimedUnitStatus(c, statusInfo, 24-i, 0)
 	}
 }
+
+func (s *UnitStatusSuite) TestWatchStatus(c *gc.C) {
+	w := s.unit.WatchStatus()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	now := time.Now()
+	setStatus := func(value status.Status, message string, offset int, data map[string]interface{}) {
+		since := now.Add(time.Duration(offset) * time.Second)
+		err := s.unit.SetStatus(status.StatusInfo{
+			Status:  value,
+			Message: message,
+			Data:    data,
+			Since:   &since,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	setStatus(status.Maintenance, "installing", 1, nil)
+	wc.AssertOneChange()
+	setStatus(status.Active, "ready", 2, nil)
+	wc.AssertOneChange()
+
+	history, err := s.unit.StatusHistory(status.StatusHistoryFilter{Size: 10})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 3)
+	c.Check(history[0].Status, gc.Equals, status.Active)
+	c.Check(history[0].Message, gc.Equals, "ready")
+	c.Check(history[1].Status, gc.Equals, status.Maintenance)
+	c.Check(history[1].Message, gc.Equals, "installing")
+	checkInitialWorkloadStatus(c, history[2])
+
+	// Changing only the status data does not notify.
+	setStatus(status.Active, "ready", 3, map[string]interface{}{"foo": "bar"})
+	wc.AssertNoChange()
+
+	// An error in the unit agent is reported as the unit's status.
+	since := now.Add(4 * time.Second)
+	err = s.unit.Agent().SetStatus(status.StatusInfo{
+		Status:  status.Error,
+		Message: "hook failed",
+		Since:   &since,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	statusInfo, err := s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(statusInfo.Status, gc.Equals, status.Error)
+	c.Check(statusInfo.Message, gc.Equals, "hook failed")
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -101,6 +102,7 @@ type unitDoc struct {
 	Life                   Life
 	TxnRevno               int64 `bson:"txn-revno"`
 	PasswordHash           string
+	PasswordChanged        time.Time `bson:",omitempty"`
 
 	// ProviderId is used by CAAS models.
 	ProviderId    string        `bson:"provider-id"`
@@ -285,20 +287,31 @@ func (u *Unit) SetPassword(password string) error {
 // to the value supplied. This is split out from SetPassword to allow direct
 // manipulation in tests (to check for backwards compatibility).
 func (u *Unit) setPasswordHash(passwordHash string) error {
+	changed := u.st.nowToTheSecond()
 	ops := []txn.Op{{
 		C:      unitsC,
 		Id:     u.doc.DocID,
 		Assert: notDeadDoc,
-		Update: bson.D{{"$set", bson.D{{"passwordhash", passwordHash}}}},
+		Update: bson.D{{"$set", bson.D{
+			{"passwordhash", passwordHash},
+			{"passwordchanged", changed},
+		}}},
 	}}
 	err := u.st.db().RunTransaction(ops)
 	if err != nil {
 		return fmt.Errorf("cannot set password of unit %q: %v", u, onAbort(err, ErrDead))
 	}
 	u.doc.PasswordHash = passwordHash
+	u.doc.PasswordChanged = changed
 	return nil
 }
 
+// PasswordChangedTime returns when the password for the unit's agent
+// was last set, or the zero time if it never has been.
+func (u *Unit) PasswordChangedTime() time.Time {
+	return u.doc.PasswordChanged.UTC()
+}
+
 // PasswordValid returns whether the given password is valid
 // for the given unit.
 func (u *Unit) PasswordValid(password string) bool {
@@ -887,6 +900,19 @@ func (u *Unit) noAssignedMachineOp() txn.Op {
 	}
 }
 
+// Addresses returns all the addresses of the unit's assigned machine,
+// each with its type and scope. PublicAddress and PrivateAddress select
+// from these, preferring addresses of the right scope and then IPv4
+// addresses over hostnames and IPv6 addresses.
+func (u *Unit) Addresses() ([]network.Address, error) {
+	m, err := u.machine()
+	if err != nil {
+		unitLogger.Tracef("%v", err)
+		return nil, errors.Trace(err)
+	}
+	return m.Addresses(), nil
+}
+
 // PublicAddress returns the public address of the unit.
 func (u *Unit) PublicAddress() (network.Address, error) {
 	m, err := u.machine()
@@ -1025,6 +1051,70 @@ func (u *Unit) SetStatus(unitStatus status.StatusInfo) error {
 	})
 }
 
+// UnitStatusArg holds the workload status to set for a single unit
+// with State.SetUnitStatuses.
+type UnitStatusArg struct {
+	UnitName string
+	Status   status.StatusInfo
+}
+
+// SetUnitStatuses sets the workload status of many units in a single
+// transaction. Each unit's status document is asserted individually, so
+// units that are removed while the statuses are being set are skipped
+// rather than preventing the rest from being set; if any were skipped,
+// a NotFound error naming them is returned once the others are set.
+func (st *State) SetUnitStatuses(args []UnitStatusArg) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set unit statuses")
+	docs := make([]statusDoc, len(args))
+	for i, arg := range args {
+		if !names.IsValidUnit(arg.UnitName) {
+			return errors.NotValidf("unit name %q", arg.UnitName)
+		}
+		if !status.ValidWorkloadStatus(arg.Status.Status) {
+			return errors.Errorf("cannot set invalid status %q for unit %q", arg.Status.Status, arg.UnitName)
+		}
+		docs[i] = statusDoc{
+			Status:     arg.Status.Status,
+			StatusInfo: arg.Status.Message,
+			StatusData: utils.EscapeKeys(arg.Status.Data),
+			Updated:    timeOrNow(arg.Status.Since, st.clock()).UnixNano(),
+		}
+	}
+
+	db := st.db()
+	var missing []string
+	var applied []int
+	buildTxn := func(int) ([]txn.Op, error) {
+		missing, applied = nil, nil
+		var ops []txn.Op
+		for i, arg := range args {
+			statusOps, err := statusSetOps(db, docs[i], unitGlobalKey(arg.UnitName))
+			if errors.Cause(err) == mgo.ErrNotFound {
+				missing = append(missing, arg.UnitName)
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, statusOps...)
+			applied = append(applied, i)
+		}
+		if len(ops) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return ops, nil
+	}
+	if err := db.Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	for _, i := range applied {
+		probablyUpdateStatusHistory(db, unitGlobalKey(args[i].UnitName), docs[i])
+	}
+	if len(missing) > 0 {
+		return errors.NotFoundf("units %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // OpenPortsOnSubnet opens the given port range and protocol for the unit on the
 // given subnet, which can be empty. When non-empty, subnetID must refer to an
 // existing, alive subnet, otherwise an error is returned. Returns an error if
@@ -1745,34 +1835,6 @@ func (u *Unit) assignToNewMachineOps(
 		return nil, nil, err
 	}
 
-	// Ensure the host machine is really clean.
-	if parentId != "" {
-		mparent, err := u.st.Machine(parentId)
-		if err != nil {
-			return nil, nil, err
-		}
-		if !mparent.Clean() {
-			return nil, nil, machineNotCleanErr
-		}
-		containers, err := mparent.Containers()
-		if err != nil {
-			return nil, nil, err
-		}
-		if len(containers) > 0 {
-			return nil, nil, machineNotCleanErr
-		}
-		parentDocId := u.st.docID(parentId)
-		ops = append(ops, txn.Op{
-			C:      machinesC,
-			Id:     parentDocId,
-			Assert: bson.D{{"clean", true}},
-		}, txn.Op{
-			C:      containerRefsC,
-			Id:     parentDocId,
-			Assert: bson.D{hasNoContainersTerm},
-		})
-	}
-
 	// The unit's subordinates must not change while we're
 	// assigning it to a machine, to ensure machine storage
 	// is created for subordinate units.
@@ -1860,7 +1922,14 @@ func (u *Unit) AssignToNewMachineOrContainer() (err error) {
 		}
 		var ops []txn.Op
 		m, ops, err = u.assignToNewMachineOps(template, host.Id, *cons.Container)
-		return ops, err
+		if err != nil {
+			return nil, err
+		}
+		hostOps, err := u.st.cleanHostOps(host.Id)
+		if err != nil {
+			return nil, err
+		}
+		return append(ops, hostOps...), nil
 	}
 	if err := u.st.db().Run(buildTxn); err != nil {
 		if errors.Cause(err) == machineNotCleanErr {
@@ -1927,6 +1996,92 @@ func (u *Unit) AssignToNewMachine() (err error) {
 	return nil
 }
 
+// cleanHostOps returns txn.Ops asserting that the machine with the given
+// id is clean and hosts no containers. It returns machineNotCleanErr if
+// that is already not the case.
+func (st *State) cleanHostOps(machineId string) ([]txn.Op, error) {
+	host, err := st.Machine(machineId)
+	if err != nil {
+		return nil, err
+	}
+	if !host.Clean() {
+		return nil, machineNotCleanErr
+	}
+	containers, err := host.Containers()
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) > 0 {
+		return nil, machineNotCleanErr
+	}
+	hostDocId := st.docID(machineId)
+	return []txn.Op{{
+		C:      machinesC,
+		Id:     hostDocId,
+		Assert: bson.D{{"clean", true}},
+	}, {
+		C:      containerRefsC,
+		Id:     hostDocId,
+		Assert: bson.D{hasNoContainersTerm},
+	}}, nil
+}
+
+// assignWithPlacement assigns the unit according to the given placement.
+// Any machine or container the placement calls for is added in the same
+// transaction that assigns the unit to it, so no machine is left behind
+// if the assignment fails.
+func (u *Unit) assignWithPlacement(placement *instance.Placement) (err error) {
+	data, err := u.st.parsePlacement(placement)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if data.placementType() == machinePlacement {
+		m, err := u.st.Machine(data.machineId)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return u.AssignToMachine(m)
+	}
+	defer assignContextf(&err, u.Name(), "new machine")
+	var m *Machine
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		var err error
+		u := u // don't change outer var
+		if attempt > 0 {
+			u, err = u.st.Unit(u.Name())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		cons, err := u.Constraints()
+		if err != nil {
+			return nil, err
+		}
+		storageParams, err := u.machineStorageParams()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		template := MachineTemplate{
+			Series:                u.doc.Series,
+			Constraints:           *cons,
+			Jobs:                  []MachineJob{JobHostUnits},
+			Placement:             data.directive,
+			Volumes:               storageParams.volumes,
+			VolumeAttachments:     storageParams.volumeAttachments,
+			Filesystems:           storageParams.filesystems,
+			FilesystemAttachments: storageParams.filesystemAttachments,
+		}
+		var ops []txn.Op
+		m, ops, err = u.assignToNewMachineOps(template, data.machineId, data.containerType)
+		return ops, err
+	}
+	if err := u.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	u.doc.MachineId = m.doc.Id
+	return nil
+}
+
 type byStorageInstance []StorageAttachment
 
 func (b byStorageInstance) Len() int      { return len(b) }
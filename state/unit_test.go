@@ -6,7 +6,7 @@ package state_test
 import (
 	"fmt"
 	"strconv"
-	"time" // Only used for time types.
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -14,6 +14,8 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6"
 	"gopkg.in/juju/worker.v1"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
@@ -235,6 +237,60 @@ func (s *UnitSuite) TestPublicAddress(c *gc.C) {
 	c.Check(address.Value, gc.Equals, "8.8.8.8")
 }
 
+func (s *UnitSuite) TestAddresses(c *gc.C) {
+	_, err := s.unit.Addresses()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	addresses, err := s.unit.Addresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, gc.HasLen, 0)
+
+	hostname := network.NewScopedAddress("unit.example.com", network.ScopePublic)
+	publicIPv6 := network.NewScopedAddress("2001:db8::1", network.ScopePublic)
+	publicIPv4 := network.NewScopedAddress("8.8.8.8", network.ScopePublic)
+	cloudLocal := network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal)
+	err = machine.SetProviderAddresses(hostname, publicIPv6, publicIPv4, cloudLocal)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The type and scope of each address survive the round trip.
+	addresses, err = s.unit.Addresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.SameContents, []network.Address{
+		hostname, publicIPv6, publicIPv4, cloudLocal,
+	})
+
+	// Public IPv4 addresses are preferred over hostnames and IPv6.
+	public, err := s.unit.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(public, jc.DeepEquals, publicIPv4)
+	private, err := s.unit.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(private, jc.DeepEquals, cloudLocal)
+}
+
+func (s *UnitSuite) TestAddressesPreferHostnameWithoutPublicIPv4(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	hostname := network.NewScopedAddress("unit.example.com", network.ScopePublic)
+	cloudLocal := network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal)
+	err = machine.SetProviderAddresses(cloudLocal, hostname)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A public hostname is preferred over an IPv4 address of the
+	// wrong scope.
+	public, err := s.unit.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(public, jc.DeepEquals, hostname)
+}
+
 func (s *UnitSuite) TestStablePrivateAddress(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -589,6 +645,41 @@ func (s *UnitSuite) TestSetCharmURLSuccess(c *gc.C) {
 	c.Assert(curl, gc.DeepEquals, s.charm.URL())
 }
 
+func (s *UnitSuite) TestSetCharmURLIndependentOfApplication(c *gc.C) {
+	other, err := s.application.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetCharmURL(s.charm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.SetCharmURL(s.charm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Upgrading the application's charm leaves the units' charms alone.
+	newCharm := s.AddConfigCharm(c, "wordpress", "options: {}", 123)
+	err = s.application.SetCharm(state.SetCharmConfig{Charm: newCharm})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, ok := s.unit.CharmURL()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(curl, gc.DeepEquals, s.charm.URL())
+
+	// Each unit records the charm it has converged to independently,
+	// and the record survives a refresh.
+	err = s.unit.SetCharmURL(newCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, ok = s.unit.CharmURL()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(curl, gc.DeepEquals, newCharm.URL())
+
+	err = other.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, ok = other.CharmURL()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(curl, gc.DeepEquals, s.charm.URL())
+}
+
 func (s *UnitSuite) TestSetCharmURLFailures(c *gc.C) {
 	preventUnitDestroyRemove(c, s.unit)
 	curl, ok := s.unit.CharmURL()
@@ -993,6 +1084,13 @@ func (s *UnitSuite) TestSetPassword(c *gc.C) {
 	})
 }
 
+func (s *UnitSuite) TestPasswordChangedTime(c *gc.C) {
+	c.Assert(s.unit.PasswordChangedTime().IsZero(), jc.IsTrue)
+	testPasswordChangedTime(c, s.Clock, func() (passwordChangedTimer, error) {
+		return s.State.Unit(s.unit.Name())
+	})
+}
+
 func (s *UnitSuite) TestUnitSetAgentPresence(c *gc.C) {
 	alive, err := s.unit.AgentPresence()
 	c.Assert(err, jc.ErrorIsNil)
@@ -1726,6 +1824,67 @@ func (s *UnitSuite) TestWatchSubordinates(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *UnitSuite) TestWatchSubordinatesWithPrincipal(c *gc.C) {
+	w := s.unit.WatchSubordinateUnitsWithPrincipal()
+	defer testing.AssertStop(c, w)
+	s.assertSubordinatesChange(c, w, map[string]string{})
+	s.assertNoSubordinatesChange(c, w)
+
+	subCharm := s.AddTestingCharm(c, "logging")
+	var subUnits []*state.Unit
+	for i := 0; i < 2; i++ {
+		name := "logging" + strconv.Itoa(i)
+		subApp := s.AddTestingApplication(c, name, subCharm)
+		eps, err := s.State.InferEndpoints(name, "wordpress")
+		c.Assert(err, jc.ErrorIsNil)
+		rel, err := s.State.AddRelation(eps...)
+		c.Assert(err, jc.ErrorIsNil)
+		ru, err := rel.Unit(s.unit)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ru.EnterScope(nil)
+		c.Assert(err, jc.ErrorIsNil)
+		units, err := subApp.AllUnits()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(units, gc.HasLen, 1)
+		subUnits = append(subUnits, units[0])
+	}
+	s.assertSubordinatesChange(c, w, map[string]string{
+		subUnits[0].Name(): s.unit.Name(),
+		subUnits[1].Name(): s.unit.Name(),
+	})
+	s.assertNoSubordinatesChange(c, w)
+
+	// Removed subordinates are still reported with their principal.
+	err := subUnits[1].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = subUnits[1].Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSubordinatesChange(c, w, map[string]string{
+		subUnits[1].Name(): s.unit.Name(),
+	})
+	s.assertNoSubordinatesChange(c, w)
+}
+
+func (s *UnitSuite) assertSubordinatesChange(c *gc.C, w state.SubordinateUnitsWatcher, expect map[string]string) {
+	s.State.StartSync()
+	select {
+	case changes, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(changes, jc.DeepEquals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("no change")
+	}
+}
+
+func (s *UnitSuite) assertNoSubordinatesChange(c *gc.C, w state.SubordinateUnitsWatcher) {
+	s.State.StartSync()
+	select {
+	case changes, ok := <-w.Changes():
+		c.Fatalf("got unwanted change: %#v, %t", changes, ok)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
 func (s *UnitSuite) TestWatchUnit(c *gc.C) {
 	w := s.unit.Watch()
 	defer testing.AssertStop(c, w)
@@ -1762,6 +1921,118 @@ func (s *UnitSuite) TestWatchUnit(c *gc.C) {
 	testing.NewNotifyWatcherC(c, s.State, w).AssertOneChange()
 }
 
+func (s *UnitSuite) TestWatchAddresses(c *gc.C) {
+	w := s.unit.WatchAddresses()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Assign the unit to a machine with no addresses: not reported.
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Set the machine's addresses: reported.
+	public := network.NewScopedAddress("8.8.8.8", network.ScopePublic)
+	private := network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal)
+	err = machine.SetProviderAddresses(public, private)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Change unrelated unit and machine fields: not reported.
+	err = s.unit.SetPassword("arble-farble-dying-yarble")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetResolved(state.ResolvedNoHooks)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetPassword("arble-farble-dying-yarble")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Setting the same addresses again: not reported.
+	err = machine.SetProviderAddresses(public, private)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Change the private address: reported.
+	err = machine.SetProviderAddresses(public, network.NewScopedAddress("10.0.0.2", network.ScopeCloudLocal))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Stop, check closed.
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *UnitSuite) TestWatchAssignment(c *gc.C) {
+	w := s.unit.WatchAssignment()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Change something unrelated: not reported.
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetPassword("arble-farble-dying-yarble")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Assign the unit: reported.
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Change something unrelated again: not reported.
+	err = unit.SetResolved(state.ResolvedNoHooks)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Unassign the unit: reported.
+	err = unit.UnassignFromMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Stop, check closed.
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *UnitSuite) TestWatchAssignmentSubordinate(c *gc.C) {
+	subUnit := s.addSubordinateUnit(c)
+	w := subUnit.WatchAssignment()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Change something unrelated: not reported.
+	err := subUnit.SetPassword("arble-farble-dying-yarble")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Change the principal manually, as there's no direct way of
+	// doing that otherwise: reported.
+	ops := []txn.Op{{
+		C:      state.UnitsC,
+		Id:     state.DocID(s.State, subUnit.Name()),
+		Update: bson.D{{"$set", bson.D{{"principal", "wordpress/1"}}}},
+	}}
+	err = state.RunTransaction(s.State, ops)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *UnitSuite) TestUnitAgentTools(c *gc.C) {
 	preventUnitDestroyRemove(c, s.unit)
 	testAgentTools(c, s.unit, `unit "wordpress/0"`)
@@ -282,6 +282,9 @@ type userDoc struct {
 	PasswordSalt string    `bson:"passwordsalt"`
 	CreatedBy    string    `bson:"createdby"`
 	DateCreated  time.Time `bson:"datecreated"`
+
+	// PasswordChanged holds when the user's password was last set.
+	PasswordChanged time.Time `bson:"passwordchanged,omitempty"`
 }
 
 type userLastLoginDoc struct {
@@ -419,9 +422,11 @@ func (u *User) SetPasswordHash(pwHash string, pwSalt string) error {
 		// explicit check before login.
 		return errors.Annotate(err, "cannot set password hash")
 	}
+	changed := u.st.nowToTheSecond()
 	update := bson.D{{"$set", bson.D{
 		{"passwordhash", pwHash},
 		{"passwordsalt", pwSalt},
+		{"passwordchanged", changed},
 	}}}
 	if u.doc.SecretKey != nil {
 		update = append(update,
@@ -439,10 +444,17 @@ func (u *User) SetPasswordHash(pwHash string, pwSalt string) error {
 	}
 	u.doc.PasswordHash = pwHash
 	u.doc.PasswordSalt = pwSalt
+	u.doc.PasswordChanged = changed
 	u.doc.SecretKey = nil
 	return nil
 }
 
+// PasswordChangedTime returns when the user's password was last set,
+// or the zero time if it never has been.
+func (u *User) PasswordChangedTime() time.Time {
+	return u.doc.PasswordChanged.UTC()
+}
+
 // PasswordValid returns whether the given password is valid for the User. The
 // caller should call user.Refresh before calling this.
 func (u *User) PasswordValid(password string) bool {
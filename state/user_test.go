@@ -111,6 +111,13 @@ func (s *UserSuite) TestSetPassword(c *gc.C) {
 	})
 }
 
+func (s *UserSuite) TestPasswordChangedTime(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	testPasswordChangedTime(c, s.Clock, func() (passwordChangedTimer, error) {
+		return s.State.User(user.UserTag())
+	})
+}
+
 func (s *UserSuite) TestAddUserSetsSalt(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "a-password"})
 	salt, hash := state.GetUserPasswordSaltAndHash(user)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,7 +22,10 @@ import (
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/presence"
 	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/status"
 
 	// TODO(fwereade): 2015-11-18 lp:1517428
 	//
@@ -83,6 +87,32 @@ type RelationUnitsWatcher interface {
 	Changes() <-chan params.RelationUnitsChange
 }
 
+// RelationDetails describes a relation reported by a
+// RelationDetailsWatcher.
+type RelationDetails struct {
+	Id        int
+	Key       string
+	Endpoints []Endpoint
+	Life      Life
+	Suspended bool
+}
+
+// RelationDetailsWatcher generates signals when relations change their
+// life or suspended status, returning the details of each changed
+// relation so that they need not be read again.
+type RelationDetailsWatcher interface {
+	Watcher
+	Changes() <-chan []RelationDetails
+}
+
+// SubordinateUnitsWatcher generates signals when a unit's subordinate
+// units change, mapping the name of each changed subordinate to the
+// name of its principal so that the principal need not be read again.
+type SubordinateUnitsWatcher interface {
+	Watcher
+	Changes() <-chan map[string]string
+}
+
 // newCommonWatcher exists so that all embedders have a place from which
 // to get a single TxnLogWatcher that will not be replaced in the lifetime
 // of the embedder (and also to restrict the width of the interface by
@@ -389,7 +419,22 @@ func (s *RemoteApplication) WatchRelations() StringsWatcher {
 	return watchApplicationRelations(s.st, s.doc.Name)
 }
 
+// WatchRelationDetails returns a RelationDetailsWatcher that notifies of
+// changes to the lifecycles of relations involving a, along with the
+// key and endpoints of each relation.
+func (a *Application) WatchRelationDetails() RelationDetailsWatcher {
+	members, filter := applicationRelationsMembersFilter(a.st, a.doc.Name)
+	return newRelationDetailsWatcher(a.st, members, filter)
+}
+
 func watchApplicationRelations(backend modelBackend, applicationName string) StringsWatcher {
+	members, filter := applicationRelationsMembersFilter(backend, applicationName)
+	return newRelationLifeSuspendedWatcher(backend, members, filter, nil)
+}
+
+// applicationRelationsMembersFilter returns the members query and the
+// change filter selecting relations involving the named application.
+func applicationRelationsMembersFilter(backend modelBackend, applicationName string) (bson.D, func(interface{}) bool) {
 	prefix := applicationName + ":"
 	infix := " " + prefix
 	filter := func(id interface{}) bool {
@@ -400,24 +445,186 @@ func watchApplicationRelations(backend modelBackend, applicationName string) Str
 		return strings.HasPrefix(k, prefix) || strings.Contains(k, infix)
 	}
 	members := bson.D{{"endpoints.applicationname", applicationName}}
-	return newRelationLifeSuspendedWatcher(backend, members, filter, nil)
+	return members, filter
 }
 
 // WatchModelMachines returns a StringsWatcher that notifies of changes to
 // the lifecycles of the machines (but not containers) in the model.
 func (st *State) WatchModelMachines() StringsWatcher {
-	members := bson.D{{"$or", []bson.D{
-		{{"containertype", ""}},
-		{{"containertype", bson.D{{"$exists", false}}}},
-	}}}
-	filter := func(id interface{}) bool {
-		k, err := st.strictLocalID(id.(string))
-		if err != nil {
-			return false
+	return newLifecycleWatcher(st, machinesC, modelMachinesMembers, st.isModelMachineID, nil)
+}
+
+// modelMachinesMembers selects the top-level machines in a model.
+var modelMachinesMembers = bson.D{{"$or", []bson.D{
+	{{"containertype", ""}},
+	{{"containertype", bson.D{{"$exists", false}}}},
+}}}
+
+// isModelMachineID reports whether the given machines document id is that
+// of a top-level machine in the model.
+func (st *State) isModelMachineID(id interface{}) bool {
+	k, err := st.strictLocalID(id.(string))
+	if err != nil {
+		return false
+	}
+	return !strings.Contains(k, "/")
+}
+
+// WatchHostMachines returns a StringsWatcher that notifies of changes to
+// the lifecycles of the machines (but not containers) in the model that
+// can host units. A machine that gains or loses JobHostUnits is reported
+// as if it had been added or removed.
+func (st *State) WatchHostMachines() StringsWatcher {
+	w := &hostMachinesWatcher{
+		commonWatcher: newCommonWatcher(st),
+		life:          make(map[string]Life),
+		out:           make(chan []string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// hostMachinesWatcher notifies of changes to the lifecycles of the
+// machines that can host units.
+type hostMachinesWatcher struct {
+	commonWatcher
+	out chan []string
+
+	// life holds the most recent known life states of the machines
+	// that can host units.
+	life map[string]Life
+}
+
+var _ Watcher = (*hostMachinesWatcher)(nil)
+
+type hostMachineDoc struct {
+	Id   string       `bson:"_id"`
+	Life Life         `bson:"life"`
+	Jobs []MachineJob `bson:"jobs"`
+}
+
+var hostMachineFields = bson.D{{"_id", 1}, {"life", 1}, {"jobs", 1}}
+
+// Changes returns the event channel for w.
+func (w *hostMachinesWatcher) Changes() <-chan []string {
+	return w.out
+}
+
+// filter reports whether the document id is that of a machine rather
+// than a container.
+func (w *hostMachinesWatcher) filter(id interface{}) bool {
+	k, err := w.backend.strictLocalID(id.(string))
+	if err != nil {
+		return false
+	}
+	return !strings.Contains(k, "/")
+}
+
+func (w *hostMachinesWatcher) initial() (set.Strings, error) {
+	machines, closer := w.db.GetCollection(machinesC)
+	defer closer()
+
+	ids := make(set.Strings)
+	var doc hostMachineDoc
+	iter := machines.Find(bson.D{
+		{"$or", []bson.D{
+			{{"containertype", ""}},
+			{{"containertype", bson.D{{"$exists", false}}}},
+		}},
+		{"jobs", JobHostUnits},
+	}).Select(hostMachineFields).Iter()
+	for iter.Next(&doc) {
+		id := w.backend.localID(doc.Id)
+		ids.Add(id)
+		if doc.Life != Dead {
+			w.life[id] = doc.Life
+		}
+	}
+	return ids, iter.Close()
+}
+
+func (w *hostMachinesWatcher) merge(ids set.Strings, updates map[interface{}]bool) error {
+	machines, closer := w.db.GetCollection(machinesC)
+	defer closer()
+
+	// Machines that have been removed, or that can no longer host
+	// units, are treated as dead.
+	var changed []string
+	latest := make(map[string]Life)
+	for id, exists := range updates {
+		docID, ok := id.(string)
+		if !ok {
+			return errors.Errorf("id is not of type string, got %T", id)
+		}
+		latest[w.backend.localID(docID)] = Dead
+		if exists {
+			changed = append(changed, docID)
+		}
+	}
+	iter := machines.Find(bson.D{{"_id", bson.D{{"$in", changed}}}}).Select(hostMachineFields).Iter()
+	var doc hostMachineDoc
+	for iter.Next(&doc) {
+		if hasJob(doc.Jobs, JobHostUnits) {
+			latest[w.backend.localID(doc.Id)] = doc.Life
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	for id, newLife := range latest {
+		gone := newLife == Dead
+		oldLife, known := w.life[id]
+		switch {
+		case known && gone:
+			delete(w.life, id)
+		case !known && !gone:
+			w.life[id] = newLife
+		case known && newLife != oldLife:
+			w.life[id] = newLife
+		default:
+			continue
+		}
+		ids.Add(id)
+	}
+	return nil
+}
+
+func (w *hostMachinesWatcher) loop() error {
+	in := make(chan watcher.Change)
+	w.watcher.WatchCollectionWithFilter(machinesC, in, w.filter)
+	defer w.watcher.UnwatchCollection(machinesC, in)
+	ids, err := w.initial()
+	if err != nil {
+		return err
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case ch := <-in:
+			updates, ok := collect(ch, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			if err := w.merge(ids, updates); err != nil {
+				return err
+			}
+			if !ids.IsEmpty() {
+				out = w.out
+			}
+		case out <- ids.Values():
+			ids = make(set.Strings)
+			out = nil
 		}
-		return !strings.Contains(k, "/")
 	}
-	return newLifecycleWatcher(st, machinesC, members, filter, nil)
 }
 
 // WatchContainers returns a StringsWatcher that notifies of changes to the
@@ -575,7 +782,21 @@ func stateWatcherDeadError(err error) error {
 	return ErrStateClosed
 }
 
+// checkWatchedCollection returns an error satisfying
+// IsUnknownCollectionError if the named collection is not part of the
+// database's schema. Watchers call it before they start, so that they
+// fail clearly rather than silently watching nothing.
+func checkWatchedCollection(db Database, collName string) error {
+	if _, ok := db.Schema()[collName]; !ok {
+		return &ErrUnknownCollection{name: collName}
+	}
+	return nil
+}
+
 func (w *lifecycleWatcher) loop() error {
+	if err := checkWatchedCollection(w.backend.db(), w.collName); err != nil {
+		return errors.Trace(err)
+	}
 	in := make(chan watcher.Change)
 	w.watcher.WatchCollectionWithFilter(w.collName, in, w.filter)
 	defer w.watcher.UnwatchCollection(w.collName, in)
@@ -614,24 +835,28 @@ func (w *lifecycleWatcher) loop() error {
 	}
 }
 
-// minUnitsWatcher notifies about MinUnits changes of the services requiring
-// a minimum number of units to be alive. The first event returned by the
-// watcher is the set of application names requiring a minimum number of units.
-// Subsequent events are generated when a service increases MinUnits, or when
-// one or more units belonging to a service are destroyed.
-type minUnitsWatcher struct {
+// EntitiesWatcher notifies of lifecycle changes to the machines,
+// applications and units of a model as a single stream of entity tags.
+//
+// The first event holds the tags of all machines, applications and units
+// not yet Dead; it is only sent once the initial state of all three kinds
+// of entity is known. Subsequent events hold the tags of entities that
+// were added or changed their life since the previous event.
+//
+// Each event holds each tag at most once. Machine tags are always ordered
+// before application tags, which are ordered before unit tags; tags of the
+// same kind are sorted by id.
+type EntitiesWatcher struct {
 	commonWatcher
-	known map[string]int
-	out   chan []string
+	out chan []params.Entity
 }
 
-var _ Watcher = (*minUnitsWatcher)(nil)
-
-func newMinUnitsWatcher(backend modelBackend) StringsWatcher {
-	w := &minUnitsWatcher{
-		commonWatcher: newCommonWatcher(backend),
-		known:         make(map[string]int),
-		out:           make(chan []string),
+// WatchModelEntities returns an EntitiesWatcher that notifies of changes
+// to the lifecycles of all machines, applications and units in the model.
+func (st *State) WatchModelEntities() *EntitiesWatcher {
+	w := &EntitiesWatcher{
+		commonWatcher: newCommonWatcher(st),
+		out:           make(chan []params.Entity),
 	}
 	go func() {
 		defer w.tomb.Done()
@@ -641,114 +866,500 @@ func newMinUnitsWatcher(backend modelBackend) StringsWatcher {
 	return w
 }
 
-// WatchMinUnits returns a StringsWatcher for the minUnits collection
-func (st *State) WatchMinUnits() StringsWatcher {
-	return newMinUnitsWatcher(st)
+// Changes returns the event channel for the EntitiesWatcher.
+func (w *EntitiesWatcher) Changes() <-chan []params.Entity {
+	return w.out
 }
 
-func (w *minUnitsWatcher) initial() (set.Strings, error) {
-	applicationnames := make(set.Strings)
-	var doc minUnitsDoc
-	newMinUnits, closer := w.db.GetCollection(minUnitsC)
-	defer closer()
+// entityChanges accumulates the ids of changed entities by kind.
+type entityChanges struct {
+	machines     set.Strings
+	applications set.Strings
+	units        set.Strings
+}
 
-	iter := newMinUnits.Find(nil).Iter()
-	for iter.Next(&doc) {
-		w.known[doc.ApplicationName] = doc.Revno
-		applicationnames.Add(doc.ApplicationName)
+func newEntityChanges() *entityChanges {
+	return &entityChanges{
+		machines:     make(set.Strings),
+		applications: make(set.Strings),
+		units:        make(set.Strings),
 	}
-	return applicationnames, iter.Close()
 }
 
-func (w *minUnitsWatcher) merge(applicationnames set.Strings, change watcher.Change) error {
-	applicationname := w.backend.localID(change.Id.(string))
-	if change.Revno == -1 {
-		delete(w.known, applicationname)
-		applicationnames.Remove(applicationname)
-		return nil
+func (c *entityChanges) isEmpty() bool {
+	return c.machines.IsEmpty() && c.applications.IsEmpty() && c.units.IsEmpty()
+}
+
+// entities returns the accumulated changes as entity tags, in the
+// order documented on EntitiesWatcher.
+func (c *entityChanges) entities() []params.Entity {
+	entities := make([]params.Entity, 0, len(c.machines)+len(c.applications)+len(c.units))
+	for _, id := range c.machines.SortedValues() {
+		entities = append(entities, params.Entity{Tag: names.NewMachineTag(id).String()})
 	}
-	doc := minUnitsDoc{}
-	newMinUnits, closer := w.db.GetCollection(minUnitsC)
-	defer closer()
-	if err := newMinUnits.FindId(change.Id).One(&doc); err != nil {
-		return err
+	for _, id := range c.applications.SortedValues() {
+		entities = append(entities, params.Entity{Tag: names.NewApplicationTag(id).String()})
 	}
-	revno, known := w.known[applicationname]
-	w.known[applicationname] = doc.Revno
-	if !known || doc.Revno > revno {
-		applicationnames.Add(applicationname)
+	for _, id := range c.units.SortedValues() {
+		entities = append(entities, params.Entity{Tag: names.NewUnitTag(id).String()})
 	}
-	return nil
+	return entities
 }
 
-func (w *minUnitsWatcher) loop() (err error) {
-	ch := make(chan watcher.Change)
-	w.watcher.WatchCollectionWithFilter(minUnitsC, ch, isLocalID(w.backend))
-	defer w.watcher.UnwatchCollection(minUnitsC, ch)
-	applicationnames, err := w.initial()
-	if err != nil {
-		return err
-	}
-	out := w.out
+func (w *EntitiesWatcher) loop() error {
+	machines := newLifecycleWatcher(w.backend, machinesC, nil, isLocalID(w.backend), nil)
+	defer watcher.Stop(machines, &w.tomb)
+	applications := newLifecycleWatcher(w.backend, applicationsC, nil, isLocalID(w.backend), nil)
+	defer watcher.Stop(applications, &w.tomb)
+	units := newLifecycleWatcher(w.backend, unitsC, nil, isLocalID(w.backend), nil)
+	defer watcher.Stop(units, &w.tomb)
+
+	// The initial event is held back until all of the underlying
+	// watchers have delivered their own initial events.
+	waiting := set.NewStrings(names.MachineTagKind, names.ApplicationTagKind, names.UnitTagKind)
+	changes := newEntityChanges()
+	sentInitial := false
+	var out chan<- []params.Entity
 	for {
 		select {
 		case <-w.tomb.Dying():
 			return tomb.ErrDying
-		case <-w.watcher.Dead():
-			return stateWatcherDeadError(w.watcher.Err())
-		case change := <-ch:
-			if err = w.merge(applicationnames, change); err != nil {
-				return err
+		case ids, ok := <-machines.Changes():
+			if !ok {
+				return watcher.EnsureErr(machines)
 			}
-			if !applicationnames.IsEmpty() {
-				out = w.out
+			changes.machines = changes.machines.Union(set.NewStrings(ids...))
+			waiting.Remove(names.MachineTagKind)
+		case ids, ok := <-applications.Changes():
+			if !ok {
+				return watcher.EnsureErr(applications)
 			}
-		case out <- applicationnames.Values():
+			changes.applications = changes.applications.Union(set.NewStrings(ids...))
+			waiting.Remove(names.ApplicationTagKind)
+		case ids, ok := <-units.Changes():
+			if !ok {
+				return watcher.EnsureErr(units)
+			}
+			changes.units = changes.units.Union(set.NewStrings(ids...))
+			waiting.Remove(names.UnitTagKind)
+		case out <- changes.entities():
+			changes = newEntityChanges()
+			sentInitial = true
 			out = nil
-			applicationnames = set.NewStrings()
+			continue
+		}
+		if waiting.IsEmpty() && (!sentInitial || !changes.isEmpty()) {
+			out = w.out
 		}
 	}
 }
 
-func (w *minUnitsWatcher) Changes() <-chan []string {
-	return w.out
+// MachinesChange classifies the machine ids reported by a
+// MachinesDetailedWatcher.
+type MachinesChange struct {
+	Added   []string
+	Changed []string
+	Dead    []string
+	Removed []string
 }
 
-// scopeInfo holds a RelationScopeWatcher's last-delivered state, and any
-// known but undelivered changes thereto.
-type scopeInfo struct {
-	base map[string]bool
-	diff map[string]bool
+// MachinesDetailedWatcher notifies of model machines being added,
+// changing life, becoming Dead, or being removed, so callers need not
+// query state to tell which kind of change each machine id represents.
+// It watches the same machines as WatchModelMachines, and classifies
+// them using the life states cached by the underlying lifecycleWatcher.
+type MachinesDetailedWatcher struct {
+	lifecycleWatcher
+	// dead holds the ids of machines reported Dead but not yet removed.
+	dead set.Strings
+	out  chan MachinesChange
 }
 
-func (info *scopeInfo) add(name string) {
-	if info.base[name] {
-		delete(info.diff, name)
-	} else {
-		info.diff[name] = true
-	}
-}
+// machineChangeKind records how a machine changed since the previous
+// event delivered by a MachinesDetailedWatcher.
+type machineChangeKind int
 
-func (info *scopeInfo) remove(name string) {
-	if info.base[name] {
-		info.diff[name] = false
-	} else {
-		delete(info.diff, name)
+const (
+	machineAdded machineChangeKind = iota
+	machineChanged
+	machineDead
+	machineRemoved
+)
+
+// WatchMachinesDetailed returns a MachinesDetailedWatcher for the
+// top-level machines in the model. The first event reports every
+// machine as Added, or as Dead if it is already Dead.
+func (st *State) WatchMachinesDetailed() *MachinesDetailedWatcher {
+	w := &MachinesDetailedWatcher{
+		lifecycleWatcher: lifecycleWatcher{
+			commonWatcher: newCommonWatcher(st),
+			coll:          collFactory(st.db(), machinesC),
+			collName:      machinesC,
+			members:       modelMachinesMembers,
+			filter:        st.isModelMachineID,
+			life:          make(map[string]Life),
+		},
+		dead: make(set.Strings),
+		out:  make(chan MachinesChange),
 	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
 }
 
-func (info *scopeInfo) commit() {
-	for name, change := range info.diff {
-		if change {
-			info.base[name] = true
-		} else {
-			delete(info.base, name)
-		}
-	}
-	info.diff = map[string]bool{}
+// Changes returns the event channel for the MachinesDetailedWatcher.
+func (w *MachinesDetailedWatcher) Changes() <-chan MachinesChange {
+	return w.out
 }
 
-func (info *scopeInfo) hasChanges() bool {
+func (w *MachinesDetailedWatcher) loop() error {
+	if err := checkWatchedCollection(w.backend.db(), w.collName); err != nil {
+		return errors.Trace(err)
+	}
+	in := make(chan watcher.Change)
+	w.watcher.WatchCollectionWithFilter(w.collName, in, w.filter)
+	defer w.watcher.UnwatchCollection(w.collName, in)
+	ids, err := w.initial()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	pending := make(map[string]machineChangeKind)
+	for _, id := range ids.Values() {
+		if _, ok := w.life[id]; ok {
+			pending[id] = machineAdded
+		} else {
+			pending[id] = machineDead
+			w.dead.Add(id)
+		}
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case ch := <-in:
+			updates, ok := collect(ch, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			if err := w.classify(pending, updates); err != nil {
+				return errors.Trace(err)
+			}
+			if len(pending) > 0 {
+				out = w.out
+			}
+		case out <- machinesChange(pending):
+			pending = make(map[string]machineChangeKind)
+			out = nil
+		}
+	}
+}
+
+// classify merges updates into the life cache, and records in pending
+// how each machine whose life changed, or which was removed, differs
+// from what was last reported.
+func (w *MachinesDetailedWatcher) classify(pending map[string]machineChangeKind, updates map[interface{}]bool) error {
+	// Note which machines were known to be alive or dying, and which
+	// were removed, before merge updates the cache.
+	known := make(set.Strings)
+	removed := make(set.Strings)
+	for key, exists := range updates {
+		docID, ok := key.(string)
+		if !ok {
+			return errors.Errorf("id is not of type string, got %T", key)
+		}
+		id := w.backend.localID(docID)
+		if _, ok := w.life[id]; ok {
+			known.Add(id)
+		}
+		if !exists {
+			removed.Add(id)
+		}
+	}
+	ids := make(set.Strings)
+	if err := w.merge(ids, updates); err != nil {
+		return errors.Trace(err)
+	}
+	for _, id := range ids.Values() {
+		_, alive := w.life[id]
+		switch {
+		case alive && known.Contains(id):
+			addMachineChange(pending, id, machineChanged)
+		case alive:
+			addMachineChange(pending, id, machineAdded)
+		case removed.Contains(id):
+			addMachineChange(pending, id, machineRemoved)
+		default:
+			addMachineChange(pending, id, machineDead)
+			w.dead.Add(id)
+		}
+	}
+	// The life cache forgets machines once they are Dead, so removal
+	// of a machine already reported Dead is noticed here.
+	for _, id := range removed.Values() {
+		if w.dead.Contains(id) {
+			w.dead.Remove(id)
+			addMachineChange(pending, id, machineRemoved)
+		}
+	}
+	return nil
+}
+
+// addMachineChange records in pending that the machine with the given
+// id changed in the given way, taking account of any change already
+// pending for it.
+func addMachineChange(pending map[string]machineChangeKind, id string, kind machineChangeKind) {
+	previous, ok := pending[id]
+	switch {
+	case !ok:
+		pending[id] = kind
+	case previous == machineAdded && kind == machineRemoved:
+		// Added and removed before the consumer heard of it.
+		delete(pending, id)
+	case previous == machineAdded && kind == machineChanged:
+		// Still new to the consumer.
+	default:
+		pending[id] = kind
+	}
+}
+
+// machinesChange returns the MachinesChange describing pending, with
+// the ids of each kind sorted.
+func machinesChange(pending map[string]machineChangeKind) MachinesChange {
+	added, changed := make(set.Strings), make(set.Strings)
+	dead, removed := make(set.Strings), make(set.Strings)
+	for id, kind := range pending {
+		switch kind {
+		case machineAdded:
+			added.Add(id)
+		case machineChanged:
+			changed.Add(id)
+		case machineDead:
+			dead.Add(id)
+		case machineRemoved:
+			removed.Add(id)
+		}
+	}
+	return MachinesChange{
+		Added:   added.SortedValues(),
+		Changed: changed.SortedValues(),
+		Dead:    dead.SortedValues(),
+		Removed: removed.SortedValues(),
+	}
+}
+
+// minUnitsWatcher notifies about MinUnits changes of the services requiring
+// a minimum number of units to be alive. The first event returned by the
+// watcher is the set of application names requiring a minimum number of units.
+// Subsequent events are generated when a service increases MinUnits, or when
+// one or more units belonging to a service are destroyed.
+type minUnitsWatcher struct {
+	commonWatcher
+	known map[string]int
+	out   chan []string
+}
+
+var _ Watcher = (*minUnitsWatcher)(nil)
+
+func newMinUnitsWatcher(backend modelBackend) StringsWatcher {
+	w := &minUnitsWatcher{
+		commonWatcher: newCommonWatcher(backend),
+		known:         make(map[string]int),
+		out:           make(chan []string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// WatchMinUnits returns a StringsWatcher for the minUnits collection
+func (st *State) WatchMinUnits() StringsWatcher {
+	return newMinUnitsWatcher(st)
+}
+
+func (w *minUnitsWatcher) initial() (set.Strings, error) {
+	applicationnames := make(set.Strings)
+	var doc minUnitsDoc
+	newMinUnits, closer := w.db.GetCollection(minUnitsC)
+	defer closer()
+
+	iter := newMinUnits.Find(nil).Iter()
+	for iter.Next(&doc) {
+		w.known[doc.ApplicationName] = doc.Revno
+		applicationnames.Add(doc.ApplicationName)
+	}
+	return applicationnames, iter.Close()
+}
+
+func (w *minUnitsWatcher) merge(applicationnames set.Strings, change watcher.Change) error {
+	applicationname := w.backend.localID(change.Id.(string))
+	if change.Revno == -1 {
+		delete(w.known, applicationname)
+		applicationnames.Remove(applicationname)
+		return nil
+	}
+	doc := minUnitsDoc{}
+	newMinUnits, closer := w.db.GetCollection(minUnitsC)
+	defer closer()
+	if err := newMinUnits.FindId(change.Id).One(&doc); err != nil {
+		return err
+	}
+	revno, known := w.known[applicationname]
+	w.known[applicationname] = doc.Revno
+	if !known || doc.Revno > revno {
+		applicationnames.Add(applicationname)
+	}
+	return nil
+}
+
+func (w *minUnitsWatcher) loop() (err error) {
+	ch := make(chan watcher.Change)
+	w.watcher.WatchCollectionWithFilter(minUnitsC, ch, isLocalID(w.backend))
+	defer w.watcher.UnwatchCollection(minUnitsC, ch)
+	applicationnames, err := w.initial()
+	if err != nil {
+		return err
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case change := <-ch:
+			if err = w.merge(applicationnames, change); err != nil {
+				return err
+			}
+			if !applicationnames.IsEmpty() {
+				out = w.out
+			}
+		case out <- applicationnames.Values():
+			out = nil
+			applicationnames = set.NewStrings()
+		}
+	}
+}
+
+func (w *minUnitsWatcher) Changes() <-chan []string {
+	return w.out
+}
+
+// applicationMinUnitsWatcher notifies about changes to the minimum
+// units of a single application.
+//
+// The first event is emitted immediately. From then on, a new event is
+// emitted only when the application's MinUnits value changes; other
+// changes to the application document are ignored.
+type applicationMinUnitsWatcher struct {
+	commonWatcher
+	application *Application
+	out         chan struct{}
+}
+
+var _ Watcher = (*applicationMinUnitsWatcher)(nil)
+
+// WatchMinUnits returns a NotifyWatcher that notifies when the minimum
+// units of the application change.
+func (a *Application) WatchMinUnits() NotifyWatcher {
+	w := &applicationMinUnitsWatcher{
+		commonWatcher: newCommonWatcher(a.st),
+		out:           make(chan struct{}),
+		application:   &Application{st: a.st, doc: a.doc}, // Copy so it may be freely refreshed
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *applicationMinUnitsWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *applicationMinUnitsWatcher) loop() error {
+	applications, closer := w.db.GetCollection(applicationsC)
+	revno, err := getTxnRevno(applications, w.application.doc.DocID)
+	closer()
+	if err != nil {
+		return err
+	}
+	applicationCh := make(chan watcher.Change)
+	w.watcher.Watch(applicationsC, w.application.doc.DocID, revno, applicationCh)
+	defer w.watcher.Unwatch(applicationsC, w.application.doc.DocID, applicationCh)
+	// The application's copy of the document may be older than the
+	// revno we are watching from, so read the current value now.
+	if err := w.application.Refresh(); err != nil {
+		return err
+	}
+	minUnits := w.application.MinUnits()
+	out := w.out
+	for {
+		select {
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-applicationCh:
+			if err := w.application.Refresh(); err != nil {
+				return err
+			}
+			if newMinUnits := w.application.MinUnits(); newMinUnits != minUnits {
+				minUnits = newMinUnits
+				out = w.out
+			}
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// scopeInfo holds a RelationScopeWatcher's last-delivered state, and any
+// known but undelivered changes thereto.
+type scopeInfo struct {
+	base map[string]bool
+	diff map[string]bool
+}
+
+func (info *scopeInfo) add(name string) {
+	if info.base[name] {
+		delete(info.diff, name)
+	} else {
+		info.diff[name] = true
+	}
+}
+
+func (info *scopeInfo) remove(name string) {
+	if info.base[name] {
+		info.diff[name] = false
+	} else {
+		delete(info.diff, name)
+	}
+}
+
+func (info *scopeInfo) commit() {
+	for name, change := range info.diff {
+		if change {
+			info.base[name] = true
+		} else {
+			delete(info.base, name)
+		}
+	}
+	info.diff = map[string]bool{}
+}
+
+func (info *scopeInfo) hasChanges() bool {
 	return len(info.diff) > 0
 }
 
@@ -920,6 +1531,19 @@ type relationUnitsWatcher struct {
 	watching set.Strings
 	updates  chan watcher.Change
 	out      chan params.RelationUnitsChange
+
+	// diffKeys, if true, causes the watcher to report the settings keys
+	// changed since the last event for each unit in ChangedKeys.
+	diffKeys bool
+
+	// snapshots holds the last seen settings of each unit in scope,
+	// keyed on unit name. It is only maintained when diffKeys is true.
+	snapshots map[string]map[string]interface{}
+
+	// counterpart, if not empty, restricts the watcher to the unit
+	// with that name; other units entering and leaving scope are
+	// ignored.
+	counterpart string
 }
 
 // Watch returns a watcher that notifies of changes to conterpart units in
@@ -928,6 +1552,23 @@ func (ru *RelationUnit) Watch() RelationUnitsWatcher {
 	return newRelationUnitsWatcher(ru.st, ru.WatchScope())
 }
 
+// WatchWithChangedKeys returns a watcher that notifies of changes to
+// counterpart units in the relation, like Watch, but additionally reports
+// the settings keys that were added, modified or removed for each changed
+// unit in the ChangedKeys field of each event.
+func (ru *RelationUnit) WatchWithChangedKeys() RelationUnitsWatcher {
+	return startRelationUnitsWatcher(ru.st, ru.WatchScope(), true, "")
+}
+
+// WatchCounterpart returns a watcher that notifies of the named
+// counterpart unit entering and leaving the relation scope, and of
+// changes to its settings, like Watch. Other counterpart units are
+// ignored, so the initial event includes the named unit only if it is
+// already in scope.
+func (ru *RelationUnit) WatchCounterpart(unitName string) RelationUnitsWatcher {
+	return startRelationUnitsWatcher(ru.st, ru.WatchScope(), false, unitName)
+}
+
 // WatchUnits returns a watcher that notifies of changes to the units of the
 // specified application endpoint in the relation. This method will return an error
 // if the endpoint is not globally scoped.
@@ -952,12 +1593,21 @@ func (r *Relation) watchUnits(applicationName string, counterpart bool) (Relatio
 }
 
 func newRelationUnitsWatcher(backend modelBackend, sw *RelationScopeWatcher) RelationUnitsWatcher {
+	return startRelationUnitsWatcher(backend, sw, false, "")
+}
+
+func startRelationUnitsWatcher(
+	backend modelBackend, sw *RelationScopeWatcher, diffKeys bool, counterpart string,
+) RelationUnitsWatcher {
 	w := &relationUnitsWatcher{
 		commonWatcher: newCommonWatcher(backend),
 		sw:            sw,
 		watching:      make(set.Strings),
 		updates:       make(chan watcher.Change),
 		out:           make(chan params.RelationUnitsChange),
+		diffKeys:      diffKeys,
+		snapshots:     make(map[string]map[string]interface{}),
+		counterpart:   counterpart,
 	}
 	go func() {
 		defer w.finish()
@@ -987,49 +1637,150 @@ func setRelationUnitChangeVersion(changes *params.RelationUnitsChange, key strin
 	changes.Changed[name] = settings
 }
 
+// relationUnitSettingsDoc holds the fields of a relation settings node
+// that a relationUnitsWatcher reports on.
+type relationUnitSettingsDoc struct {
+	DocID    string      `bson:"_id"`
+	TxnRevno int64       `bson:"txn-revno"`
+	Version  int64       `bson:"version"`
+	Settings settingsMap `bson:"settings"`
+}
+
 // mergeSettings reads the relation settings node for the unit with the
 // supplied key, and sets a value in the Changed field keyed on the unit's
-// name. It returns the mgo/txn revision number of the settings node.
+// name. If the watcher is diffing keys, the keys changed since the last
+// snapshot of the unit's settings are also merged into the ChangedKeys
+// field. It returns the mgo/txn revision number of the settings node.
 func (w *relationUnitsWatcher) mergeSettings(changes *params.RelationUnitsChange, key string) (int64, error) {
-	var doc struct {
-		TxnRevno int64 `bson:"txn-revno"`
-		Version  int64 `bson:"version"`
-	}
+	var doc relationUnitSettingsDoc
 	if err := readSettingsDocInto(w.backend.db(), settingsC, key, &doc); err != nil {
 		return -1, err
 	}
+	return w.mergeSettingsDoc(changes, key, &doc), nil
+}
+
+// mergeSettingsDoc merges the supplied settings node for the unit with
+// the supplied key as mergeSettings does, and returns its mgo/txn
+// revision number.
+func (w *relationUnitsWatcher) mergeSettingsDoc(changes *params.RelationUnitsChange, key string, doc *relationUnitSettingsDoc) int64 {
 	setRelationUnitChangeVersion(changes, key, doc.Version)
-	return doc.TxnRevno, nil
+	if w.diffKeys {
+		w.mergeChangedKeys(changes, unitNameFromScopeKey(key), doc.Settings)
+	}
+	return doc.TxnRevno
 }
 
-// mergeScope starts and stops settings watches on the units entering and
-// leaving the scope in the supplied RelationScopeChange event, and applies
-// the expressed changes to the supplied RelationUnitsChange event.
-func (w *relationUnitsWatcher) mergeScope(changes *params.RelationUnitsChange, c *RelationScopeChange) error {
+// readSettingsDocs reads the relation settings nodes with the supplied
+// keys in a single query, and returns them by key. It returns an error
+// satisfying errors.IsNotFound if any of them does not exist.
+func (w *relationUnitsWatcher) readSettingsDocs(keys []string) (map[string]*relationUnitSettingsDoc, error) {
+	result := make(map[string]*relationUnitSettingsDoc, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+	docIDs := make([]string, len(keys))
+	for i, key := range keys {
+		docIDs[i] = w.backend.docID(key)
+	}
+	settings, closer := w.db.GetCollection(settingsC)
+	defer closer()
+
+	var docs []relationUnitSettingsDoc
+	query := settings.Find(bson.D{{"_id", bson.D{{"$in", docIDs}}}})
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read settings")
+	}
+	for i := range docs {
+		result[w.backend.localID(docs[i].DocID)] = &docs[i]
+	}
+	for _, key := range keys {
+		if _, ok := result[key]; !ok {
+			return nil, errors.NotFoundf("settings")
+		}
+	}
+	return result, nil
+}
+
+// mergeChangedKeys compares the supplied settings with the last snapshot
+// recorded for the named unit, adds any added, modified or removed keys to
+// the ChangedKeys field of changes, and records the settings as the unit's
+// new snapshot.
+func (w *relationUnitsWatcher) mergeChangedKeys(changes *params.RelationUnitsChange, name string, settings map[string]interface{}) {
+	previous := w.snapshots[name]
+	w.snapshots[name] = copyMap(settings, nil)
+	// Keys reported for this unit but not yet sent must not be lost.
+	changed := set.NewStrings(changes.ChangedKeys[name]...)
+	for key, value := range settings {
+		if old, ok := previous[key]; !ok || !reflect.DeepEqual(old, value) {
+			changed.Add(key)
+		}
+	}
+	for key := range previous {
+		if _, ok := settings[key]; !ok {
+			changed.Add(key)
+		}
+	}
+	if changed.IsEmpty() {
+		return
+	}
+	if changes.ChangedKeys == nil {
+		changes.ChangedKeys = make(map[string][]string)
+	}
+	changes.ChangedKeys[name] = changed.SortedValues()
+}
+
+// mergeScope starts and stops settings watches on the units entering and
+// leaving the scope in the supplied RelationScopeChange event, and applies
+// the expressed changes to the supplied RelationUnitsChange event.
+func (w *relationUnitsWatcher) mergeScope(changes *params.RelationUnitsChange, c *RelationScopeChange) error {
+	var entered []string
 	for _, name := range c.Entered {
-		key := w.sw.prefix + name
-		docID := w.backend.docID(key)
-		revno, err := w.mergeSettings(changes, key)
-		if err != nil {
-			return err
+		if w.interestedIn(name) {
+			entered = append(entered, name)
 		}
+	}
+	keys := make([]string, len(entered))
+	for i, name := range entered {
+		keys[i] = w.sw.prefix + name
+	}
+	docs, err := w.readSettingsDocs(keys)
+	if err != nil {
+		return err
+	}
+	for i, name := range entered {
+		key := keys[i]
+		docID := w.backend.docID(key)
+		revno := w.mergeSettingsDoc(changes, key, docs[key])
 		changes.Departed = remove(changes.Departed, name)
 		w.watcher.Watch(settingsC, docID, revno, w.updates)
 		w.watching.Add(docID)
 	}
 	for _, name := range c.Left {
+		if !w.interestedIn(name) {
+			continue
+		}
 		key := w.sw.prefix + name
 		docID := w.backend.docID(key)
 		changes.Departed = append(changes.Departed, name)
 		if changes.Changed != nil {
 			delete(changes.Changed, name)
 		}
+		if changes.ChangedKeys != nil {
+			delete(changes.ChangedKeys, name)
+		}
+		delete(w.snapshots, name)
 		w.watcher.Unwatch(settingsC, docID, w.updates)
 		w.watching.Remove(docID)
 	}
 	return nil
 }
 
+// interestedIn reports whether the watcher reports changes to the
+// named unit.
+func (w *relationUnitsWatcher) interestedIn(name string) bool {
+	return w.counterpart == "" || name == w.counterpart
+}
+
 // remove removes s from strs and returns the modified slice.
 func remove(strs []string, s string) []string {
 	for i, v := range strs {
@@ -1092,6 +1843,123 @@ func (w *relationUnitsWatcher) loop() (err error) {
 	}
 }
 
+var _ Watcher = (*RelationScopeSettingsWatcher)(nil)
+
+// RelationScopeSettingsChange contains information about units that have
+// entered or left a particular scope. Entered holds the settings of each
+// entering unit, keyed on unit name, as they were when the unit entered.
+type RelationScopeSettingsChange struct {
+	Entered map[string]map[string]interface{}
+	Left    []string
+}
+
+// RelationScopeSettingsWatcher observes changes to the set of units in a
+// particular relation scope, like RelationScopeWatcher, and includes the
+// settings of units entering the scope. Later changes to those settings
+// are not reported.
+type RelationScopeSettingsWatcher struct {
+	commonWatcher
+	sw  *RelationScopeWatcher
+	out chan *RelationScopeSettingsChange
+}
+
+// WatchScopeWithSettings returns a watcher which notifies of counterpart
+// units entering and leaving the unit's scope, with the settings of each
+// entering unit.
+func (ru *RelationUnit) WatchScopeWithSettings() *RelationScopeSettingsWatcher {
+	return newRelationScopeSettingsWatcher(ru.st, ru.WatchScope())
+}
+
+func newRelationScopeSettingsWatcher(backend modelBackend, sw *RelationScopeWatcher) *RelationScopeSettingsWatcher {
+	w := &RelationScopeSettingsWatcher{
+		commonWatcher: newCommonWatcher(backend),
+		sw:            sw,
+		out:           make(chan *RelationScopeSettingsChange),
+	}
+	go func() {
+		defer w.finish()
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns a channel that will receive changes when units enter and
+// leave a relation scope. The Entered field in the first event on the channel
+// holds the initial state.
+func (w *RelationScopeSettingsWatcher) Changes() <-chan *RelationScopeSettingsChange {
+	return w.out
+}
+
+// mergeScope reads the settings of the units entering the scope in the
+// supplied RelationScopeChange event, and applies the expressed changes
+// to the supplied RelationScopeSettingsChange event.
+func (w *RelationScopeSettingsWatcher) mergeScope(changes *RelationScopeSettingsChange, c *RelationScopeChange) error {
+	keys := make([]string, len(c.Entered))
+	for i, name := range c.Entered {
+		keys[i] = w.sw.prefix + name
+	}
+	nodes, err := readSettingsBulk(w.backend, settingsC, keys)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range c.Entered {
+		node, ok := nodes[w.sw.prefix+name]
+		if !ok {
+			// The unit has already left the scope; the scope
+			// watcher will report its departure shortly.
+			continue
+		}
+		if changes.Entered == nil {
+			changes.Entered = make(map[string]map[string]interface{})
+		}
+		changes.Entered[name] = node.Map()
+		changes.Left = remove(changes.Left, name)
+	}
+	for _, name := range c.Left {
+		delete(changes.Entered, name)
+		changes.Left = append(changes.Left, name)
+	}
+	return nil
+}
+
+func (w *RelationScopeSettingsWatcher) finish() {
+	watcher.Stop(w.sw, &w.tomb)
+	close(w.out)
+	w.tomb.Done()
+}
+
+func (w *RelationScopeSettingsWatcher) loop() error {
+	var (
+		sentInitial bool
+		changes     = &RelationScopeSettingsChange{}
+		out         chan<- *RelationScopeSettingsChange
+	)
+	for {
+		select {
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case c, ok := <-w.sw.Changes():
+			if !ok {
+				return watcher.EnsureErr(w.sw)
+			}
+			if err := w.mergeScope(changes, c); err != nil {
+				return err
+			}
+			if !sentInitial || len(changes.Entered)+len(changes.Left) > 0 {
+				out = w.out
+			} else {
+				out = nil
+			}
+		case out <- changes:
+			sentInitial = true
+			changes = &RelationScopeSettingsChange{}
+			out = nil
+		}
+	}
+}
+
 // WatchLifeSuspendedStatus returns a watcher that notifies of changes to the life
 // or suspended status of the relation.
 func (r *Relation) WatchLifeSuspendedStatus() StringsWatcher {
@@ -1273,6 +2141,176 @@ func (w *relationLifeSuspendedWatcher) loop() error {
 	}
 }
 
+// relationDetailsWatcher sends the details of relations whose life or
+// suspended status changes.
+type relationDetailsWatcher struct {
+	commonWatcher
+	out chan []RelationDetails
+
+	members bson.D
+	filter  func(interface{}) bool
+	// known holds the most recent details of the relations
+	// not yet known to be dead, keyed by relation key.
+	known map[string]RelationDetails
+}
+
+// newRelationDetailsWatcher creates a watcher that sends the details of
+// specific relations whenever their life or suspended status changes.
+func newRelationDetailsWatcher(
+	backend modelBackend,
+	members bson.D,
+	filter func(key interface{}) bool,
+) *relationDetailsWatcher {
+	w := &relationDetailsWatcher{
+		commonWatcher: newCommonWatcher(backend),
+		out:           make(chan []RelationDetails),
+		members:       members,
+		filter:        filter,
+		known:         make(map[string]RelationDetails),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes is part of the RelationDetailsWatcher interface.
+func (w *relationDetailsWatcher) Changes() <-chan []RelationDetails {
+	return w.out
+}
+
+var relationDetailsFields = bson.D{
+	{"_id", 1}, {"key", 1}, {"id", 1}, {"endpoints", 1}, {"life", 1}, {"suspended", 1},
+}
+
+func relationDetailsFromDoc(doc *relationDoc) RelationDetails {
+	return RelationDetails{
+		Id:        doc.Id,
+		Key:       doc.Key,
+		Endpoints: doc.Endpoints,
+		Life:      doc.Life,
+		Suspended: doc.Suspended,
+	}
+}
+
+func (w *relationDetailsWatcher) initial() (map[string]RelationDetails, error) {
+	coll, closer := w.db.GetCollection(relationsC)
+	defer closer()
+
+	var docs []relationDoc
+	if err := coll.Find(w.members).Select(relationDetailsFields).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	changes := make(map[string]RelationDetails)
+	for i := range docs {
+		key := w.backend.localID(docs[i].DocID)
+		details := relationDetailsFromDoc(&docs[i])
+		changes[key] = details
+		if details.Life != Dead {
+			w.known[key] = details
+		}
+	}
+	return changes, nil
+}
+
+func (w *relationDetailsWatcher) merge(changes map[string]RelationDetails, updates map[interface{}]bool) error {
+	coll, closer := w.db.GetCollection(relationsC)
+	defer closer()
+
+	// Separate ids into those thought to exist and those known to be
+	// removed. Removed relations are reported as dead, with the details
+	// last known for them.
+	var changed []string
+	latest := make(map[string]RelationDetails)
+	for docID, exists := range updates {
+		id, ok := docID.(string)
+		if !ok {
+			return errors.Errorf("id is not of type string, got %T", docID)
+		}
+		if exists {
+			changed = append(changed, id)
+			continue
+		}
+		key := w.backend.localID(id)
+		if details, ok := w.known[key]; ok {
+			details.Life = Dead
+			latest[key] = details
+		}
+	}
+
+	// Any relations that don't actually exist are ignored; we'll hear
+	// about them in the next set of updates.
+	var docs []relationDoc
+	query := coll.Find(bson.D{{"_id", bson.D{{"$in", changed}}}}).Select(relationDetailsFields)
+	if err := query.All(&docs); err != nil {
+		return errors.Trace(err)
+	}
+	for i := range docs {
+		latest[w.backend.localID(docs[i].DocID)] = relationDetailsFromDoc(&docs[i])
+	}
+
+	// Add to changes any whose life or suspended status is known to
+	// have changed.
+	for key, details := range latest {
+		gone := details.Life == Dead
+		old, known := w.known[key]
+		switch {
+		case known && gone:
+			delete(w.known, key)
+		case !known && !gone:
+			w.known[key] = details
+		case known && (details.Life != old.Life || details.Suspended != old.Suspended):
+			w.known[key] = details
+		default:
+			continue
+		}
+		changes[key] = details
+	}
+	return nil
+}
+
+func (w *relationDetailsWatcher) loop() error {
+	in := make(chan watcher.Change)
+	w.watcher.WatchCollectionWithFilter(relationsC, in, w.filter)
+	defer w.watcher.UnwatchCollection(relationsC, in)
+	changes, err := w.initial()
+	if err != nil {
+		return err
+	}
+	out := w.out
+	for {
+		details := make([]RelationDetails, 0, len(changes))
+		for _, d := range changes {
+			details = append(details, d)
+		}
+		sort.Slice(details, func(i, j int) bool {
+			return details[i].Id < details[j].Id
+		})
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case ch := <-in:
+			updates, ok := collect(ch, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			if err := w.merge(changes, updates); err != nil {
+				return err
+			}
+			if len(changes) > 0 {
+				out = w.out
+			}
+		case out <- details:
+			changes = make(map[string]RelationDetails)
+			out = nil
+		}
+	}
+}
+
 // unitsWatcher notifies of changes to a set of units. Notifications will be
 // sent when units enter or leave the set, and when units in the set change
 // their lifecycle status. The initial event contains all units in the set,
@@ -1302,6 +2340,61 @@ func (u *Unit) WatchSubordinateUnits() StringsWatcher {
 	return newUnitsWatcher(u.st, u.Tag(), getUnits, coll, u.doc.DocID)
 }
 
+// WatchSubordinateUnitsWithPrincipal returns a SubordinateUnitsWatcher
+// tracking the unit's subordinate units. It reports the same changes as
+// WatchSubordinateUnits, with each subordinate mapped to this unit.
+func (u *Unit) WatchSubordinateUnitsWithPrincipal() SubordinateUnitsWatcher {
+	w := &subordinateUnitsWatcher{
+		commonWatcher: newCommonWatcher(u.st),
+		principal:     u.doc.Name,
+		source:        u.WatchSubordinateUnits(),
+		out:           make(chan map[string]string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// subordinateUnitsWatcher maps the subordinate unit names reported by
+// the watcher returned from WatchSubordinateUnits to their principal.
+type subordinateUnitsWatcher struct {
+	commonWatcher
+	principal string
+	source    StringsWatcher
+	out       chan map[string]string
+}
+
+// Changes returns the event channel for the watcher.
+func (w *subordinateUnitsWatcher) Changes() <-chan map[string]string {
+	return w.out
+}
+
+func (w *subordinateUnitsWatcher) loop() error {
+	defer watcher.Stop(w.source, &w.tomb)
+	changes := make(map[string]string)
+	var out chan<- map[string]string
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case units, ok := <-w.source.Changes():
+			if !ok {
+				return watcher.EnsureErr(w.source)
+			}
+			for _, name := range units {
+				changes[name] = w.principal
+			}
+			out = w.out
+		case out <- changes:
+			changes = make(map[string]string)
+			out = nil
+		}
+	}
+}
+
 // WatchPrincipalUnits returns a StringsWatcher tracking the machine's principal
 // units.
 func (m *Machine) WatchPrincipalUnits() StringsWatcher {
@@ -1493,30 +2586,171 @@ func (w *unitsWatcher) loop(coll, id string) error {
 	}
 }
 
-// WatchHardwareCharacteristics returns a watcher for observing changes to a machine's hardware characteristics.
-func (m *Machine) WatchHardwareCharacteristics() NotifyWatcher {
-	return newEntityWatcher(m.st, instanceDataC, m.doc.DocID)
-}
-
-// WatchControllerInfo returns a NotifyWatcher for the controllers collection
-func (st *State) WatchControllerInfo() NotifyWatcher {
-	return newEntityWatcher(st, controllersC, modelGlobalKey)
+// WatchAgentPresence returns a NotifyWatcher that fires whenever the
+// machine agent's presence changes, so that callers can react to the
+// agent starting or stopping instead of polling AgentPresence. The
+// initial event is sent immediately.
+func (m *Machine) WatchAgentPresence() NotifyWatcher {
+	w := &agentPresenceWatcher{
+		commonWatcher: newCommonWatcher(m.st),
+		presence:      m.st.workers.presenceWatcher(),
+		key:           m.globalKey(),
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
 }
 
-// WatchControllerConfig returns a NotifyWatcher for controller settings.
-func (st *State) WatchControllerConfig() NotifyWatcher {
-	return newEntityWatcher(st, controllersC, controllerSettingsGlobalKey)
+// agentPresenceWatcher notifies of changes to the presence of an agent.
+type agentPresenceWatcher struct {
+	commonWatcher
+	presence *presence.Watcher
+	key      string
+	out      chan struct{}
 }
 
-// Watch returns a watcher for observing changes to a machine.
-func (m *Machine) Watch() NotifyWatcher {
-	return newEntityWatcher(m.st, machinesC, m.doc.DocID)
+// Changes returns the event channel for the watcher.
+func (w *agentPresenceWatcher) Changes() <-chan struct{} {
+	return w.out
 }
 
-// Watch returns a watcher for observing changes to an application.
-func (a *Application) Watch() NotifyWatcher {
-	return newEntityWatcher(a.st, applicationsC, a.doc.DocID)
-}
+func (w *agentPresenceWatcher) loop() error {
+	// The presence watcher reports the agent's current liveness as
+	// soon as the key is watched, which provides the initial event.
+	ch := make(chan presence.Change)
+	w.presence.Watch(w.key, ch)
+	defer w.presence.Unwatch(w.key, ch)
+	var alive, known bool
+	var out chan<- struct{}
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.presence.Dead():
+			return stateWatcherDeadError(w.presence.Err())
+		case change := <-ch:
+			if known && change.Alive == alive {
+				continue
+			}
+			alive, known = change.Alive, true
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// WatchHardwareCharacteristics returns a watcher for observing changes to a machine's hardware characteristics.
+func (m *Machine) WatchHardwareCharacteristics() NotifyWatcher {
+	w := &hardwareCharacteristicsWatcher{
+		commonWatcher: newCommonWatcher(m.st),
+		machineId:     m.doc.Id,
+		docID:         m.doc.DocID,
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// hardwareCharacteristicsWatcher notifies about changes to the hardware
+// characteristics recorded for a machine.
+//
+// The first event is emitted immediately. From then on, a new event is
+// emitted only when the machine's hardware characteristics are first
+// recorded or change; other changes to the machine's instance data,
+// such as whether the instance is kept on removal, are ignored.
+type hardwareCharacteristicsWatcher struct {
+	commonWatcher
+	machineId string
+	docID     string
+	out       chan struct{}
+}
+
+var _ Watcher = (*hardwareCharacteristicsWatcher)(nil)
+
+// Changes returns the event channel for w.
+func (w *hardwareCharacteristicsWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+// hardware returns the machine's current hardware characteristics, or
+// nil if none have been recorded.
+func (w *hardwareCharacteristicsWatcher) hardware() (*instance.HardwareCharacteristics, error) {
+	coll, closer := w.db.GetCollection(instanceDataC)
+	defer closer()
+	var doc instanceData
+	err := coll.FindId(w.machineId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get instance data for machine %v", w.machineId)
+	}
+	return hardwareCharacteristics(doc), nil
+}
+
+func (w *hardwareCharacteristicsWatcher) loop() error {
+	coll, closer := w.db.GetCollection(instanceDataC)
+	revno, err := getTxnRevno(coll, w.docID)
+	closer()
+	if err != nil {
+		return err
+	}
+	in := make(chan watcher.Change)
+	w.watcher.Watch(instanceDataC, w.docID, revno, in)
+	defer w.watcher.Unwatch(instanceDataC, w.docID, in)
+	hardware, err := w.hardware()
+	if err != nil {
+		return err
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-in:
+			newHardware, err := w.hardware()
+			if err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(newHardware, hardware) {
+				hardware = newHardware
+				out = w.out
+			}
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// WatchControllerInfo returns a NotifyWatcher for the controllers collection
+func (st *State) WatchControllerInfo() NotifyWatcher {
+	return newEntityWatcher(st, controllersC, modelGlobalKey)
+}
+
+// WatchControllerConfig returns a NotifyWatcher for controller settings.
+func (st *State) WatchControllerConfig() NotifyWatcher {
+	return newEntityWatcher(st, controllersC, controllerSettingsGlobalKey)
+}
+
+// Watch returns a watcher for observing changes to a machine.
+func (m *Machine) Watch() NotifyWatcher {
+	return newEntityWatcher(m.st, machinesC, m.doc.DocID)
+}
+
+// Watch returns a watcher for observing changes to an application.
+func (a *Application) Watch() NotifyWatcher {
+	return newEntityWatcher(a.st, applicationsC, a.doc.DocID)
+}
 
 // WatchLeaderSettings returns a watcher for observing changed to a service's
 // leader settings.
@@ -1553,6 +2787,92 @@ func (model *Model) WatchForModelConfigChanges() NotifyWatcher {
 	return newEntityWatcher(model.st, settingsC, model.st.docID(modelGlobalKey))
 }
 
+// WatchForModelConfigKeyChanges returns a NotifyWatcher waiting for any
+// of the given model config keys to change value. The first event is
+// sent immediately; from then on, changes to other keys, and writes
+// that leave the values of the given keys unchanged, are ignored.
+func (model *Model) WatchForModelConfigKeyChanges(keys ...string) NotifyWatcher {
+	w := &modelConfigKeysWatcher{
+		commonWatcher: newCommonWatcher(model.st),
+		keys:          keys,
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// modelConfigKeysWatcher notifies about changes to the values of a set
+// of model config keys.
+type modelConfigKeysWatcher struct {
+	commonWatcher
+	keys []string
+	out  chan struct{}
+}
+
+var _ Watcher = (*modelConfigKeysWatcher)(nil)
+
+// Changes returns the event channel for w.
+func (w *modelConfigKeysWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+// values returns the current values of the watched keys, omitting
+// those that are not set.
+func (w *modelConfigKeysWatcher) values() (map[string]interface{}, error) {
+	settings, err := readSettings(w.db, settingsC, modelGlobalKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	values := make(map[string]interface{})
+	for _, key := range w.keys {
+		if value, ok := settings.Get(key); ok {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+func (w *modelConfigKeysWatcher) loop() error {
+	docID := w.backend.docID(modelGlobalKey)
+	settings, closer := w.db.GetCollection(settingsC)
+	revno, err := getTxnRevno(settings, docID)
+	closer()
+	if err != nil {
+		return err
+	}
+	in := make(chan watcher.Change)
+	w.watcher.Watch(settingsC, docID, revno, in)
+	defer w.watcher.Unwatch(settingsC, docID, in)
+	values, err := w.values()
+	if err != nil {
+		return err
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-in:
+			newValues, err := w.values()
+			if err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(newValues, values) {
+				values = newValues
+				out = w.out
+			}
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
 // WatchModelEntityReferences returns a NotifyWatcher waiting for the Model
 // Entity references to change for specified model.
 func (st *State) WatchModelEntityReferences(mUUID string) NotifyWatcher {
@@ -1731,29 +3051,347 @@ func (w *docWatcher) loop(docKeys []docKey) error {
 // the machine, or the lifecycle of a unit that is currently assigned to
 // the machine changes.
 //
-// After a unit is found to be Dead, no further event will include it.
-type machineUnitsWatcher struct {
+// After a unit is found to be Dead, no further event will include it.
+//
+// If principalsOnly is set, subordinate units are neither watched nor
+// reported.
+type machineUnitsWatcher struct {
+	commonWatcher
+	machine        *Machine
+	principalsOnly bool
+	out            chan []string
+	in             chan watcher.Change
+	known          map[string]Life
+}
+
+var _ Watcher = (*machineUnitsWatcher)(nil)
+
+// WatchUnits returns a new StringsWatcher watching m's units.
+func (m *Machine) WatchUnits() StringsWatcher {
+	return newMachineUnitsWatcher(m, false)
+}
+
+// WatchPrincipalUnitsOnly returns a new StringsWatcher watching m's
+// units as WatchUnits does, but ignoring subordinate units. Unlike
+// WatchPrincipalUnits, it reports lifecycle changes of the principal
+// units as well as their assignment to and from the machine.
+func (m *Machine) WatchPrincipalUnitsOnly() StringsWatcher {
+	return newMachineUnitsWatcher(m, true)
+}
+
+func newMachineUnitsWatcher(m *Machine, principalsOnly bool) StringsWatcher {
+	w := &machineUnitsWatcher{
+		commonWatcher:  newCommonWatcher(m.st),
+		out:            make(chan []string),
+		in:             make(chan watcher.Change),
+		known:          make(map[string]Life),
+		machine:        &Machine{st: m.st, doc: m.doc}, // Copy so it may be freely refreshed
+		principalsOnly: principalsOnly,
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *machineUnitsWatcher) Changes() <-chan []string {
+	return w.out
+}
+
+func (w *machineUnitsWatcher) updateMachine(pending []string) (new []string, err error) {
+	err = w.machine.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	for _, unitName := range w.machine.doc.Principals {
+		if _, ok := w.known[unitName]; !ok {
+			pending, err = w.merge(pending, unitName)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return pending, nil
+}
+
+func (w *machineUnitsWatcher) merge(pending []string, unitName string) (new []string, err error) {
+	doc := unitDoc{}
+	newUnits, closer := w.db.GetCollection(unitsC)
+	defer closer()
+	err = newUnits.FindId(unitName).One(&doc)
+	if err != nil && err != mgo.ErrNotFound {
+		return nil, err
+	}
+	life, known := w.known[unitName]
+	if err == mgo.ErrNotFound || doc.Principal == "" && (doc.MachineId == "" || doc.MachineId != w.machine.doc.Id) {
+		// Unit was removed or unassigned from w.machine.
+		if known {
+			delete(w.known, unitName)
+			w.watcher.Unwatch(unitsC, w.backend.docID(unitName), w.in)
+			if life != Dead && !hasString(pending, unitName) {
+				pending = append(pending, unitName)
+			}
+			if w.principalsOnly {
+				return pending, nil
+			}
+			for _, subunitName := range doc.Subordinates {
+				if sublife, subknown := w.known[subunitName]; subknown {
+					delete(w.known, subunitName)
+					w.watcher.Unwatch(unitsC, w.backend.docID(subunitName), w.in)
+					if sublife != Dead && !hasString(pending, subunitName) {
+						pending = append(pending, subunitName)
+					}
+				}
+			}
+		}
+		return pending, nil
+	}
+	if !known {
+		w.watcher.Watch(unitsC, doc.DocID, doc.TxnRevno, w.in)
+		pending = append(pending, unitName)
+	} else if life != doc.Life && !hasString(pending, unitName) {
+		pending = append(pending, unitName)
+	}
+	w.known[unitName] = doc.Life
+	if w.principalsOnly {
+		return pending, nil
+	}
+	for _, subunitName := range doc.Subordinates {
+		if _, ok := w.known[subunitName]; !ok {
+			pending, err = w.merge(pending, subunitName)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return pending, nil
+}
+
+func (w *machineUnitsWatcher) loop() error {
+	defer func() {
+		for unit := range w.known {
+			w.watcher.Unwatch(unitsC, w.backend.docID(unit), w.in)
+		}
+	}()
+
+	machines, closer := w.db.GetCollection(machinesC)
+	revno, err := getTxnRevno(machines, w.machine.doc.DocID)
+	closer()
+	if err != nil {
+		return err
+	}
+	machineCh := make(chan watcher.Change)
+	w.watcher.Watch(machinesC, w.machine.doc.DocID, revno, machineCh)
+	defer w.watcher.Unwatch(machinesC, w.machine.doc.DocID, machineCh)
+	changes, err := w.updateMachine([]string(nil))
+	if err != nil {
+		return err
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-machineCh:
+			changes, err = w.updateMachine(changes)
+			if err != nil {
+				return err
+			}
+			if len(changes) > 0 {
+				out = w.out
+			}
+		case c := <-w.in:
+			changes, err = w.merge(changes, w.backend.localID(c.Id.(string)))
+			if err != nil {
+				return err
+			}
+			if len(changes) > 0 {
+				out = w.out
+			}
+		case out <- changes:
+			out = nil
+			changes = nil
+		}
+	}
+}
+
+// machineAddressesWatcher notifies about changes to a machine's addresses.
+//
+// The first event emitted contains the addresses currently assigned to the
+// machine. From then on, a new event is emitted whenever the machine's
+// addresses change.
+type machineAddressesWatcher struct {
+	commonWatcher
+	machine *Machine
+	out     chan struct{}
+}
+
+var _ Watcher = (*machineAddressesWatcher)(nil)
+
+// WatchAddresses returns a new NotifyWatcher watching m's addresses.
+func (m *Machine) WatchAddresses() NotifyWatcher {
+	return newMachineAddressesWatcher(m)
+}
+
+func newMachineAddressesWatcher(m *Machine) NotifyWatcher {
+	w := &machineAddressesWatcher{
+		commonWatcher: newCommonWatcher(m.st),
+		out:           make(chan struct{}),
+		machine:       &Machine{st: m.st, doc: m.doc}, // Copy so it may be freely refreshed
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *machineAddressesWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *machineAddressesWatcher) loop() error {
+	machines, closer := w.db.GetCollection(machinesC)
+	revno, err := getTxnRevno(machines, w.machine.doc.DocID)
+	closer()
+	if err != nil {
+		return err
+	}
+	machineCh := make(chan watcher.Change)
+	w.watcher.Watch(machinesC, w.machine.doc.DocID, revno, machineCh)
+	defer w.watcher.Unwatch(machinesC, w.machine.doc.DocID, machineCh)
+	addresses := w.machine.Addresses()
+	out := w.out
+	for {
+		select {
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-machineCh:
+			if err := w.machine.Refresh(); err != nil {
+				return err
+			}
+			newAddresses := w.machine.Addresses()
+			if !addressesEqual(newAddresses, addresses) {
+				addresses = newAddresses
+				out = w.out
+			}
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// unitAssignmentWatcher notifies about changes to the machine a unit is
+// assigned to, and to the unit's principal.
+//
+// The first event is emitted immediately. From then on, a new event is
+// emitted only when the unit's machine id or principal changes; other
+// changes to the unit document are ignored.
+type unitAssignmentWatcher struct {
+	commonWatcher
+	unit *Unit
+	out  chan struct{}
+}
+
+var _ Watcher = (*unitAssignmentWatcher)(nil)
+
+// WatchAssignment returns a new NotifyWatcher watching the machine u is
+// assigned to and the principal of u.
+func (u *Unit) WatchAssignment() NotifyWatcher {
+	return newUnitAssignmentWatcher(u)
+}
+
+func newUnitAssignmentWatcher(u *Unit) NotifyWatcher {
+	w := &unitAssignmentWatcher{
+		commonWatcher: newCommonWatcher(u.st),
+		out:           make(chan struct{}),
+		unit:          &Unit{st: u.st, doc: u.doc}, // Copy so it may be freely refreshed
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *unitAssignmentWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *unitAssignmentWatcher) loop() error {
+	units, closer := w.db.GetCollection(unitsC)
+	revno, err := getTxnRevno(units, w.unit.doc.DocID)
+	closer()
+	if err != nil {
+		return err
+	}
+	unitCh := make(chan watcher.Change)
+	w.watcher.Watch(unitsC, w.unit.doc.DocID, revno, unitCh)
+	defer w.watcher.Unwatch(unitsC, w.unit.doc.DocID, unitCh)
+	if err := w.unit.Refresh(); err != nil {
+		return err
+	}
+	machineId, principal := w.unit.doc.MachineId, w.unit.doc.Principal
+	out := w.out
+	for {
+		select {
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-unitCh:
+			if err := w.unit.Refresh(); err != nil {
+				return err
+			}
+			doc := w.unit.doc
+			if doc.MachineId != machineId || doc.Principal != principal {
+				machineId, principal = doc.MachineId, doc.Principal
+				out = w.out
+			}
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// unitAddressesWatcher notifies about changes to a unit's public and
+// private addresses.
+//
+// The first event is emitted immediately. From then on, a new event is
+// emitted only when the unit's public or private address changes, either
+// because the addresses of its machine changed or because the unit was
+// assigned to a machine; other changes to the unit and machine documents
+// are ignored.
+type unitAddressesWatcher struct {
 	commonWatcher
-	machine *Machine
-	out     chan []string
-	in      chan watcher.Change
-	known   map[string]Life
+	unit *Unit
+	out  chan struct{}
 }
 
-var _ Watcher = (*machineUnitsWatcher)(nil)
+var _ Watcher = (*unitAddressesWatcher)(nil)
 
-// WatchUnits returns a new StringsWatcher watching m's units.
-func (m *Machine) WatchUnits() StringsWatcher {
-	return newMachineUnitsWatcher(m)
+// WatchAddresses returns a new NotifyWatcher watching the public and
+// private addresses of u.
+func (u *Unit) WatchAddresses() NotifyWatcher {
+	return newUnitAddressesWatcher(u)
 }
 
-func newMachineUnitsWatcher(m *Machine) StringsWatcher {
-	w := &machineUnitsWatcher{
-		commonWatcher: newCommonWatcher(m.st),
-		out:           make(chan []string),
-		in:            make(chan watcher.Change),
-		known:         make(map[string]Life),
-		machine:       &Machine{st: m.st, doc: m.doc}, // Copy so it may be freely refreshed
+func newUnitAddressesWatcher(u *Unit) NotifyWatcher {
+	w := &unitAddressesWatcher{
+		commonWatcher: newCommonWatcher(u.st),
+		out:           make(chan struct{}),
+		unit:          &Unit{st: u.st, doc: u.doc}, // Copy so it may be freely refreshed
 	}
 	go func() {
 		defer w.tomb.Done()
@@ -1764,90 +3402,93 @@ func newMachineUnitsWatcher(m *Machine) StringsWatcher {
 }
 
 // Changes returns the event channel for w.
-func (w *machineUnitsWatcher) Changes() <-chan []string {
+func (w *unitAddressesWatcher) Changes() <-chan struct{} {
 	return w.out
 }
 
-func (w *machineUnitsWatcher) updateMachine(pending []string) (new []string, err error) {
-	err = w.machine.Refresh()
+// unitAddress returns the address returned by get, or an empty address
+// if the unit has no machine or the machine has no such address.
+func unitAddress(get func() (network.Address, error)) (network.Address, error) {
+	addr, err := get()
+	if errors.IsNotAssigned(err) || errors.IsNotFound(err) || network.IsNoAddressError(err) {
+		return network.Address{}, nil
+	}
+	return addr, err
+}
+
+// addresses returns the unit's current public and private addresses.
+func (w *unitAddressesWatcher) addresses() ([]network.Address, error) {
+	public, err := unitAddress(w.unit.PublicAddress)
 	if err != nil {
-		return nil, err
+		return nil, errors.Trace(err)
 	}
-	for _, unitName := range w.machine.doc.Principals {
-		if _, ok := w.known[unitName]; !ok {
-			pending, err = w.merge(pending, unitName)
-			if err != nil {
-				return nil, err
-			}
-		}
+	private, err := unitAddress(w.unit.PrivateAddress)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
-	return pending, nil
+	return []network.Address{public, private}, nil
 }
 
-func (w *machineUnitsWatcher) merge(pending []string, unitName string) (new []string, err error) {
-	doc := unitDoc{}
-	newUnits, closer := w.db.GetCollection(unitsC)
-	defer closer()
-	err = newUnits.FindId(unitName).One(&doc)
-	if err != nil && err != mgo.ErrNotFound {
-		return nil, err
-	}
-	life, known := w.known[unitName]
-	if err == mgo.ErrNotFound || doc.Principal == "" && (doc.MachineId == "" || doc.MachineId != w.machine.doc.Id) {
-		// Unit was removed or unassigned from w.machine.
-		if known {
-			delete(w.known, unitName)
-			w.watcher.Unwatch(unitsC, w.backend.docID(unitName), w.in)
-			if life != Dead && !hasString(pending, unitName) {
-				pending = append(pending, unitName)
-			}
-			for _, subunitName := range doc.Subordinates {
-				if sublife, subknown := w.known[subunitName]; subknown {
-					delete(w.known, subunitName)
-					w.watcher.Unwatch(unitsC, w.backend.docID(subunitName), w.in)
-					if sublife != Dead && !hasString(pending, subunitName) {
-						pending = append(pending, subunitName)
-					}
-				}
-			}
-		}
-		return pending, nil
+// machineDocID returns the document id of the unit's machine, or ""
+// if the unit is not assigned to a machine.
+func (w *unitAddressesWatcher) machineDocID() (string, error) {
+	id, err := w.unit.AssignedMachineId()
+	if errors.IsNotAssigned(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
 	}
-	if !known {
-		w.watcher.Watch(unitsC, doc.DocID, doc.TxnRevno, w.in)
-		pending = append(pending, unitName)
-	} else if life != doc.Life && !hasString(pending, unitName) {
-		pending = append(pending, unitName)
+	return w.backend.docID(id), nil
+}
+
+func (w *unitAddressesWatcher) loop() error {
+	units, closer := w.db.GetCollection(unitsC)
+	revno, err := getTxnRevno(units, w.unit.doc.DocID)
+	closer()
+	if err != nil {
+		return err
 	}
-	w.known[unitName] = doc.Life
-	for _, subunitName := range doc.Subordinates {
-		if _, ok := w.known[subunitName]; !ok {
-			pending, err = w.merge(pending, subunitName)
-			if err != nil {
-				return nil, err
-			}
-		}
+	unitCh := make(chan watcher.Change)
+	w.watcher.Watch(unitsC, w.unit.doc.DocID, revno, unitCh)
+	defer w.watcher.Unwatch(unitsC, w.unit.doc.DocID, unitCh)
+	if err := w.unit.Refresh(); err != nil {
+		return err
 	}
-	return pending, nil
-}
 
-func (w *machineUnitsWatcher) loop() error {
+	// The machine document is watched too, and rewatched whenever
+	// the unit's machine changes.
+	machineCh := make(chan watcher.Change)
+	var machineID string
 	defer func() {
-		for unit := range w.known {
-			w.watcher.Unwatch(unitsC, w.backend.docID(unit), w.in)
+		if machineID != "" {
+			w.watcher.Unwatch(machinesC, machineID, machineCh)
 		}
 	}()
-
-	machines, closer := w.db.GetCollection(machinesC)
-	revno, err := getTxnRevno(machines, w.machine.doc.DocID)
-	closer()
-	if err != nil {
+	watchMachine := func() error {
+		docID, err := w.machineDocID()
+		if err != nil || docID == machineID {
+			return err
+		}
+		if machineID != "" {
+			w.watcher.Unwatch(machinesC, machineID, machineCh)
+		}
+		machineID = docID
+		if machineID == "" {
+			return nil
+		}
+		machines, closer := w.db.GetCollection(machinesC)
+		revno, err := getTxnRevno(machines, machineID)
+		closer()
+		if err != nil {
+			return err
+		}
+		w.watcher.Watch(machinesC, machineID, revno, machineCh)
+		return nil
+	}
+	if err := watchMachine(); err != nil {
 		return err
 	}
-	machineCh := make(chan watcher.Change)
-	w.watcher.Watch(machinesC, w.machine.doc.DocID, revno, machineCh)
-	defer w.watcher.Unwatch(machinesC, w.machine.doc.DocID, machineCh)
-	changes, err := w.updateMachine([]string(nil))
+	addresses, err := w.addresses()
 	if err != nil {
 		return err
 	}
@@ -1858,52 +3499,52 @@ func (w *machineUnitsWatcher) loop() error {
 			return stateWatcherDeadError(w.watcher.Err())
 		case <-w.tomb.Dying():
 			return tomb.ErrDying
-		case <-machineCh:
-			changes, err = w.updateMachine(changes)
-			if err != nil {
+		case <-unitCh:
+			if err := w.unit.Refresh(); err != nil {
 				return err
 			}
-			if len(changes) > 0 {
-				out = w.out
-			}
-		case c := <-w.in:
-			changes, err = w.merge(changes, w.backend.localID(c.Id.(string)))
-			if err != nil {
+			if err := watchMachine(); err != nil {
 				return err
 			}
-			if len(changes) > 0 {
-				out = w.out
-			}
-		case out <- changes:
+		case <-machineCh:
+		case out <- struct{}{}:
 			out = nil
-			changes = nil
+			continue
+		}
+		newAddresses, err := w.addresses()
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(newAddresses, addresses) {
+			addresses = newAddresses
+			out = w.out
 		}
 	}
 }
 
-// machineAddressesWatcher notifies about changes to a machine's addresses.
+// unitStatusWatcher notifies about changes to the status of a unit, as
+// reported by Unit.Status.
 //
-// The first event emitted contains the addresses currently assigned to the
-// machine. From then on, a new event is emitted whenever the machine's
-// addresses change.
-type machineAddressesWatcher struct {
+// The first event is emitted immediately. From then on, a new event is
+// emitted only when the unit's status value or message changes. Since
+// an error in the unit agent is reported as the unit's status, both
+// the unit and unit agent status documents are watched; changes to
+// their other fields, such as the status data or timestamp, are ignored.
+type unitStatusWatcher struct {
 	commonWatcher
-	machine *Machine
-	out     chan struct{}
+	unit *Unit
+	out  chan struct{}
 }
 
-var _ Watcher = (*machineAddressesWatcher)(nil)
-
-// WatchAddresses returns a new NotifyWatcher watching m's addresses.
-func (m *Machine) WatchAddresses() NotifyWatcher {
-	return newMachineAddressesWatcher(m)
-}
+var _ Watcher = (*unitStatusWatcher)(nil)
 
-func newMachineAddressesWatcher(m *Machine) NotifyWatcher {
-	w := &machineAddressesWatcher{
-		commonWatcher: newCommonWatcher(m.st),
+// WatchStatus returns a NotifyWatcher that notifies when the status or
+// status message of the unit changes.
+func (u *Unit) WatchStatus() NotifyWatcher {
+	w := &unitStatusWatcher{
+		commonWatcher: newCommonWatcher(u.st),
 		out:           make(chan struct{}),
-		machine:       &Machine{st: m.st, doc: m.doc}, // Copy so it may be freely refreshed
+		unit:          &Unit{st: u.st, doc: u.doc},
 	}
 	go func() {
 		defer w.tomb.Done()
@@ -1914,21 +3555,37 @@ func newMachineAddressesWatcher(m *Machine) NotifyWatcher {
 }
 
 // Changes returns the event channel for w.
-func (w *machineAddressesWatcher) Changes() <-chan struct{} {
+func (w *unitStatusWatcher) Changes() <-chan struct{} {
 	return w.out
 }
 
-func (w *machineAddressesWatcher) loop() error {
-	machines, closer := w.db.GetCollection(machinesC)
-	revno, err := getTxnRevno(machines, w.machine.doc.DocID)
+// status returns the unit's current status value and message.
+func (w *unitStatusWatcher) status() (status.Status, string, error) {
+	info, err := w.unit.Status()
+	if err != nil {
+		return "", "", errors.Annotatef(err, "cannot get status of unit %q", w.unit)
+	}
+	return info.Status, info.Message, nil
+}
+
+func (w *unitStatusWatcher) loop() error {
+	in := make(chan watcher.Change)
+	statuses, closer := w.db.GetCollection(statusesC)
+	for _, key := range []string{w.unit.globalKey(), w.unit.globalAgentKey()} {
+		docID := w.backend.docID(key)
+		revno, err := getTxnRevno(statuses, docID)
+		if err != nil {
+			closer()
+			return err
+		}
+		w.watcher.Watch(statusesC, docID, revno, in)
+		defer w.watcher.Unwatch(statusesC, docID, in)
+	}
 	closer()
+	current, message, err := w.status()
 	if err != nil {
 		return err
 	}
-	machineCh := make(chan watcher.Change)
-	w.watcher.Watch(machinesC, w.machine.doc.DocID, revno, machineCh)
-	defer w.watcher.Unwatch(machinesC, w.machine.doc.DocID, machineCh)
-	addresses := w.machine.Addresses()
 	out := w.out
 	for {
 		select {
@@ -1936,13 +3593,13 @@ func (w *machineAddressesWatcher) loop() error {
 			return stateWatcherDeadError(w.watcher.Err())
 		case <-w.tomb.Dying():
 			return tomb.ErrDying
-		case <-machineCh:
-			if err := w.machine.Refresh(); err != nil {
+		case <-in:
+			newCurrent, newMessage, err := w.status()
+			if err != nil {
 				return err
 			}
-			newAddresses := w.machine.Addresses()
-			if !addressesEqual(newAddresses, addresses) {
-				addresses = newAddresses
+			if newCurrent != current || newMessage != message {
+				current, message = newCurrent, newMessage
 				out = w.out
 			}
 		case out <- struct{}{}:
@@ -2407,6 +4064,151 @@ func (st *State) watchEnqueuedActionsFilteredBy(receivers ...ActionReceiver) Str
 	})
 }
 
+// machineInstanceErrorWatcher notifies of machines whose instance status
+// has entered ProvisioningError.
+type machineInstanceErrorWatcher struct {
+	commonWatcher
+	// errored holds the ids of machines whose instance status was
+	// last seen to be ProvisioningError.
+	errored set.Strings
+	out     chan []string
+}
+
+var _ Watcher = (*machineInstanceErrorWatcher)(nil)
+
+// WatchMachinesWithTransientErrors returns a StringsWatcher that notifies
+// of the ids of machines whose instance status changes to
+// ProvisioningError. The first event holds the ids of all machines whose
+// instance status is ProvisioningError at the time; after that, a machine
+// is only reported again once its instance status has left and re-entered
+// that state.
+func (st *State) WatchMachinesWithTransientErrors() StringsWatcher {
+	w := &machineInstanceErrorWatcher{
+		commonWatcher: newCommonWatcher(st),
+		errored:       make(set.Strings),
+		out:           make(chan []string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *machineInstanceErrorWatcher) Changes() <-chan []string {
+	return w.out
+}
+
+// machineIdFromInstanceKey returns the id of the machine whose instance
+// has the given global key, or false if the key is not a machine
+// instance key.
+func machineIdFromInstanceKey(key string) (string, bool) {
+	if !strings.HasPrefix(key, "m#") || !strings.HasSuffix(key, "#instance") {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(key, "m#"), "#instance"), true
+}
+
+// initial returns the ids of the machines whose instance status is
+// currently ProvisioningError.
+func (w *machineInstanceErrorWatcher) initial() ([]string, error) {
+	statuses, closer := w.db.GetCollection(statusesC)
+	defer closer()
+
+	var doc struct {
+		DocID string `bson:"_id"`
+	}
+	var ids []string
+	iter := statuses.Find(bson.D{{"status", status.ProvisioningError}}).Select(bson.D{{"_id", 1}}).Iter()
+	for iter.Next(&doc) {
+		id, ok := machineIdFromInstanceKey(w.backend.localID(doc.DocID))
+		if !ok {
+			continue
+		}
+		w.errored.Add(id)
+		ids = append(ids, id)
+	}
+	return ids, iter.Close()
+}
+
+// merge reads the instance statuses with the changed keys, and adds to
+// changes the ids of any machines that have newly entered
+// ProvisioningError.
+func (w *machineInstanceErrorWatcher) merge(changes []string, updates map[interface{}]bool) ([]string, error) {
+	statuses, closer := w.db.GetCollection(statusesC)
+	defer closer()
+
+	for docID, exists := range updates {
+		key, err := w.backend.strictLocalID(docID.(string))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		id, _ := machineIdFromInstanceKey(key)
+		if !exists {
+			w.errored.Remove(id)
+			continue
+		}
+		var doc statusDoc
+		if err := statuses.FindId(key).One(&doc); err == mgo.ErrNotFound {
+			w.errored.Remove(id)
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		switch {
+		case doc.Status != status.ProvisioningError:
+			w.errored.Remove(id)
+		case !w.errored.Contains(id):
+			w.errored.Add(id)
+			changes = append(changes, id)
+		}
+	}
+	return changes, nil
+}
+
+func (w *machineInstanceErrorWatcher) loop() error {
+	in := make(chan watcher.Change)
+	filter := func(id interface{}) bool {
+		key, err := w.backend.strictLocalID(id.(string))
+		if err != nil {
+			return false
+		}
+		_, ok := machineIdFromInstanceKey(key)
+		return ok
+	}
+	w.watcher.WatchCollectionWithFilter(statusesC, in, filter)
+	defer w.watcher.UnwatchCollection(statusesC, in)
+	changes, err := w.initial()
+	if err != nil {
+		return err
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case ch := <-in:
+			updates, ok := collect(ch, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			if changes, err = w.merge(changes, updates); err != nil {
+				return err
+			}
+			if len(changes) > 0 {
+				out = w.out
+			}
+		case out <- changes:
+			changes = nil
+			out = nil
+		}
+	}
+}
+
 // WatchControllerStatusChanges starts and returns a StringsWatcher that
 // notifies when the status of a controller machine changes.
 // TODO(cherylj) Add unit tests for this, as per bug 1543408.
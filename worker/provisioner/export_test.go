@@ -39,6 +39,7 @@ var (
 	RetryStrategyDelay       = &retryStrategyDelay
 	RetryStrategyCount       = &retryStrategyCount
 	GetObservedNetworkConfig = &getObservedNetworkConfig
+	HarvestClock             = &harvestClock
 )
 
 var ClassifyMachine = classifyMachine
@@ -64,6 +65,10 @@ func SetupToStartMachine(p ProvisionerTask, machine *apiprovisioner.Machine, ver
 	return p.(*provisionerTask).setupToStartMachine(machine, version)
 }
 
+func MaintainMachines(p ProvisionerTask, machines []*apiprovisioner.Machine) error {
+	return p.(*provisionerTask).maintainMachines(machines)
+}
+
 func GetAPIProvisionerState(p Provisioner) *apiprovisioner.State {
 	return p.(*environProvisioner).st
 }
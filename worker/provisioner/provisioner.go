@@ -31,6 +31,11 @@ var _ Provisioner = (*containerProvisioner)(nil)
 var (
 	retryStrategyDelay = 10 * time.Second
 	retryStrategyCount = 10
+
+	// The retry budget allows a burst of retries, then one retry
+	// per refill interval, across all machines being started.
+	retryStrategyBudgetRefill = time.Second
+	retryStrategyBudgetBurst  = int64(20)
 )
 
 // Provisioner represents a running provisioner worker.
@@ -75,6 +80,16 @@ type provisioner struct {
 type RetryStrategy struct {
 	retryDelay time.Duration
 	retryCount int
+	// timeout is how long a machine may keep failing to start, measured
+	// from its first attempt, before the provisioner gives up on it
+	// until provisioning is retried manually. Zero means no limit.
+	timeout time.Duration
+	// budgetRefill and budgetBurst describe a token bucket shared by
+	// all machines being started: every retry takes a token, and
+	// waits for one if there are none left. A zero budgetRefill
+	// means retries are not limited across machines.
+	budgetRefill time.Duration
+	budgetBurst  int64
 }
 
 // NewRetryStrategy returns a new retry strategy with the specified delay and
@@ -86,6 +101,26 @@ func NewRetryStrategy(delay time.Duration, count int) RetryStrategy {
 	}
 }
 
+// NewRetryStrategyWithLimits returns a new retry strategy like
+// NewRetryStrategy, which additionally gives up on a machine once it
+// has been failing to start for longer than timeout, and limits the
+// rate of retries across all machines: a burst of retries is allowed,
+// after which one retry is allowed per refill interval. A zero timeout
+// or refill disables the corresponding limit.
+func NewRetryStrategyWithLimits(
+	delay time.Duration, count int,
+	timeout time.Duration,
+	refill time.Duration, burst int64,
+) RetryStrategy {
+	return RetryStrategy{
+		retryDelay:   delay,
+		retryCount:   count,
+		timeout:      timeout,
+		budgetRefill: refill,
+		budgetBurst:  burst,
+	}
+}
+
 // configObserver is implemented so that tests can see
 // when the environment configuration changes.
 type configObserver struct {
@@ -168,7 +203,17 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 		p.broker,
 		auth,
 		modelCfg.ImageStream(),
-		RetryStrategy{retryDelay: retryStrategyDelay, retryCount: retryStrategyCount},
+		NewRetryStrategyWithLimits(
+			retryStrategyDelay,
+			retryStrategyCount,
+			modelCfg.ProvisionerStartTimeout(),
+			retryStrategyBudgetRefill,
+			retryStrategyBudgetBurst,
+		),
+		ProvisionerTaskOptions{
+			HarvestExclude:      modelCfg.ProvisionerHarvestExclude(),
+			HarvestUnknownGrace: modelCfg.ProvisionerHarvestUnknownGrace(),
+		},
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -244,6 +289,8 @@ func (p *environProvisioner) loop() error {
 			if err := p.setConfig(modelConfig); err != nil {
 				return errors.Annotate(err, "loaded invalid model configuration")
 			}
+			task.SetHarvestExclude(modelConfig.ProvisionerHarvestExclude())
+			task.SetHarvestUnknownGrace(modelConfig.ProvisionerHarvestUnknownGrace())
 			task.SetHarvestMode(modelConfig.ProvisionerHarvestMode())
 		}
 	}
@@ -339,6 +386,8 @@ func (p *containerProvisioner) loop() error {
 				return errors.Annotate(err, "cannot load model configuration")
 			}
 			p.configObserver.notify(modelConfig)
+			task.SetHarvestExclude(modelConfig.ProvisionerHarvestExclude())
+			task.SetHarvestUnknownGrace(modelConfig.ProvisionerHarvestUnknownGrace())
 			task.SetHarvestMode(modelConfig.ProvisionerHarvestMode())
 		}
 	}
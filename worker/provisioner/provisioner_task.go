@@ -9,9 +9,12 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/juju/errors"
+	"github.com/juju/ratelimit"
 	"github.com/juju/utils"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/set"
 	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
@@ -48,6 +51,55 @@ type ProvisionerTask interface {
 	// should harvest machines. See config.HarvestMode for
 	// documentation of behavior.
 	SetHarvestMode(mode config.HarvestMode)
+
+	// SetHarvestExclude sets the name prefixes of instances that are
+	// managed outside of Juju. Such instances are never considered
+	// unknown, and so are never harvested.
+	SetHarvestExclude(prefixes []string)
+
+	// SetHarvestUnknownGrace sets how long an instance must have been
+	// unknown before the provisioner task will harvest it.
+	SetHarvestUnknownGrace(grace time.Duration)
+
+	// PlanDistribution returns the availability zone each of the given
+	// machines would be started in, were they started now in the given
+	// order, without recording anything. The zones are all "" if the
+	// provider does not support availability zones.
+	PlanDistribution(machineIds []string) (map[string]string, error)
+}
+
+// harvestClock is used to measure how long instances have been unknown.
+var harvestClock clock.Clock = clock.WallClock
+
+// NonceGenerator returns a nonce for a new instance being started by
+// the provisioner running on the machine with the given tag. The nonce
+// is stored with the machine when it is provisioned, and the machine
+// agent must present the same value before it is allowed to connect.
+type NonceGenerator func(provisionerTag names.MachineTag) (string, error)
+
+// DefaultNonceGenerator generates nonces with the format "machine-#:UUID".
+// The first part is a badge, specifying the tag of the machine the
+// provisioner is running on, while the second part is a random UUID.
+func DefaultNonceGenerator(provisionerTag names.MachineTag) (string, error) {
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return fmt.Sprintf("%s:%s", provisionerTag, uuid), nil
+}
+
+// validateNonce checks that the given nonce can be written to an
+// instance's agent configuration and later presented back to state.
+func validateNonce(nonce string) error {
+	if nonce == "" {
+		return errors.NotValidf("empty nonce")
+	}
+	for _, r := range nonce {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return errors.NotValidf("nonce %q", nonce)
+		}
+	}
+	return nil
 }
 
 type MachineGetter interface {
@@ -68,6 +120,22 @@ type ToolsFinder interface {
 	FindTools(version version.Number, series string, arch string) (coretools.List, error)
 }
 
+// ProvisionerTaskOptions holds the optional settings of a provisioner
+// task. The zero value is valid.
+type ProvisionerTaskOptions struct {
+	// HarvestExclude holds the name prefixes of instances managed
+	// outside of Juju, which are never harvested.
+	HarvestExclude []string
+
+	// HarvestUnknownGrace is how long an instance must have been
+	// unknown before it is harvested.
+	HarvestUnknownGrace time.Duration
+
+	// GenerateNonce generates the nonces of new instances. If nil,
+	// DefaultNonceGenerator is used.
+	GenerateNonce NonceGenerator
+}
+
 func NewProvisionerTask(
 	controllerUUID string,
 	machineTag names.MachineTag,
@@ -81,7 +149,12 @@ func NewProvisionerTask(
 	auth authentication.AuthenticationProvider,
 	imageStream string,
 	retryStartInstanceStrategy RetryStrategy,
+	options ProvisionerTaskOptions,
 ) (ProvisionerTask, error) {
+	generateNonce := options.GenerateNonce
+	if generateNonce == nil {
+		generateNonce = DefaultNonceGenerator
+	}
 	machineChanges := machineWatcher.Changes()
 	workers := []worker.Worker{machineWatcher}
 	var retryChanges watcher.NotifyChannel
@@ -101,10 +174,21 @@ func NewProvisionerTask(
 		auth:                       auth,
 		harvestMode:                harvestMode,
 		harvestModeChan:            make(chan config.HarvestMode, 1),
+		harvestExclude:             options.HarvestExclude,
+		harvestUnknownGrace:        options.HarvestUnknownGrace,
 		machines:                   make(map[string]*apiprovisioner.Machine),
+		firstAttempts:              make(map[string]time.Time),
+		unknownSince:               make(map[instance.Id]time.Time),
 		availabilityZoneMachines:   make([]*AvailabilityZoneMachine, 0),
 		imageStream:                imageStream,
 		retryStartInstanceStrategy: retryStartInstanceStrategy,
+		generateNonce:              generateNonce,
+	}
+	if retryStartInstanceStrategy.budgetRefill > 0 {
+		task.retryBudget = ratelimit.NewBucket(
+			retryStartInstanceStrategy.budgetRefill,
+			retryStartInstanceStrategy.budgetBurst,
+		)
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &task.catacomb,
@@ -137,11 +221,24 @@ type provisionerTask struct {
 	imageStream                string
 	harvestMode                config.HarvestMode
 	harvestModeChan            chan config.HarvestMode
+	harvestExcludeMutex        sync.Mutex
+	harvestExclude             []string
+	harvestUnknownGraceMutex   sync.Mutex
+	harvestUnknownGrace        time.Duration
 	retryStartInstanceStrategy RetryStrategy
+	// retryBudget is shared by all machines being started, bounding
+	// the rate of StartInstance retries. It is nil if unlimited.
+	retryBudget   *ratelimit.Bucket
+	generateNonce NonceGenerator
 	// instance id -> instance
 	instances map[instance.Id]instance.Instance
 	// machine id -> machine
-	machines                 map[string]*apiprovisioner.Machine
+	machines map[string]*apiprovisioner.Machine
+	// instance id -> time the instance was first seen to be unknown
+	unknownSince map[instance.Id]time.Time
+	// machine id -> time of the first attempt to start it
+	firstAttemptsMutex       sync.Mutex
+	firstAttempts            map[string]time.Time
 	azMachinesMutex          sync.RWMutex
 	availabilityZoneMachines []*AvailabilityZoneMachine
 }
@@ -210,6 +307,81 @@ func (task *provisionerTask) SetHarvestMode(mode config.HarvestMode) {
 	}
 }
 
+// SetHarvestExclude implements ProvisionerTask.SetHarvestExclude().
+func (task *provisionerTask) SetHarvestExclude(prefixes []string) {
+	task.harvestExcludeMutex.Lock()
+	defer task.harvestExcludeMutex.Unlock()
+	task.harvestExclude = prefixes
+}
+
+// instanceNamer is implemented by instances that have a name, such as
+// an OpenStack server name or an EC2 Name tag, as well as an id.
+type instanceNamer interface {
+	Name() string
+}
+
+// isHarvestExcluded reports whether the given instance is managed
+// outside of Juju, and must therefore never be harvested. Instances are
+// matched by name; those without one, as on providers whose instance
+// ids are the names, are matched by id.
+func (task *provisionerTask) isHarvestExcluded(inst instance.Instance) bool {
+	name := string(inst.Id())
+	if namer, ok := inst.(instanceNamer); ok {
+		name = namer.Name()
+	}
+	task.harvestExcludeMutex.Lock()
+	defer task.harvestExcludeMutex.Unlock()
+	for _, prefix := range task.harvestExclude {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetHarvestUnknownGrace implements ProvisionerTask.SetHarvestUnknownGrace().
+func (task *provisionerTask) SetHarvestUnknownGrace(grace time.Duration) {
+	task.harvestUnknownGraceMutex.Lock()
+	defer task.harvestUnknownGraceMutex.Unlock()
+	task.harvestUnknownGrace = grace
+}
+
+// unknownPastGrace records when each of the given unknown instances was
+// first seen to be unknown, forgets instances that are no longer
+// unknown, and returns those instances that have been unknown for at
+// least the harvest grace period.
+func (task *provisionerTask) unknownPastGrace(unknown []instance.Instance) []instance.Instance {
+	task.harvestUnknownGraceMutex.Lock()
+	grace := task.harvestUnknownGrace
+	task.harvestUnknownGraceMutex.Unlock()
+
+	now := harvestClock.Now()
+	stillUnknown := make(map[instance.Id]time.Time)
+	var expired, waiting []instance.Instance
+	for _, inst := range unknown {
+		since, ok := task.unknownSince[inst.Id()]
+		if !ok {
+			since = now
+		}
+		stillUnknown[inst.Id()] = since
+		if now.Sub(since) >= grace {
+			expired = append(expired, inst)
+		} else {
+			waiting = append(waiting, inst)
+		}
+	}
+	task.unknownSince = stillUnknown
+	if len(waiting) > 0 {
+		logger.Infof(
+			"%s is set to %v; unknown instances not yet stopped %v",
+			config.ProvisionerHarvestUnknownGraceKey,
+			grace,
+			instanceIds(waiting),
+		)
+	}
+	return expired
+}
+
 func (task *provisionerTask) processMachinesWithTransientErrors() error {
 	results, err := task.machineGetter.MachinesWithTransientErrors()
 	if err != nil {
@@ -232,6 +404,9 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 			continue
 		}
 		task.machines[machine.Tag().String()] = machine
+		// Provisioning has been retried by hand, so give the
+		// machine a fresh timeout.
+		task.clearFirstAttempt(machine)
 		pending = append(pending, machine)
 	}
 	return task.startMachines(pending)
@@ -259,6 +434,8 @@ func (task *provisionerTask) processMachines(ids []string) error {
 	if err != nil {
 		return err
 	}
+	unknown = task.withoutPoweredOffInstances(unknown)
+	unknown = task.unknownPastGrace(unknown)
 	if !task.harvestMode.HarvestUnknown() {
 		logger.Infof(
 			"%s is set to %s; unknown instances not stopped %v",
@@ -268,6 +445,7 @@ func (task *provisionerTask) processMachines(ids []string) error {
 		)
 		unknown = nil
 	}
+	unknown = task.withoutControllerInstances(unknown)
 	if task.harvestMode.HarvestNone() || !task.harvestMode.HarvestDestroyed() {
 		logger.Infof(
 			`%s is set to "%s"; will not harvest %s`,
@@ -298,12 +476,17 @@ func (task *provisionerTask) processMachines(ids []string) error {
 		if err := machine.MarkForRemoval(); err != nil {
 			logger.Errorf("failed to remove dead machine %q", machine)
 		}
+		task.clearFirstAttempt(machine)
 		task.removeMachineFromAZMap(machine)
 		delete(task.machines, machine.Id())
 	}
 
-	// Any machines that require maintenance get pinged
-	task.maintainMachines(maintain)
+	// Any machines that require maintenance get pinged. Failures are
+	// recorded against the machines concerned, and need not stop us
+	// starting the pending ones.
+	if err := task.maintainMachines(maintain); err != nil {
+		logger.Errorf("%v", err)
+	}
 
 	// Start an instance for the pending ones
 	return task.startMachines(pending)
@@ -476,11 +659,94 @@ func (task *provisionerTask) findUnknownInstances(stopping []instance.Instance)
 	}
 	var unknown []instance.Instance
 	for _, inst := range instances {
+		if task.isHarvestExcluded(inst) {
+			logger.Debugf("instance %v is excluded from harvesting", inst.Id())
+			continue
+		}
 		unknown = append(unknown, inst)
 	}
 	return unknown, nil
 }
 
+// runningInstancer is implemented by brokers that can tell instances
+// that are powered off apart from running ones, such as the MAAS
+// provider.
+type runningInstancer interface {
+	AllRunningInstances() ([]instance.Instance, error)
+}
+
+// withoutPoweredOffInstances returns the given unknown instances less
+// any that the broker does not report as running. A powered off
+// instance is not live, and may be started again by whoever owns it,
+// so it is not harvested.
+func (task *provisionerTask) withoutPoweredOffInstances(unknown []instance.Instance) []instance.Instance {
+	ri, ok := task.broker.(runningInstancer)
+	if !ok || len(unknown) == 0 {
+		return unknown
+	}
+	instances, err := ri.AllRunningInstances()
+	if err != nil {
+		// Without knowing which instances are running, none of the
+		// unknown instances can be safely stopped this time.
+		logger.Warningf("not harvesting unknown instances: cannot get running instances: %v", err)
+		return nil
+	}
+	running := make(map[instance.Id]bool)
+	for _, inst := range instances {
+		running[inst.Id()] = true
+	}
+	var result []instance.Instance
+	for _, inst := range unknown {
+		if !running[inst.Id()] {
+			logger.Debugf("instance %v is not running; not harvesting it", inst.Id())
+			continue
+		}
+		result = append(result, inst)
+	}
+	return result
+}
+
+// controllerInstancer is implemented by brokers that can identify the
+// instances running controllers, such as environs.Environ.
+type controllerInstancer interface {
+	ControllerInstances(controllerUUID string) ([]instance.Id, error)
+}
+
+// withoutControllerInstances returns the given unknown instances less
+// any that the broker reports as controller instances. Stopping a
+// controller instance destroys the models it hosts, so they are never
+// harvested as unknown.
+func (task *provisionerTask) withoutControllerInstances(unknown []instance.Instance) []instance.Instance {
+	ci, ok := task.broker.(controllerInstancer)
+	if !ok || len(unknown) == 0 {
+		return unknown
+	}
+	ids, err := ci.ControllerInstances(task.controllerUUID)
+	switch errors.Cause(err) {
+	case nil:
+	case environs.ErrNoInstances, environs.ErrNotBootstrapped:
+		return unknown
+	default:
+		// Without knowing which instances are controllers, none of
+		// the unknown instances can be safely stopped this time.
+		logger.Warningf("not harvesting unknown instances: cannot get controller instances: %v", err)
+		return nil
+	}
+	controllers := make(map[instance.Id]bool)
+	for _, id := range ids {
+		controllers[id] = true
+	}
+	var result []instance.Instance
+	for _, inst := range unknown {
+		if controllers[inst.Id()] {
+			logger.Warningf("not harvesting unknown controller instance %v", inst.Id())
+			continue
+		}
+		result = append(result, inst)
+	}
+	return result
+}
+
 // instancesForDeadMachines returns a list of instance.Instance that represent
 // the list of dead machines running in the provider. Missing machines are
 // omitted from the list.
@@ -535,20 +801,24 @@ func (task *provisionerTask) constructInstanceConfig(
 		return nil, errors.Annotate(err, "failed to setup authentication")
 	}
 
-	// Generated a nonce for the new instance, with the format: "machine-#:UUID".
-	// The first part is a badge, specifying the tag of the machine the provisioner
-	// is running on, while the second part is a random UUID.
-	uuid, err := utils.NewUUID()
+	nonce, err := task.generateNonce(task.machineTag)
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to generate a nonce for machine "+machine.Id())
 	}
+	if err := validateNonce(nonce); err != nil {
+		return nil, errors.Annotate(err, "failed to generate a nonce for machine "+machine.Id())
+	}
 
-	nonce := fmt.Sprintf("%s:%s", task.machineTag, uuid)
+	// An application hosted on the machine may select its own image stream.
+	imageStream := task.imageStream
+	if pInfo.ImageStream != "" {
+		imageStream = pInfo.ImageStream
+	}
 	instanceConfig, err := instancecfg.NewInstanceConfig(
 		names.NewControllerTag(controller.Config(pInfo.ControllerConfig).ControllerUUID()),
 		machine.Id(),
 		nonce,
-		task.imageStream,
+		imageStream,
 		pInfo.Series,
 		apiInfo,
 	)
@@ -700,16 +970,27 @@ func (task *provisionerTask) constructStartInstanceParams(
 	return startInstanceParams, nil
 }
 
+// maintainMachines asks the broker to maintain each of the given
+// machines. A failure to maintain one machine does not stop the others
+// being maintained; failed machines have their instance status set to
+// ProvisioningError, and an error naming them all is returned at the end.
 func (task *provisionerTask) maintainMachines(machines []*apiprovisioner.Machine) error {
+	var failed []string
 	for _, m := range machines {
 		logger.Infof("maintainMachines: %v", m)
 		startInstanceParams := environs.StartInstanceParams{}
 		startInstanceParams.InstanceConfig = &instancecfg.InstanceConfig{}
 		startInstanceParams.InstanceConfig.MachineId = m.Id()
 		if err := task.broker.MaintainInstance(startInstanceParams); err != nil {
-			return errors.Annotatef(err, "cannot maintain machine %v", m)
+			failed = append(failed, fmt.Sprintf("%v (%v)", m, err))
+			if err := task.setErrorStatus("cannot maintain machine %v: %v", m, err); err != nil {
+				logger.Errorf("%v", err)
+			}
 		}
 	}
+	if len(failed) > 0 {
+		return errors.Errorf("cannot maintain machines: %s", strings.Join(failed, ", "))
+	}
 	return nil
 }
 
@@ -724,6 +1005,16 @@ type AvailabilityZoneMachine struct {
 	MachineIds         set.Strings
 	FailedMachineIds   set.Strings
 	ExcludedMachineIds set.Strings // Don't use these machines in the zone.
+	Capacity           float64     // Relative capacity of the zone; 0 if unknown.
+}
+
+// weight returns the zone's relative capacity, or 1 if it is unknown so
+// that zones without capacities are weighted equally.
+func (azm *AvailabilityZoneMachine) weight() float64 {
+	if azm.Capacity <= 0 {
+		return 1
+	}
+	return azm.Capacity
 }
 
 // populateAvailabilityZoneMachines fills in the map, availabilityZoneMachines,
@@ -751,6 +1042,12 @@ func (task *provisionerTask) populateAvailabilityZoneMachines() error {
 		return err
 	}
 
+	zoneNames := make([]string, len(availabilityZoneInstances))
+	for i, instances := range availabilityZoneInstances {
+		zoneNames[i] = instances.ZoneName
+	}
+	capacities := availabilityZoneCapacities(zonedEnv, zoneNames)
+
 	instanceMachines := make(map[instance.Id]string)
 	for _, machine := range task.machines {
 		instId, err := machine.InstanceId()
@@ -775,41 +1072,127 @@ func (task *provisionerTask) populateAvailabilityZoneMachines() error {
 			MachineIds:         machineIds,
 			FailedMachineIds:   set.NewStrings(),
 			ExcludedMachineIds: set.NewStrings(),
+			Capacity:           capacities[instances.ZoneName],
 		}
 	}
 	return nil
 }
 
+// availabilityZoneCapacities returns the relative capacities of the named
+// zones if the environ reports them. If it does not, or any of the zones
+// has no positive capacity, nil is returned and all zones are weighted
+// equally.
+func availabilityZoneCapacities(env providercommon.ZonedEnviron, zoneNames []string) map[string]float64 {
+	capacityEnv, ok := env.(providercommon.ZoneCapacityEnviron)
+	if !ok {
+		return nil
+	}
+	capacities, err := capacityEnv.AvailabilityZoneCapacities()
+	if err != nil {
+		logger.Warningf("cannot get availability zone capacities, weighting zones equally: %v", err)
+		return nil
+	}
+	for _, name := range zoneNames {
+		if capacities[name] <= 0 {
+			logger.Debugf("no capacity for availability zone %q, weighting zones equally", name)
+			return nil
+		}
+	}
+	return capacities
+}
+
 // populateDistributionGroupZoneMap returns a zone mapping which only includes
 // machines in the same distribution group.  This is used to determine where new
 // machines in that distribution group should be placed.
-func (task *provisionerTask) populateDistributionGroupZoneMap(machineIds []string) []*AvailabilityZoneMachine {
+func populateDistributionGroupZoneMap(azMachines []*AvailabilityZoneMachine, machineIds []string) []*AvailabilityZoneMachine {
 	var dgAvailabilityZoneMachines []*AvailabilityZoneMachine
 	dgSet := set.NewStrings(machineIds...)
-	for _, azm := range task.availabilityZoneMachines {
+	for _, azm := range azMachines {
 		dgAvailabilityZoneMachines = append(dgAvailabilityZoneMachines, &AvailabilityZoneMachine{
 			azm.ZoneName,
 			azm.MachineIds.Intersection(dgSet),
 			azm.FailedMachineIds,
 			azm.ExcludedMachineIds,
+			azm.Capacity,
 		})
 	}
 	return dgAvailabilityZoneMachines
 }
 
+// copyAvailabilityZoneMachines returns a deep copy of azMachines.
+func copyAvailabilityZoneMachines(azMachines []*AvailabilityZoneMachine) []*AvailabilityZoneMachine {
+	result := make([]*AvailabilityZoneMachine, len(azMachines))
+	for i, azm := range azMachines {
+		result[i] = &AvailabilityZoneMachine{
+			ZoneName:           azm.ZoneName,
+			MachineIds:         set.NewStrings(azm.MachineIds.Values()...),
+			FailedMachineIds:   set.NewStrings(azm.FailedMachineIds.Values()...),
+			ExcludedMachineIds: set.NewStrings(azm.ExcludedMachineIds.Values()...),
+			Capacity:           azm.Capacity,
+		}
+	}
+	return result
+}
+
 // machineAvailabilityZoneDistribution returns a suggested availability zone
-// for the specified machine to start in.  If the current provider does not
-// implement availability zones, "" and no error will be returned. Machines are
-// spread across availability zones based on lowest population of the "available" zones.
-// Machines in the same DistributionGroup are placed in different zones, spread
-// across availability zones based on lowest population of machines in that
-// DistributionGroup.  Machines are not placed in a zone they are excluded from.
-// If availability zones are implemented and one isn't found, return NotFound error.
+// for the specified machine to start in, and records the machine in that
+// zone. See distributeMachine.
 func (task *provisionerTask) machineAvailabilityZoneDistribution(machineId string, distributionGroupMachineIds []string) (string, error) {
 	task.azMachinesMutex.Lock()
 	defer task.azMachinesMutex.Unlock()
+	return distributeMachine(task.availabilityZoneMachines, machineId, distributionGroupMachineIds)
+}
 
-	if len(task.availabilityZoneMachines) == 0 {
+// PlanDistribution is part of the ProvisionerTask interface.
+func (task *provisionerTask) PlanDistribution(machineIds []string) (map[string]string, error) {
+	machineTags := make([]names.MachineTag, len(machineIds))
+	for i, id := range machineIds {
+		if !names.IsValidMachine(id) {
+			return nil, errors.NotValidf("machine id %q", id)
+		}
+		machineTags[i] = names.NewMachineTag(id)
+	}
+	var machineDistributionGroups []apiprovisioner.DistributionGroupResult
+	if len(machineTags) > 0 {
+		var err error
+		machineDistributionGroups, err = task.distributionGroupFinder.DistributionGroupByMachineId(machineTags...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	// Distribute the machines over a copy of the zones, so that
+	// nothing is recorded against the real ones.
+	task.azMachinesMutex.RLock()
+	azMachines := copyAvailabilityZoneMachines(task.availabilityZoneMachines)
+	task.azMachinesMutex.RUnlock()
+
+	plan := make(map[string]string)
+	for i, id := range machineIds {
+		if err := machineDistributionGroups[i].Err; err != nil {
+			return nil, errors.Annotatef(err, "fetching distribution groups for machine %q", id)
+		}
+		zone, err := distributeMachine(azMachines, id, machineDistributionGroups[i].MachineIds)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		plan[id] = zone
+	}
+	return plan, nil
+}
+
+// distributeMachine returns a suggested availability zone from azMachines
+// for the specified machine to start in, and records the machine there.
+// If the current provider does not implement availability zones, "" and no
+// error will be returned. Machines are spread across availability zones
+// based on lowest population of the "available" zones, relative to each
+// zone's capacity if the provider reports capacities. Machines in the same
+// DistributionGroup are placed in different zones, spread across
+// availability zones based on lowest population of machines in that
+// DistributionGroup.  Machines are not placed in a zone they are excluded from.
+// If availability zones are implemented and one isn't found, return NotFound error.
+func distributeMachine(azMachines []*AvailabilityZoneMachine, machineId string, distributionGroupMachineIds []string) (string, error) {
+	if len(azMachines) == 0 {
 		return "", nil
 	}
 
@@ -818,14 +1201,14 @@ func (task *provisionerTask) machineAvailabilityZoneDistribution(machineId strin
 	// if the machine has a distribution group, assign based on lowest
 	// az population of the distribution group machine.
 	if len(distributionGroupMachineIds) > 0 {
-		dgZoneMap := task.populateDistributionGroupZoneMap(distributionGroupMachineIds)
+		dgZoneMap := populateDistributionGroupZoneMap(azMachines, distributionGroupMachineIds)
 		sort.Sort(byPopulationThenNames(dgZoneMap))
 
 		for _, dgZoneMachines := range dgZoneMap {
 			if !dgZoneMachines.FailedMachineIds.Contains(machineId) &&
 				!dgZoneMachines.ExcludedMachineIds.Contains(machineId) {
 				machineZone = dgZoneMachines.ZoneName
-				for _, azm := range task.availabilityZoneMachines {
+				for _, azm := range azMachines {
 					if azm.ZoneName == dgZoneMachines.ZoneName {
 						azm.MachineIds.Add(machineId)
 						break
@@ -835,8 +1218,8 @@ func (task *provisionerTask) machineAvailabilityZoneDistribution(machineId strin
 			}
 		}
 	} else {
-		sort.Sort(byPopulationThenNames(task.availabilityZoneMachines))
-		for _, zoneMachines := range task.availabilityZoneMachines {
+		sort.Sort(byPopulationThenNames(azMachines))
+		for _, zoneMachines := range azMachines {
 			if !zoneMachines.FailedMachineIds.Contains(machineId) &&
 				!zoneMachines.ExcludedMachineIds.Contains(machineId) {
 				machineZone = zoneMachines.ZoneName
@@ -858,10 +1241,14 @@ func (b byPopulationThenNames) Len() int {
 }
 
 func (b byPopulationThenNames) Less(i, j int) bool {
+	// Compare populations relative to zone capacity, i.e.
+	// size(i)/weight(i) against size(j)/weight(j).
+	popI := float64(b[i].MachineIds.Size()) * b[j].weight()
+	popJ := float64(b[j].MachineIds.Size()) * b[i].weight()
 	switch {
-	case b[i].MachineIds.Size() < b[j].MachineIds.Size():
+	case popI < popJ:
 		return true
-	case b[i].MachineIds.Size() == b[j].MachineIds.Size():
+	case popI == popJ:
 		return b[i].ZoneName < b[j].ZoneName
 	}
 	return false
@@ -986,18 +1373,18 @@ func (task *provisionerTask) setupToStartMachine(machine *apiprovisioner.Machine
 
 // populateExcludedMachines, translates the results of DeriveAvailabilityZones
 // into availabilityZoneMachines.ExcludedMachineIds for machines not to be used
-// in the given zone.
-func (task *provisionerTask) populateExcludedMachines(machineId string, startInstanceParams environs.StartInstanceParams) error {
+// in the given zone. It returns the derived zones.
+func (task *provisionerTask) populateExcludedMachines(machineId string, startInstanceParams environs.StartInstanceParams) ([]string, error) {
 	zonedEnv, ok := task.broker.(providercommon.ZonedEnviron)
 	if !ok {
-		return nil
+		return nil, nil
 	}
 	derivedZones, err := zonedEnv.DeriveAvailabilityZones(startInstanceParams)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	if len(derivedZones) == 0 {
-		return nil
+		return nil, nil
 	}
 	task.azMachinesMutex.Lock()
 	defer task.azMachinesMutex.Unlock()
@@ -1007,6 +1394,27 @@ func (task *provisionerTask) populateExcludedMachines(machineId string, startIns
 			zoneMachines.ExcludedMachineIds.Add(machineId)
 		}
 	}
+	return derivedZones, nil
+}
+
+// waitForRetryBudget blocks until the retry budget shared by all
+// machines allows another attempt to start the given machine, or the
+// task is dying. Waiting does not use up any of the machine's own
+// attempts.
+func (task *provisionerTask) waitForRetryBudget(machine *apiprovisioner.Machine) error {
+	if task.retryBudget == nil {
+		return nil
+	}
+	wait := task.retryBudget.Take(1)
+	if wait <= 0 {
+		return nil
+	}
+	logger.Debugf("retry budget exhausted, waiting %v to retry machine %s", wait, machine)
+	select {
+	case <-task.catacomb.Dying():
+		return task.catacomb.ErrDying()
+	case <-time.After(wait):
+	}
 	return nil
 }
 
@@ -1026,10 +1434,19 @@ func (task *provisionerTask) startMachine(
 	// Figure out if the zones available to use for a new instance are
 	// restricted based on placement, and if so exclude those machines
 	// from being started in any other zone.
-	if err := task.populateExcludedMachines(machine.Id(), startInstanceParams); err != nil {
+	derivedZones, err := task.populateExcludedMachines(machine.Id(), startInstanceParams)
+	if err != nil {
 		return err
 	}
 
+	// A machine whose placement allows only one zone is pinned to it:
+	// it is only ever started in that zone, however many attempts fail
+	// there.
+	pinnedZone, err := task.pinMachineToAvailabilityZone(machine.Id(), derivedZones)
+	if err != nil {
+		return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
+	}
+
 	// TODO (jam): 2017-01-19 Should we be setting this earlier in the cycle?
 	if err := machine.SetInstanceStatus(status.Provisioning, "starting", nil); err != nil {
 		logger.Errorf("%v", err)
@@ -1040,15 +1457,24 @@ func (task *provisionerTask) startMachine(
 	// Is rate limiting handled correctly?
 	var result *environs.StartInstanceResult
 
+	// Let the broker abandon an in-flight StartInstance if we're killed.
+	startInstanceParams.Abort = task.catacomb.Dying()
+
+	firstAttempt := task.recordFirstAttempt(machine)
+
 	// Attempt creating the instance "retryCount" times. If the provider
 	// supports availability zones and we're automatically distributing
 	// across the zones, then we try each zone for every attempt, or until
 	// one of the StartInstance calls returns an error satisfying
 	// environs.IsAvailabilityZoneIndependent.
 	for attemptsLeft := task.retryStartInstanceStrategy.retryCount; attemptsLeft >= 0; {
-		startInstanceParams.AvailabilityZone, err = task.machineAvailabilityZoneDistribution(machine.Id(), distributionGroupMachineIds)
-		if err != nil {
-			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
+		if pinnedZone != "" {
+			startInstanceParams.AvailabilityZone = pinnedZone
+		} else {
+			startInstanceParams.AvailabilityZone, err = task.machineAvailabilityZoneDistribution(machine.Id(), distributionGroupMachineIds)
+			if err != nil {
+				return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
+			}
 		}
 		if startInstanceParams.AvailabilityZone != "" {
 			logger.Infof("trying machine %s StartInstance in availability zone %s", machine, startInstanceParams.AvailabilityZone)
@@ -1058,17 +1484,45 @@ func (task *provisionerTask) startMachine(
 		if err == nil {
 			result = attemptResult
 			break
-		} else if attemptsLeft <= 0 {
+		}
+		select {
+		case <-task.catacomb.Dying():
+			// The failure may be due to the start being aborted;
+			// don't record it against the machine.
+			return task.catacomb.ErrDying()
+		default:
+		}
+		if attemptsLeft <= 0 {
 			// Set the state to error, so the machine will be skipped
 			// next time until the error is resolved.
 			task.removeMachineFromAZMap(machine)
+			if pinnedZone != "" {
+				err = errors.Errorf(
+					"giving up after %d attempts in pinned availability zone %q: %v",
+					task.retryStartInstanceStrategy.retryCount+1, pinnedZone, err,
+				)
+			}
 			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
 		}
+		if !environs.IsRetryable(err) {
+			// Retrying won't help, so don't use up the attempts.
+			task.removeMachineFromAZMap(machine)
+			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
+		}
+		if timeout := task.retryStartInstanceStrategy.timeout; timeout > 0 && time.Since(firstAttempt) > timeout {
+			// The machine has been failing for too long; stop
+			// retrying until provisioning is retried by hand.
+			task.removeMachineFromAZMap(machine)
+			return task.setErrorStatus(
+				"cannot start instance for machine %q: %v",
+				machine, errors.Errorf("giving up after %v: %v", timeout, err),
+			)
+		}
 
 		retrying := true
 		retryMsg := ""
-		if startInstanceParams.AvailabilityZone != "" && !environs.IsAvailabilityZoneIndependent(err) {
-			// We've specified a zone, and the error may be specific to
+		if pinnedZone == "" && startInstanceParams.AvailabilityZone != "" && !environs.IsAvailabilityZoneIndependent(err) {
+			// We've chosen a zone, and the error may be specific to
 			// that zone. Retry in another zone if there are any untried.
 			azRemaining, err2 := task.markMachineFailedInAZ(machine, startInstanceParams.AvailabilityZone)
 			if err2 != nil {
@@ -1111,6 +1565,9 @@ func (task *provisionerTask) startMachine(
 			return task.catacomb.ErrDying()
 		case <-time.After(task.retryStartInstanceStrategy.retryDelay):
 		}
+		if err := task.waitForRetryBudget(machine); err != nil {
+			return err
+		}
 	}
 
 	networkConfig := networkingcommon.NetworkConfigFromInterfaceInfo(result.NetworkInfo)
@@ -1135,6 +1592,7 @@ func (task *provisionerTask) startMachine(
 		return errors.Annotate(err, "cannot set instance info")
 	}
 
+	task.clearFirstAttempt(machine)
 	logger.Infof(
 		"started machine %s as instance %s with hardware %q, network config %+v, volumes %v, volume attachments %v, subnets to zones %v",
 		machine,
@@ -1148,6 +1606,27 @@ func (task *provisionerTask) startMachine(
 	return nil
 }
 
+// recordFirstAttempt records the current time as the first attempt to
+// start the machine, unless an earlier attempt has already been
+// recorded, and returns the time of the first attempt.
+func (task *provisionerTask) recordFirstAttempt(machine *apiprovisioner.Machine) time.Time {
+	task.firstAttemptsMutex.Lock()
+	defer task.firstAttemptsMutex.Unlock()
+	first, ok := task.firstAttempts[machine.Id()]
+	if !ok {
+		first = time.Now()
+		task.firstAttempts[machine.Id()] = first
+	}
+	return first
+}
+
+// clearFirstAttempt forgets when the machine was first attempted.
+func (task *provisionerTask) clearFirstAttempt(machine *apiprovisioner.Machine) {
+	task.firstAttemptsMutex.Lock()
+	defer task.firstAttemptsMutex.Unlock()
+	delete(task.firstAttempts, machine.Id())
+}
+
 // markMachineFailedInAZ moves the machine in zone from MachineIds to FailedMachineIds
 // in availabilityZoneMachines, report if there are any availability zones not failed for
 // the specified machine.
@@ -1174,6 +1653,32 @@ func (task *provisionerTask) markMachineFailedInAZ(machine *apiprovisioner.Machi
 	return azRemaining, nil
 }
 
+// pinMachineToAvailabilityZone records a machine whose placement allows
+// exactly one availability zone, as derived by the provider, as being
+// started in that zone, and returns the zone's name. It returns "" if
+// the placement allows any other number of zones, or if the provider
+// does not implement availability zones, in which case the machine's
+// zone is chosen by machineAvailabilityZoneDistribution.
+func (task *provisionerTask) pinMachineToAvailabilityZone(machineId string, derivedZones []string) (string, error) {
+	if len(derivedZones) != 1 {
+		return "", nil
+	}
+	zoneName := derivedZones[0]
+
+	task.azMachinesMutex.Lock()
+	defer task.azMachinesMutex.Unlock()
+	if len(task.availabilityZoneMachines) == 0 {
+		return "", nil
+	}
+	for _, zoneMachines := range task.availabilityZoneMachines {
+		if zoneMachines.ZoneName == zoneName {
+			zoneMachines.MachineIds.Add(machineId)
+			return zoneName, nil
+		}
+	}
+	return "", errors.NotFoundf("availability zone %q", zoneName)
+}
+
 func (task *provisionerTask) clearMachineAZFailures(machine *apiprovisioner.Machine) {
 	task.azMachinesMutex.Lock()
 	defer task.azMachinesMutex.Unlock()
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"github.com/juju/utils/arch"
@@ -27,6 +28,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/controller/authentication"
+	coreapplication "github.com/juju/juju/core/application"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/filestorage"
@@ -46,6 +48,7 @@ import (
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/poolmanager"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 	coretools "github.com/juju/juju/tools"
 	jujuversion "github.com/juju/juju/version"
 	"github.com/juju/juju/worker/provisioner"
@@ -999,6 +1002,104 @@ func (s *MachineClassifySuite) TestMachineClassification(c *gc.C) {
 	}
 }
 
+// recordingBroker records the parameters passed to StartInstance.
+type recordingBroker struct {
+	environs.Environ
+	started chan environs.StartInstanceParams
+}
+
+func (b *recordingBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	b.started <- args
+	return b.Environ.StartInstance(args)
+}
+
+// waitStartInstance returns the parameters of the next instance started
+// through the broker.
+func (s *ProvisionerSuite) waitStartInstance(c *gc.C, broker *recordingBroker) environs.StartInstanceParams {
+	s.BackingState.StartSync()
+	select {
+	case args := <-broker.started:
+		return args
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("instance not started")
+	}
+	// Satisfy Go, Fatal should be a panic anyway
+	return environs.StartInstanceParams{}
+}
+
+func (s *ProvisionerSuite) TestProvisioningMachinesWithEndpointBindings(c *gc.C) {
+	broker := &recordingBroker{
+		Environ: s.Environ,
+		started: make(chan environs.StartInstanceParams, 1),
+	}
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	_, err := s.State.AddSpace("space1", "first-space-id", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("space2", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Deploy with bindings, as 'juju deploy --bind' does.
+	wordpress := s.AddTestingApplicationWithBindings(c, "wordpress", s.AddTestingCharm(c, "wordpress"), map[string]string{
+		"url": "space1",
+		"db":  "space2",
+	})
+	unit, err := wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Space names are translated to provider ids where known.
+	args := s.waitStartInstance(c, broker)
+	c.Assert(args.EndpointBindings, jc.DeepEquals, map[string]network.Id{
+		"url": "first-space-id",
+		"db":  "space2",
+	})
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitInstanceIdNoAssert(c, m)
+}
+
+func (s *ProvisionerSuite) TestProvisioningMachinesWithApplicationImageStream(c *gc.C) {
+	broker := &recordingBroker{
+		Environ: s.Environ,
+		started: make(chan environs.StartInstanceParams, 1),
+	}
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	ch := s.AddTestingCharm(c, "dummy")
+	daily := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:                    "daily",
+		Charm:                   ch,
+		ApplicationConfig:       map[string]interface{}{coreapplication.ImageStreamKey: "daily"},
+		ApplicationConfigFields: coreapplication.IAASConfigSchema(),
+	})
+	plain := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:  "plain",
+		Charm: ch,
+	})
+
+	// The application's image stream overrides the model's.
+	unit, err := daily.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	args := s.waitStartInstance(c, broker)
+	c.Assert(args.InstanceConfig.ImageStream, gc.Equals, "daily")
+
+	// Other applications use the model's image stream.
+	unit, err = plain.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	args = s.waitStartInstance(c, broker)
+	c.Assert(args.InstanceConfig.ImageStream, gc.Equals, imagemetadata.ReleasedStream)
+}
+
 func (s *ProvisionerSuite) TestProvisioningMachinesWithSpacesSuccess(c *gc.C) {
 	p := s.newEnvironProvisioner(c)
 	defer workertest.CleanKill(c, p)
@@ -1333,6 +1434,20 @@ func (s *ProvisionerSuite) newProvisionerTaskWithRetryStrategy(
 	toolsFinder provisioner.ToolsFinder,
 	retryStrategy provisioner.RetryStrategy,
 ) provisioner.ProvisionerTask {
+	return s.newProvisionerTaskWithNonceGenerator(c, harvestingMethod, broker,
+		machineGetter, distributionGroupFinder, toolsFinder, retryStrategy, nil)
+}
+
+func (s *ProvisionerSuite) newProvisionerTaskWithNonceGenerator(
+	c *gc.C,
+	harvestingMethod config.HarvestMode,
+	broker environs.InstanceBroker,
+	machineGetter provisioner.MachineGetter,
+	distributionGroupFinder provisioner.DistributionGroupFinder,
+	toolsFinder provisioner.ToolsFinder,
+	retryStrategy provisioner.RetryStrategy,
+	generateNonce provisioner.NonceGenerator,
+) provisioner.ProvisionerTask {
 
 	machineWatcher, err := s.provisioner.WatchModelMachines()
 	c.Assert(err, jc.ErrorIsNil)
@@ -1354,11 +1469,71 @@ func (s *ProvisionerSuite) newProvisionerTaskWithRetryStrategy(
 		auth,
 		imagemetadata.ReleasedStream,
 		retryStrategy,
+		provisioner.ProvisionerTaskOptions{GenerateNonce: generateNonce},
 	)
 	c.Assert(err, jc.ErrorIsNil)
 	return w
 }
 
+func (s *ProvisionerSuite) TestProvisionerUsesNonceGenerator(c *gc.C) {
+	provisionerTags := make(chan names.MachineTag, 1)
+	generateNonce := func(tag names.MachineTag) (string, error) {
+		provisionerTags <- tag
+		return "custom-nonce", nil
+	}
+	task := s.newProvisionerTaskWithNonceGenerator(c, config.HarvestDestroyed,
+		s.Environ, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{},
+		provisioner.NewRetryStrategy(0*time.Second, 0), generateNonce)
+	defer workertest.CleanKill(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitInstanceIdNoAssert(c, m)
+	c.Assert(<-provisionerTags, gc.Equals, names.NewMachineTag("0"))
+
+	// The machine agent can present the custom nonce to state.
+	c.Assert(m.Refresh(), jc.ErrorIsNil)
+	c.Assert(m.CheckProvisioned("custom-nonce"), jc.IsTrue)
+	c.Assert(m.CheckProvisioned("custom-nonce-x"), jc.IsFalse)
+}
+
+func (s *ProvisionerSuite) TestProvisionerRejectsMalformedNonce(c *gc.C) {
+	for i, nonce := range []string{"", "bad nonce", "bad\nnonce"} {
+		c.Logf("test %d: %q", i, nonce)
+		generateNonce := func(names.MachineTag) (string, error) {
+			return nonce, nil
+		}
+		task := s.newProvisionerTaskWithNonceGenerator(c, config.HarvestDestroyed,
+			s.Environ, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{},
+			provisioner.NewRetryStrategy(0*time.Second, 0), generateNonce)
+
+		m, err := s.addMachine()
+		c.Assert(err, jc.ErrorIsNil)
+		_, instanceStatus := s.waitUntilMachineNotPending(c, m)
+		c.Check(instanceStatus.Status, gc.Equals, status.ProvisioningError)
+		c.Check(instanceStatus.Message, gc.Matches, ".*failed to generate a nonce for machine .*not valid")
+		_, err = m.InstanceId()
+		c.Check(err, jc.Satisfies, errors.IsNotProvisioned)
+		workertest.CleanKill(c, task)
+	}
+}
+
+func (s *ProvisionerSuite) TestProvisionerNonceGeneratorError(c *gc.C) {
+	generateNonce := func(names.MachineTag) (string, error) {
+		return "", errors.New("no entropy")
+	}
+	task := s.newProvisionerTaskWithNonceGenerator(c, config.HarvestDestroyed,
+		s.Environ, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{},
+		provisioner.NewRetryStrategy(0*time.Second, 0), generateNonce)
+	defer workertest.CleanKill(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	_, instanceStatus := s.waitUntilMachineNotPending(c, m)
+	c.Check(instanceStatus.Status, gc.Equals, status.ProvisioningError)
+	c.Check(instanceStatus.Message, gc.Matches, ".*failed to generate a nonce for machine .*: no entropy")
+}
+
 func (s *ProvisionerSuite) TestHarvestNoneReapsNothing(c *gc.C) {
 
 	task := s.newProvisionerTask(c, config.HarvestDestroyed, s.Environ, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
@@ -1405,6 +1580,261 @@ func (s *ProvisionerSuite) TestHarvestUnknownReapsOnlyUnknown(c *gc.C) {
 	s.waitForRemovalMark(c, m0)
 }
 
+func (s *ProvisionerSuite) TestHarvestUnknownSkipsExcludedInstances(c *gc.C) {
+
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		s.Environ,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	// Create a machine and two unknown instances, one of which is
+	// managed outside of Juju.
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+	i1 := s.startUnknownInstance(c, "998")
+	i2 := s.startUnknownInstance(c, "999")
+
+	task.SetHarvestExclude([]string{string(i2.Id())})
+	task.SetHarvestMode(config.HarvestUnknown)
+
+	// Only the unknown instance that is not excluded is stopped.
+	s.checkStopSomeInstances(c, []instance.Instance{i1}, []instance.Instance{i0, i2})
+}
+
+// namedInstancesEnviron gives names to some of the instances of the
+// wrapped Environ.
+type namedInstancesEnviron struct {
+	environs.Environ
+	mu    sync.Mutex
+	names map[instance.Id]string
+}
+
+func (e *namedInstancesEnviron) setNames(names map[instance.Id]string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.names = names
+}
+
+func (e *namedInstancesEnviron) AllInstances() ([]instance.Instance, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	insts, err := e.Environ.AllInstances()
+	for i, inst := range insts {
+		if name, ok := e.names[inst.Id()]; ok {
+			insts[i] = namedInstance{inst, name}
+		}
+	}
+	return insts, err
+}
+
+type namedInstance struct {
+	instance.Instance
+	name string
+}
+
+func (inst namedInstance) Name() string {
+	return inst.name
+}
+
+func (s *ProvisionerSuite) TestHarvestUnknownSkipsExcludedInstanceNames(c *gc.C) {
+	broker := &namedInstancesEnviron{Environ: s.Environ}
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		broker,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	// Create a machine and two unknown instances, one of which is
+	// named as managed outside of Juju.
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+	i1 := s.startUnknownInstance(c, "998")
+	i2 := s.startUnknownInstance(c, "999")
+	broker.setNames(map[instance.Id]string{
+		i1.Id(): "juju-unknown",
+		i2.Id(): "external-db",
+	})
+
+	// Prefixes match names, not ids.
+	task.SetHarvestExclude([]string{"external-", string(i1.Id())})
+	task.SetHarvestMode(config.HarvestUnknown)
+
+	// Only the unknown instance that is not excluded is stopped.
+	s.checkStopSomeInstances(c, []instance.Instance{i1}, []instance.Instance{i0, i2})
+}
+
+// controllerInstancesEnviron reports the given instances as the
+// controller instances of the wrapped Environ.
+type controllerInstancesEnviron struct {
+	environs.Environ
+	mu  sync.Mutex
+	ids []instance.Id
+}
+
+func (e *controllerInstancesEnviron) setIds(ids []instance.Id) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ids = ids
+}
+
+func (e *controllerInstancesEnviron) ControllerInstances(string) ([]instance.Id, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.ids, nil
+}
+
+func (s *ProvisionerSuite) TestHarvestUnknownSkipsControllerInstances(c *gc.C) {
+	broker := &controllerInstancesEnviron{Environ: s.Environ}
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		broker,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	// Create a machine and two unknown instances, one of which is
+	// reported as a controller instance.
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+	i1 := s.startUnknownInstance(c, "998")
+	i2 := s.startUnknownInstance(c, "999")
+	broker.setIds([]instance.Id{i2.Id()})
+
+	task.SetHarvestMode(config.HarvestUnknown)
+
+	// Only the unknown instance that is not a controller is stopped.
+	s.checkStopSomeInstances(c, []instance.Instance{i1}, []instance.Instance{i0, i2})
+}
+
+// runningInstancesEnviron reports all the instances of the wrapped
+// Environ as running, except those that are powered off.
+type runningInstancesEnviron struct {
+	environs.Environ
+	mu         sync.Mutex
+	poweredOff map[instance.Id]bool
+}
+
+func (e *runningInstancesEnviron) setPoweredOff(poweredOff map[instance.Id]bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.poweredOff = poweredOff
+}
+
+func (e *runningInstancesEnviron) AllRunningInstances() ([]instance.Instance, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	insts, err := e.Environ.AllInstances()
+	if err != nil {
+		return nil, err
+	}
+	var running []instance.Instance
+	for _, inst := range insts {
+		if !e.poweredOff[inst.Id()] {
+			running = append(running, inst)
+		}
+	}
+	return running, nil
+}
+
+func (s *ProvisionerSuite) TestHarvestUnknownSkipsPoweredOffInstances(c *gc.C) {
+	broker := &runningInstancesEnviron{Environ: s.Environ}
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		broker,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	// Create a machine and two unknown instances, one of which is
+	// powered off.
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+	i1 := s.startUnknownInstance(c, "998")
+	i2 := s.startUnknownInstance(c, "999")
+	broker.setPoweredOff(map[instance.Id]bool{i2.Id(): true})
+
+	task.SetHarvestMode(config.HarvestUnknown)
+
+	// Only the unknown instance that is running is stopped.
+	s.checkStopSomeInstances(c, []instance.Instance{i1}, []instance.Instance{i0, i2})
+}
+
+func (s *ProvisionerSuite) TestHarvestAllSkipsExcludedInstances(c *gc.C) {
+
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		s.Environ,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	// Create a machine and two unknown instances, one of which is
+	// managed outside of Juju.
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+	i1 := s.startUnknownInstance(c, "998")
+	i2 := s.startUnknownInstance(c, "999")
+
+	task.SetHarvestExclude([]string{string(i2.Id())})
+	task.SetHarvestMode(config.HarvestAll)
+
+	// Mark the first machine as dead.
+	c.Assert(m0.EnsureDead(), gc.IsNil)
+
+	// Everything but the excluded instance must die.
+	s.checkStopSomeInstances(c, []instance.Instance{i0, i1}, []instance.Instance{i2})
+	s.waitForRemovalMark(c, m0)
+}
+
+func (s *ProvisionerSuite) TestHarvestUnknownWaitsForGracePeriod(c *gc.C) {
+	clock := jujutesting.NewClock(time.Now())
+	s.PatchValue(provisioner.HarvestClock, clock)
+
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		s.Environ,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m0)
+	i1 := s.startUnknownInstance(c, "999")
+
+	// A freshly unknown instance survives the loop that first sees it.
+	task.SetHarvestUnknownGrace(10 * time.Minute)
+	task.SetHarvestMode(config.HarvestUnknown)
+	s.checkNoOperations(c)
+
+	// Once the grace period has passed, the next loop harvests it.
+	clock.Advance(10 * time.Minute)
+	m1, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m1)
+	s.checkStopInstances(c, i1)
+}
+
 func (s *ProvisionerSuite) TestHarvestDestroyedReapsOnlyDestroyed(c *gc.C) {
 
 	task := s.newProvisionerTask(
@@ -1548,6 +1978,208 @@ func (s *ProvisionerSuite) TestProvisionerRetriesTransientErrors(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
 }
 
+func (s *ProvisionerSuite) TestProvisionerGivesUpAfterTimeout(c *gc.C) {
+	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
+	broker := &mockBroker{
+		Environ:    s.Environ,
+		retryCount: make(map[string]int),
+		startInstanceFailureInfo: map[string]mockBrokerFailures{
+			"1": {whenSucceed: 1000, err: errors.New("quota exceeded")},
+		},
+	}
+	retryStrategy := provisioner.NewRetryStrategyWithLimits(5*time.Millisecond, 1000, 50*time.Millisecond, 0, 0)
+	task := s.newProvisionerTaskWithRetryStrategy(c, config.HarvestAll,
+		broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{}, retryStrategy)
+	defer workertest.CleanKill(c, task)
+
+	attempts := func() int {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		return broker.retryCount["1"]
+	}
+
+	// The machine is retried until the timeout expires, long before
+	// the retry count is exhausted.
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	_, instanceStatus := s.waitUntilMachineNotPending(c, m)
+	c.Check(instanceStatus.Status, gc.Equals, status.ProvisioningError)
+	c.Check(instanceStatus.Message, gc.Equals, "giving up after 50ms: quota exceeded")
+	failed := attempts()
+	c.Assert(failed, jc.LessThan, 1000)
+
+	// The machine is no longer retried automatically.
+	time.Sleep(coretesting.ShortWait)
+	c.Assert(attempts(), gc.Equals, failed)
+	_, err = m.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	// Retrying provisioning by hand gives the machine a fresh timeout.
+	now := time.Now()
+	err = m.SetInstanceStatus(status.StatusInfo{
+		Status:  status.ProvisioningError,
+		Message: "info",
+		Data:    map[string]interface{}{"transient": true},
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if attempts() > failed {
+			return
+		}
+	}
+	c.Fatalf("machine was not retried")
+}
+
+func (s *ProvisionerSuite) TestProvisionerSharesRetryBudget(c *gc.C) {
+	broker := &mockBroker{
+		Environ:    s.Environ,
+		retryCount: make(map[string]int),
+		startInstanceFailureInfo: map[string]mockBrokerFailures{
+			"1": {whenSucceed: 1000, err: errors.New("zing")},
+			"2": {whenSucceed: 1000, err: errors.New("zing")},
+			"3": {whenSucceed: 1000, err: errors.New("zing")},
+		},
+	}
+	// A burst of 4 retries is allowed, and the budget is not refilled
+	// within the test.
+	retryStrategy := provisioner.NewRetryStrategyWithLimits(5*time.Millisecond, 1000, 0, time.Hour, 4)
+	task := s.newProvisionerTaskWithRetryStrategy(c, config.HarvestAll,
+		broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{}, retryStrategy)
+	defer workertest.CleanKill(c, task)
+
+	attempts := func() int {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		total := 0
+		for _, count := range broker.retryCount {
+			total += count
+		}
+		return total
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := s.addMachine()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// Each machine makes its first attempt, and then the machines
+	// share the 4 retries between them, however many attempts each
+	// has left.
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if attempts() >= 3+4 {
+			break
+		}
+	}
+	time.Sleep(coretesting.ShortWait)
+	c.Assert(attempts(), gc.Equals, 3+4)
+}
+
+func (s *ProvisionerSuite) TestProvisionerRetriesPinnedMachineInSameZone(c *gc.C) {
+	broker := newMockZoneRecordingBroker(s.Environ, map[string]mockBrokerFailures{
+		"1": {whenSucceed: 2, err: errors.New("zing")},
+	}, map[string][]string{"1": {"zone3"}})
+	retryStrategy := provisioner.NewRetryStrategy(5*time.Millisecond, 2)
+	task := s.newProvisionerTaskWithRetryStrategy(c, config.HarvestDestroyed,
+		broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{}, retryStrategy)
+	defer workertest.CleanKill(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m)
+
+	c.Assert(broker.attemptedZones("1"), jc.DeepEquals, []string{"zone3", "zone3", "zone3"})
+	zone, err := m.AvailabilityZone()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zone, gc.Equals, "zone3")
+}
+
+func (s *ProvisionerSuite) TestProvisionerGivesUpOnPinnedMachine(c *gc.C) {
+	broker := newMockZoneRecordingBroker(s.Environ, map[string]mockBrokerFailures{
+		"1": {whenSucceed: 1000, err: errors.New("zing")},
+	}, map[string][]string{"1": {"zone3"}})
+	retryStrategy := provisioner.NewRetryStrategy(5*time.Millisecond, 2)
+	task := s.newProvisionerTaskWithRetryStrategy(c, config.HarvestDestroyed,
+		broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{}, retryStrategy)
+	defer workertest.CleanKill(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	_, instanceStatus := s.waitUntilMachineNotPending(c, m)
+	c.Check(instanceStatus.Status, gc.Equals, status.ProvisioningError)
+	c.Check(instanceStatus.Message, gc.Equals, `giving up after 3 attempts in pinned availability zone "zone3": zing`)
+	c.Assert(broker.attemptedZones("1"), jc.DeepEquals, []string{"zone3", "zone3", "zone3"})
+}
+
+func (s *ProvisionerSuite) TestProvisionerMovesUnpinnedMachineToNewZone(c *gc.C) {
+	broker := newMockZoneRecordingBroker(s.Environ, map[string]mockBrokerFailures{
+		"1": {whenSucceed: 1, err: errors.New("zing")},
+	}, map[string][]string{"1": {"zone1", "zone3"}})
+	retryStrategy := provisioner.NewRetryStrategy(5*time.Millisecond, 2)
+	task := s.newProvisionerTaskWithRetryStrategy(c, config.HarvestDestroyed,
+		broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{}, retryStrategy)
+	defer workertest.CleanKill(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m)
+
+	zones := broker.attemptedZones("1")
+	c.Assert(zones, gc.HasLen, 2)
+	c.Assert(zones[1], gc.Not(gc.Equals), zones[0])
+	zone, err := m.AvailabilityZone()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zone, gc.Equals, zones[1])
+}
+
+func (s *ProvisionerSuite) TestProvisionerPinnedMachineUnknownZone(c *gc.C) {
+	broker := newMockZoneRecordingBroker(s.Environ, nil, map[string][]string{"1": {"zone2"}})
+	task := s.newProvisionerTask(c, config.HarvestDestroyed,
+		broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	// zone2 exists but is not available.
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	_, instanceStatus := s.waitUntilMachineNotPending(c, m)
+	c.Check(instanceStatus.Status, gc.Equals, status.ProvisioningError)
+	c.Check(instanceStatus.Message, gc.Equals, `availability zone "zone2" not found`)
+	c.Assert(broker.attemptedZones("1"), gc.HasLen, 0)
+}
+
+type nonRetryableError struct {
+	error
+}
+
+func (nonRetryableError) Retryable() bool {
+	return false
+}
+
+func (s *ProvisionerSuite) TestProvisionerDoesNotRetryNonRetryableErrors(c *gc.C) {
+	broker := &mockBroker{
+		Environ:    s.Environ,
+		retryCount: make(map[string]int),
+		startInstanceFailureInfo: map[string]mockBrokerFailures{
+			"1": {whenSucceed: 1000, err: nonRetryableError{errors.New("quota exceeded")}},
+		},
+	}
+	retryStrategy := provisioner.NewRetryStrategy(5*time.Millisecond, 1000)
+	task := s.newProvisionerTaskWithRetryStrategy(c, config.HarvestAll,
+		broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{}, retryStrategy)
+	defer workertest.CleanKill(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	_, instanceStatus := s.waitUntilMachineNotPending(c, m)
+	c.Check(instanceStatus.Status, gc.Equals, status.ProvisioningError)
+	c.Check(instanceStatus.Message, gc.Equals, "quota exceeded")
+
+	time.Sleep(coretesting.ShortWait)
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	c.Assert(broker.retryCount["1"], gc.Equals, 1)
+}
+
 func (s *ProvisionerSuite) TestProvisionerObservesMachineJobs(c *gc.C) {
 	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
 	broker := &mockBroker{Environ: s.Environ, retryCount: make(map[string]int),
@@ -1669,6 +2301,103 @@ func (s *ProvisionerSuite) TestAvailabilityZoneMachinesStartMachines(c *gc.C) {
 	assertAvailabilityZoneMachinesDistribution(c, availabilityZoneMachines)
 }
 
+func (s *ProvisionerSuite) TestAvailabilityZoneMachinesPlanDistribution(c *gc.C) {
+	// Per provider dummy, there will be 3 available availability zones.
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, s.Environ, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	before := provisioner.GetCopyAvailabilityZoneMachines(task)
+	plan, err := task.PlanDistribution([]string{"1", "2", "3"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan, gc.HasLen, 3)
+	zones := set.NewStrings()
+	for _, zone := range plan {
+		zones.Add(zone)
+	}
+	c.Assert(zones.Size(), gc.Equals, 3)
+
+	// Planning must not record anything against the real zones.
+	c.Assert(provisioner.GetCopyAvailabilityZoneMachines(task), jc.DeepEquals, before)
+
+	// Starting the machines one at a time puts each where planned.
+	for _, id := range []string{"1", "2", "3"} {
+		m, err := s.addMachine()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(m.Id(), gc.Equals, id)
+		s.checkStartInstance(c, m)
+		zone, err := m.AvailabilityZone()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(zone, gc.Equals, plan[id])
+	}
+}
+
+func (s *ProvisionerSuite) TestAvailabilityZoneMachinesPlanDistributionWithDG(c *gc.C) {
+	dgFinder := &mockDistributionGroupFinder{groups: map[names.MachineTag][]string{
+		names.NewMachineTag("1"): []string{"2"},
+		names.NewMachineTag("2"): []string{"1"},
+	}}
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, s.Environ, s.provisioner, dgFinder, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	plan, err := task.PlanDistribution([]string{"1", "2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan, gc.HasLen, 2)
+	c.Assert(plan["1"], gc.Not(gc.Equals), plan["2"])
+
+	_, err = task.PlanDistribution([]string{"1", "3"})
+	c.Assert(err, gc.ErrorMatches, `fetching distribution groups for machine "3": Fail`)
+
+	_, err = task.PlanDistribution([]string{"foo"})
+	c.Assert(err, gc.ErrorMatches, `machine id "foo" not valid`)
+}
+
+func (s *ProvisionerSuite) TestAvailabilityZoneMachinesStartMachinesZoneCapacities(c *gc.C) {
+	// Per provider dummy, the available zones are zone1, zone3 and zone4.
+	// zone1 is twice the size of the others, so should attract twice as
+	// many machines.
+	e := &mockCapacityBroker{
+		mockBroker: &mockBroker{Environ: s.Environ, retryCount: make(map[string]int)},
+		capacities: map[string]float64{"zone1": 2, "zone3": 1, "zone4": 1},
+	}
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, e, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	machines, err := s.addMachines(8)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstances(c, machines)
+
+	availabilityZoneMachines := provisioner.GetCopyAvailabilityZoneMachines(task)
+	assertAvailabilityZoneMachines(c, machines, nil, availabilityZoneMachines)
+	counts := make(map[string]int)
+	for _, m := range machines {
+		zone, err := m.AvailabilityZone()
+		c.Assert(err, jc.ErrorIsNil)
+		counts[zone]++
+	}
+	c.Logf("machines per zone: %v", counts)
+	c.Assert(counts["zone1"], jc.GreaterThan, 0)
+	c.Assert(counts["zone1"] >= 2*counts["zone3"], jc.IsTrue)
+	c.Assert(counts["zone1"] >= 2*counts["zone4"], jc.IsTrue)
+}
+
+func (s *ProvisionerSuite) TestAvailabilityZoneMachinesStartMachinesIncompleteZoneCapacities(c *gc.C) {
+	// zone4 has no capacity, so all zones are weighted equally.
+	e := &mockCapacityBroker{
+		mockBroker: &mockBroker{Environ: s.Environ, retryCount: make(map[string]int)},
+		capacities: map[string]float64{"zone1": 10, "zone3": 1},
+	}
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, e, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	machines, err := s.addMachines(4)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstances(c, machines)
+
+	availabilityZoneMachines := provisioner.GetCopyAvailabilityZoneMachines(task)
+	assertAvailabilityZoneMachines(c, machines, nil, availabilityZoneMachines)
+	assertAvailabilityZoneMachinesDistribution(c, availabilityZoneMachines)
+}
+
 func (s *ProvisionerSuite) TestAvailabilityZoneMachinesStartMachinesAZFailures(c *gc.C) {
 	// Per provider dummy, there will be 3 available availability zones.
 	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
@@ -1933,6 +2662,107 @@ func (s *ProvisionerSuite) TestProvisioningMachinesNoZonedEnviron(c *gc.C) {
 	c.Assert(expected, gc.HasLen, 0)
 }
 
+func (s *ProvisionerSuite) TestProvisionerTaskAbortsStartInstanceWhenKilled(c *gc.C) {
+	broker := &mockBlockingBroker{
+		Environ: s.Environ,
+		started: make(chan struct{}),
+		aborted: make(chan struct{}),
+	}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.DirtyKill(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.BackingState.StartSync()
+	select {
+	case <-broker.started:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for StartInstance")
+	}
+
+	// Killing the task aborts the StartInstance call in flight, and
+	// the machine is not marked as failing to provision.
+	workertest.CleanKill(c, task)
+	select {
+	case <-broker.aborted:
+	default:
+		c.Fatalf("StartInstance was not aborted")
+	}
+	statusInfo, err := m.InstanceStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Not(gc.Equals), status.ProvisioningError)
+}
+
+// mockBlockingBroker blocks in StartInstance until the call is aborted.
+type mockBlockingBroker struct {
+	environs.Environ
+	started chan struct{}
+	aborted chan struct{}
+}
+
+func (b *mockBlockingBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	close(b.started)
+	select {
+	case <-args.Abort:
+		close(b.aborted)
+		return nil, errors.New("start instance aborted")
+	case <-time.After(coretesting.LongWait):
+		return nil, errors.New("start instance not aborted")
+	}
+}
+
+func (s *ProvisionerSuite) TestMaintainMachinesContinuesPastFailures(c *gc.C) {
+	broker := &mockMaintainBroker{Environ: s.Environ, fail: set.NewStrings()}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	var machines []*apiprovisioner.Machine
+	var failing *state.Machine
+	for i := 0; i < 3; i++ {
+		m, err := s.addMachine()
+		c.Assert(err, jc.ErrorIsNil)
+		s.checkStartInstance(c, m)
+		result, err := s.provisioner.Machines(m.MachineTag())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(result[0].Err, gc.IsNil)
+		machines = append(machines, result[0].Machine)
+		if i == 1 {
+			failing = m
+			broker.fail.Add(m.Id())
+		}
+	}
+
+	err := provisioner.MaintainMachines(task, machines)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(`cannot maintain machines: %s \(maintenance failed\)`, failing.Id()))
+	c.Assert(broker.maintained, jc.SameContents, []string{machines[0].Id(), machines[2].Id()})
+
+	statusInfo, err := failing.InstanceStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, status.ProvisioningError)
+	c.Assert(statusInfo.Message, gc.Equals, "maintenance failed")
+}
+
+// mockMaintainBroker fails to maintain the machines in fail, and
+// records the ones it maintains successfully.
+type mockMaintainBroker struct {
+	environs.Environ
+
+	mu         sync.Mutex
+	fail       set.Strings
+	maintained []string
+}
+
+func (b *mockMaintainBroker) MaintainInstance(args environs.StartInstanceParams) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := args.InstanceConfig.MachineId
+	if b.fail.Contains(id) {
+		return errors.New("maintenance failed")
+	}
+	b.maintained = append(b.maintained, id)
+	return nil
+}
+
 type mockNoZonedEnvironBroker struct {
 	environs.Environ
 }
@@ -1998,6 +2828,62 @@ func (b *mockBroker) DeriveAvailabilityZones(args environs.StartInstanceParams)
 	return b.Environ.(providercommon.ZonedEnviron).DeriveAvailabilityZones(args)
 }
 
+// mockZoneRecordingBroker is a mockBroker which records the availability
+// zone of each StartInstance attempt, by machine id. It derives the
+// given availability zones from each machine's placement.
+type mockZoneRecordingBroker struct {
+	*mockBroker
+	derivedZones map[string][]string
+
+	zonesMu sync.Mutex
+	zones   map[string][]string
+}
+
+func newMockZoneRecordingBroker(
+	env environs.Environ,
+	failures map[string]mockBrokerFailures,
+	derivedZones map[string][]string,
+) *mockZoneRecordingBroker {
+	return &mockZoneRecordingBroker{
+		mockBroker: &mockBroker{
+			Environ:                  env,
+			retryCount:               make(map[string]int),
+			startInstanceFailureInfo: failures,
+		},
+		derivedZones: derivedZones,
+		zones:        make(map[string][]string),
+	}
+}
+
+func (b *mockZoneRecordingBroker) DeriveAvailabilityZones(args environs.StartInstanceParams) ([]string, error) {
+	return b.derivedZones[args.InstanceConfig.MachineId], nil
+}
+
+func (b *mockZoneRecordingBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	id := args.InstanceConfig.MachineId
+	b.zonesMu.Lock()
+	b.zones[id] = append(b.zones[id], args.AvailabilityZone)
+	b.zonesMu.Unlock()
+	return b.mockBroker.StartInstance(args)
+}
+
+func (b *mockZoneRecordingBroker) attemptedZones(machineId string) []string {
+	b.zonesMu.Lock()
+	defer b.zonesMu.Unlock()
+	return append([]string(nil), b.zones[machineId]...)
+}
+
+// mockCapacityBroker is a mockBroker which reports the relative
+// capacities of its availability zones.
+type mockCapacityBroker struct {
+	*mockBroker
+	capacities map[string]float64
+}
+
+func (b *mockCapacityBroker) AvailabilityZoneCapacities() (map[string]float64, error) {
+	return b.capacities, nil
+}
+
 type mockToolsFinder struct {
 }
 